@@ -96,23 +96,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/system-configs": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "admin"
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
-                        }
-                    }
-                }
-            },
+        "/api/v1/admin/audit-logs": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -130,7 +114,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system_config.CreateSystemConfigRequest"
+                            "$ref": "#/definitions/audit_log.ListAuditLogsRequest"
                         }
                     }
                 ],
@@ -144,22 +128,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/system-configs/{key}": {
+        "/api/v1/admin/audit-logs/export": {
             "get": {
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
                     "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "操作者用户 ID",
+                        "name": "actor_user_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
+                        "description": "动作",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "对象类型",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "对象 ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间（RFC3339）",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（RFC3339）",
+                        "name": "end_time",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
                     }
+                }
+            }
+        },
+        "/api/v1/admin/audit-logs/verify": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -169,8 +201,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/admin/change-requests": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -181,20 +215,13 @@ const docTemplate = `{
                     "admin"
                 ],
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/system_config.UpdateSystemConfigRequest"
+                            "$ref": "#/definitions/change_request.ListChangeRequestsRequest"
                         }
                     }
                 ],
@@ -206,8 +233,13 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/change-requests/{id}/approve": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
@@ -216,30 +248,22 @@ const docTemplate = `{
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "配置键",
-                        "name": "key",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "变更申请 ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
+                            "$ref": "#/definitions/change_request.ReviewChangeRequestRequest"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/admin/user-pay-configs": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -249,7 +273,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/change-requests/{id}/reject": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -261,13 +287,21 @@ const docTemplate = `{
                     "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "变更申请 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user_pay_config.CreateUserPayConfigRequest"
+                            "$ref": "#/definitions/change_request.ReviewChangeRequestRequest"
                         }
                     }
                 ],
@@ -281,18 +315,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/admin/user-pay-configs/{id}": {
+        "/api/v1/admin/dispute-attachments/{id}": {
             "get": {
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "admin"
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "配置ID",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "附件 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -302,12 +337,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
+                            "type": "file"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/admin/disputes": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -318,20 +355,13 @@ const docTemplate = `{
                     "admin"
                 ],
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "配置ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user_pay_config.UpdateUserPayConfigRequest"
+                            "$ref": "#/definitions/arbitration.ListArbitrationsRequest"
                         }
                     }
                 ],
@@ -343,8 +373,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/disputes/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
@@ -353,8 +385,9 @@ const docTemplate = `{
                 ],
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "配置ID",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "争议 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -370,16 +403,38 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/config/public": {
-            "get": {
+        "/api/v1/admin/disputes/{id}/messages": {
+            "post": {
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "config"
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "争议 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "留言内容",
+                        "name": "content",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "附件（图片或文本文件）",
+                        "name": "files",
+                        "in": "formData"
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -391,8 +446,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/dashboard/stats/daily": {
-            "get": {
+        "/api/v1/admin/disputes/{id}/offers": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -400,16 +455,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dashboard"
+                    "admin"
                 ],
-                "summary": "获取每日收支统计",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "查询天数，最大7天",
-                        "name": "days",
-                        "in": "query",
+                        "format": "int64",
+                        "description": "争议 ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arbitration.ProposeOfferRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -422,8 +486,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/dashboard/stats/top-customers": {
-            "get": {
+        "/api/v1/admin/disputes/{id}/rule": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -431,23 +495,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "dashboard"
+                    "admin"
                 ],
-                "summary": "获取Top客户",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "查询天数，最大7天",
-                        "name": "days",
-                        "in": "query",
+                        "format": "int64",
+                        "description": "争议 ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "返回数量，最大10",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/arbitration.RuleDisputeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -460,31 +526,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/health": {
-            "get": {
+        "/api/v1/admin/risk-decisions": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/util.ResponseAny"
+                            "$ref": "#/definitions/risk_control.ListRiskDecisionsRequest"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/merchant/api-keys": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "merchant"
                 ],
                 "responses": {
                     "200": {
@@ -494,7 +556,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/risk-decisions/{id}/review": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -503,16 +567,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "决策 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_key.CreateAPIKeyRequest"
+                            "$ref": "#/definitions/risk_control.ReviewRiskDecisionRequest"
                         }
                     }
                 ],
@@ -526,23 +598,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/api-keys/{id}": {
+        "/api/v1/admin/risk-rules": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
-                ],
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -553,7 +615,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -561,24 +623,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api_key.UpdateAPIKeyRequest"
+                            "$ref": "#/definitions/risk_control.RiskRuleRequest"
                         }
                     }
                 ],
@@ -590,22 +644,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/risk-rules/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "API Key ID",
+                        "description": "规则 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/risk_control.RiskRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -616,21 +684,19 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/merchant/api-keys/{id}/payment-links": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "API Key ID",
+                        "description": "规则 ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -644,35 +710,15 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/admin/roles": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
-                ],
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "创建支付链接请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/link.CreatePaymentLinkRequest"
-                        }
-                    }
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -684,31 +730,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/api-keys/{id}/payment-links/{linkId}": {
-            "delete": {
+        "/api/v1/admin/roles/me": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
-                ],
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "API Key ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Payment Link ID",
-                        "name": "linkId",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -720,27 +748,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/payment": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/admin/system-configs": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
-                ],
-                "parameters": [
-                    {
-                        "description": "支付订单请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/payment.PayOrderRequest"
-                        }
-                    }
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -750,9 +764,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/merchant/payment-links/pay": {
+            },
             "post": {
                 "consumes": [
                     "application/json"
@@ -761,16 +773,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "description": "支付请求",
+                        "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/link.PayByLinkRequest"
+                            "$ref": "#/definitions/system_config.CreateSystemConfigRequest"
                         }
                     }
                 ],
@@ -784,19 +796,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/merchant/payment-links/{token}": {
+        "/api/v1/admin/system-configs/{key}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "merchant"
+                    "admin"
                 ],
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "支付链接 Token",
-                        "name": "token",
+                        "description": "配置键",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
@@ -809,10 +821,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/merchant/payment/order": {
-            "get": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -820,15 +830,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "admin"
                 ],
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "订单号",
-                        "name": "order_no",
-                        "in": "query",
+                        "description": "配置键",
+                        "name": "key",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/system_config.UpdateSystemConfigRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -839,25 +858,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/oauth/callback": {
-            "post": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/oauth.CallbackRequest"
-                        }
+                        "type": "string",
+                        "description": "配置键",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -870,13 +885,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/oauth/login": {
+        "/api/v1/admin/user-pay-configs": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "admin"
                 ],
                 "responses": {
                     "200": {
@@ -886,15 +901,27 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/oauth/logout": {
-            "get": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user_pay_config.CreateUserPayConfigRequest"
+                        }
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -906,13 +933,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/oauth/user-info": {
+        "/api/v1/admin/user-pay-configs/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "oauth"
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "配置ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -922,10 +958,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/order/dispute": {
-            "post": {
+            },
+            "put": {
                 "consumes": [
                     "application/json"
                 ],
@@ -933,16 +967,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "配置ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.CreateDisputeRequest"
+                            "$ref": "#/definitions/user_pay_config.UpdateUserPayConfigRequest"
                         }
                     }
                 ],
@@ -954,27 +995,58 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/order/dispute/close": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "description": "request body",
-                        "name": "request",
-                        "in": "body",
+                        "type": "string",
+                        "description": "配置ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/user-roles": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.CloseDisputeRequest"
+                            "$ref": "#/definitions/role.ListUserRolesRequest"
                         }
                     }
                 ],
@@ -988,7 +1060,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/disputes": {
+        "/api/v1/admin/user-roles/grant": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -997,15 +1069,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
                 "parameters": [
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.ListDisputesRequest"
+                            "$ref": "#/definitions/role.UpdateUserRoleRequest"
                         }
                     }
                 ],
@@ -1019,7 +1092,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/disputes/merchant": {
+        "/api/v1/admin/user-roles/revoke": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1028,15 +1101,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
                 "parameters": [
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.ListDisputesRequest"
+                            "$ref": "#/definitions/role.UpdateUserRoleRequest"
                         }
                     }
                 ],
@@ -1050,7 +1124,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/refund-review": {
+        "/api/v1/admin/users/search": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1059,7 +1133,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
                 "parameters": [
                     {
@@ -1068,7 +1142,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dispute.RefundReviewRequest"
+                            "$ref": "#/definitions/user_manage.SearchUsersRequest"
                         }
                     }
                 ],
@@ -1082,7 +1156,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/order/transactions": {
+        "/api/v1/admin/users/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/users/{id}/adjust": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1091,15 +1193,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/order.TransactionListRequest"
+                            "$ref": "#/definitions/user_manage.AdjustBalanceRequest"
                         }
                     }
                 ],
@@ -1113,7 +1224,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/payment/transfer": {
+        "/api/v1/admin/users/{id}/operations": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -1122,16 +1233,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "admin"
                 ],
                 "parameters": [
                     {
-                        "description": "转账请求",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payment.TransferRequest"
+                            "$ref": "#/definitions/user_manage.ListUserOperationsRequest"
                         }
                     }
                 ],
@@ -1145,8 +1264,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/pay-key": {
-            "put": {
+        "/api/v1/admin/users/{id}/revoke-sessions": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -1154,16 +1273,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.UpdatePayKeyRequest"
+                            "$ref": "#/definitions/user_manage.RevokeSessionsRequest"
                         }
                     }
                 ],
@@ -1177,25 +1304,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/pay/submit.php": {
+        "/api/v1/admin/users/{id}/status": {
             "post": {
                 "consumes": [
-                    "application/x-www-form-urlencoded"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "admin"
                 ],
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "request body",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payment.CreateOrderRequest"
+                            "$ref": "#/definitions/user_manage.UpdateUserStatusRequest"
                         }
                     }
                 ],
@@ -1208,97 +1343,3852 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api_key.CreateAPIKeyRequest": {
-            "type": "object",
-            "required": [
-                "app_homepage_url",
-                "app_name",
-                "notify_url"
-            ],
-            "properties": {
-                "app_description": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "app_homepage_url": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "app_name": {
-                    "type": "string",
-                    "maxLength": 20
-                },
-                "notify_url": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "redirect_uri": {
-                    "type": "string",
-                    "maxLength": 100
-                }
-            }
         },
-        "api_key.UpdateAPIKeyRequest": {
-            "type": "object",
-            "properties": {
-                "app_description": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "app_homepage_url": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "app_name": {
-                    "type": "string",
-                    "maxLength": 20
-                },
-                "notify_url": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "redirect_uri": {
-                    "type": "string",
-                    "maxLength": 100
+        "/api/v1/admin/users/{id}/unlock-pay-key": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "用户 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user_manage.UnlockPayKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
                 }
             }
         },
-        "dispute.CloseDisputeRequest": {
-            "type": "object",
-            "required": [
-                "dispute_id"
-            ],
-            "properties": {
-                "dispute_id": {
-                    "type": "integer"
+        "/api/v1/admin/wash-trade-flags": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/risk_control.ListWashTradeFlagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
                 }
             }
         },
-        "dispute.CreateDisputeRequest": {
-            "type": "object",
-            "required": [
-                "order_id",
-                "reason"
+        "/api/v1/admin/wash-trade-flags/{id}/review": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "标记 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/risk_control.ReviewWashTradeFlagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/config/public": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "config"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/dashboard/stats/daily": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "获取每日收支统计",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "查询天数，最大7天",
+                        "name": "days",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/dashboard/stats/top-customers": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "获取Top客户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "查询天数，最大7天",
+                        "name": "days",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回数量，最大10",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/email": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/email.BindEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/email/settings": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/email.UpdateEmailSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/email/verify": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "email"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/email.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_key.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_key.UpdateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/payment-links": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "创建支付链接请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/link.CreatePaymentLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/payment-links/{linkId}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Payment Link ID",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/secrets": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/secrets/rotate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api_key.RotateSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/api-keys/{id}/secrets/{secretId}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "密钥 ID",
+                        "name": "secretId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/orders/{trade_no}/stream": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "平台订单号",
+                        "name": "trade_no",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.OrderStatusEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "支付订单请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.PayOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/pay": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "description": "支付请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/link.PayByLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment-links/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "支付链接 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/order": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payment/order/stream": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "订单号",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/payment.OrderStatusEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payouts": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payout.CreatePayoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/merchant/payouts/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "merchant"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "批次 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notification/list": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification.ListNotificationsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notification/preferences": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification.UpdatePreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notification/read": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/notification.MarkReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/notification/unread-count": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/callback": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/oauth.CallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/login": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/logout": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth/user-info": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/authorizations": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/authorizations/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "授权 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/authorize": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "parameters": [
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 128,
+                        "minLength": 43,
+                        "type": "string",
+                        "name": "code_challenge",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "code_challenge_method",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "name": "redirect_uri",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "response_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/oauth_server.ApproveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/balance": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/charge": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/oauth_server.ChargeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/token": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "client_secret",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "code",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "code_verifier",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "redirect_uri",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "name": "refresh_token",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/oauth_server.TokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/oauth2/userinfo": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth2"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.CreateDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute/appeal": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.AppealDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute/attachments/{id}": {
+            "get": {
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "附件 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute/close": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.CloseDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute/messages": {
+            "post": {
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "争议 ID",
+                        "name": "dispute_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "留言内容",
+                        "name": "content",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "附件（图片或文本文件）",
+                        "name": "files",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/dispute/offer/respond": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.RespondDisputeOfferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/disputes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dispute.ListDisputesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/disputes/merchant": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dispute.ListDisputesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/refund-review": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dispute.RefundReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/order/transactions": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/order.TransactionListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/recipient": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "收款人用户 ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "收款人用户名",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/request": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment_request.CreatePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/request/approve": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment_request.ApprovePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/request/cancel": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment_request.CancelPaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/request/decline": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment_request.DeclinePaymentRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/requests": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment_request.ListPaymentRequestsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/requests/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款请求 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/scheduled-transfer": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.CreateScheduledTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/scheduled-transfer/cancel": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.ScheduledTransferIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/scheduled-transfer/pause": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.ScheduledTransferIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/scheduled-transfer/resume": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.ScheduledTransferIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/scheduled-transfer/runs": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.ListScheduledTransferRunsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/scheduled-transfers": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scheduled_transfer.ListScheduledTransfersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payment/transfer": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "转账请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/qrcode/order": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "qrcode"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "加密订单号（CreateMerchantOrder 返回的支付链接中的 order_no）",
+                        "name": "order_no",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "图片格式：png、svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "图片边长（像素），64-1024",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "纠错等级：L、M、Q、H",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/qrcode/payment-links/{token}": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "qrcode"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "支付链接 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "图片格式：png、svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "图片边长（像素），64-1024",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "纠错等级：L、M、Q、H",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/qrcode/payment-requests/{token}": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "qrcode"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "收款请求 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "图片格式：png、svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "图片边长（像素），64-1024",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "纠错等级：L、M、Q、H",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/qrcode/receive": {
+            "get": {
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "qrcode"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "图片格式：png、svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "图片边长（像素），64-1024",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "纠错等级：L、M、Q、H",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packet": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/red_packet.CreateRedPacketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packet/claim": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/red_packet.ClaimRedPacketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packet/list": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/red_packet.ListRedPacketsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packet/{token}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "红包 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/red-packet/{token}/claims": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "red_packet"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "红包 Token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/access-tokens": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "access_token"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "access_token"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/access_token.CreateAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/access-tokens/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "access_token"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "访问令牌 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/balance/stream": {
+            "get": {
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.BalanceEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/pay-key": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdatePayKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/totp": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "totp"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/totp/activate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "totp"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/totp.ActivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/totp/disable": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "totp"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/totp.CodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/totp/enroll": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "totp"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/totp/recovery-codes": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "totp"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/totp.CodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/totp/threshold": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "totp"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/totp.UpdateThresholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        },
+        "/pay/submit.php": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "parameters": [
+                    {
+                        "description": "request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payment.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.ResponseAny"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "access_token.CreateAccessTokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_in_days": {
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "api_key.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "app_homepage_url",
+                "app_name",
+                "notify_url"
+            ],
+            "properties": {
+                "app_description": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_homepage_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "notify_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "redirect_uri": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "api_key.RotateSecretRequest": {
+            "type": "object",
+            "properties": {
+                "overlap_hours": {
+                    "type": "integer",
+                    "maximum": 168,
+                    "minimum": 0
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "api_key.UpdateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "app_description": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_homepage_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "app_name": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "notify_url": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "redirect_uri": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "arbitration.ListArbitrationsRequest": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "arbitrating",
+                        "refund",
+                        "partial_refund",
+                        "closed"
+                    ]
+                }
+            }
+        },
+        "arbitration.ProposeOfferRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "arbitration.RuleDisputeRequest": {
+            "type": "object",
+            "required": [
+                "note",
+                "ruling"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "ruling": {
+                    "enum": [
+                        "refund",
+                        "partial_refund",
+                        "deny"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.DisputeRuling"
+                        }
+                    ]
+                }
+            }
+        },
+        "audit_log.ListAuditLogsRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "actor_user_id": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "target_type": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "change_request.ListChangeRequestsRequest": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "expired"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ChangeRequestStatus"
+                        }
+                    ]
+                },
+                "target_type": {
+                    "enum": [
+                        "user_pay_config",
+                        "system_config"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ChangeRequestTarget"
+                        }
+                    ]
+                }
+            }
+        },
+        "change_request.ReviewChangeRequestRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dispute.AppealDisputeRequest": {
+            "type": "object",
+            "required": [
+                "dispute_id",
+                "reason"
+            ],
+            "properties": {
+                "dispute_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "dispute.CloseDisputeRequest": {
+            "type": "object",
+            "required": [
+                "dispute_id"
+            ],
+            "properties": {
+                "dispute_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dispute.CreateDisputeRequest": {
+            "type": "object",
+            "required": [
+                "order_id",
+                "reason"
+            ],
+            "properties": {
+                "order_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dispute.ListDisputesRequest": {
+            "type": "object",
+            "properties": {
+                "dispute_id": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "disputing",
+                        "refund",
+                        "closed",
+                        "arbitrating",
+                        "partial_refund"
+                    ]
+                }
+            }
+        },
+        "dispute.RefundReviewRequest": {
+            "type": "object",
+            "required": [
+                "dispute_id",
+                "status"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "dispute_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "refund",
+                        "closed",
+                        "partial_refund"
+                    ]
+                }
+            }
+        },
+        "dispute.RespondDisputeOfferRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "offer_id"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "accept",
+                        "reject",
+                        "counter"
+                    ]
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "offer_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "email.BindEmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "locale": {
+                    "type": "string",
+                    "enum": [
+                        "zh",
+                        "en"
+                    ]
+                }
+            }
+        },
+        "email.UpdateEmailSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "type": "string",
+                    "enum": [
+                        "zh",
+                        "en"
+                    ]
+                },
+                "opt_out": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "email.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "link.CreatePaymentLinkRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "product_name"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "product_name": {
+                    "type": "string",
+                    "maxLength": 30
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "link.PayByLinkRequest": {
+            "type": "object",
+            "required": [
+                "pay_key",
+                "token"
+            ],
+            "properties": {
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "token": {
+                    "type": "string"
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "model.AdminRole": {
+            "type": "string",
+            "enum": [
+                "superadmin",
+                "finance",
+                "support",
+                "auditor"
+            ],
+            "x-enum-varnames": [
+                "AdminRoleSuperAdmin",
+                "AdminRoleFinance",
+                "AdminRoleSupport",
+                "AdminRoleAuditor"
+            ]
+        },
+        "model.ChangeRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "ChangeRequestStatusPending",
+                "ChangeRequestStatusApproved",
+                "ChangeRequestStatusRejected",
+                "ChangeRequestStatusExpired"
+            ]
+        },
+        "model.ChangeRequestTarget": {
+            "type": "string",
+            "enum": [
+                "user_pay_config",
+                "system_config"
+            ],
+            "x-enum-varnames": [
+                "ChangeRequestTargetUserPayConfig",
+                "ChangeRequestTargetSystemConfig"
+            ]
+        },
+        "model.DisputeRuling": {
+            "type": "string",
+            "enum": [
+                "refund",
+                "partial_refund",
+                "deny"
+            ],
+            "x-enum-varnames": [
+                "DisputeRulingRefund",
+                "DisputeRulingPartialRefund",
+                "DisputeRulingDeny"
+            ]
+        },
+        "model.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "success",
+                "failed",
+                "pending",
+                "expired",
+                "disputing",
+                "refund",
+                "refused",
+                "partial_refund"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusSuccess",
+                "OrderStatusFailed",
+                "OrderStatusPending",
+                "OrderStatusExpired",
+                "OrderStatusDisputing",
+                "OrderStatusRefund",
+                "OrderStatusRefused",
+                "OrderStatusPartialRefund"
+            ]
+        },
+        "model.PayLevel": {
+            "type": "integer",
+            "format": "int32",
+            "enum": [
+                0,
+                1,
+                2,
+                3
+            ],
+            "x-enum-varnames": [
+                "PayLevelFree",
+                "PayLevelBasic",
+                "PayLevelStandard",
+                "PayLevelPremium"
+            ]
+        },
+        "notification.ListNotificationsRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "unread_only": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "notification.MarkReadRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "notification.UpdatePreferenceRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "enabled"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "oauth.CallbackRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "oauth_server.ApproveRequest": {
+            "type": "object",
+            "required": [
+                "approve",
+                "client_id",
+                "code_challenge",
+                "code_challenge_method",
+                "redirect_uri",
+                "response_type",
+                "scope"
+            ],
+            "properties": {
+                "approve": {
+                    "type": "boolean"
+                },
+                "client_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "code_challenge": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 43
+                },
+                "code_challenge_method": {
+                    "type": "string"
+                },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "redirect_uri": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "response_type": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "spend_cap": {
+                    "type": "number"
+                },
+                "state": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "oauth_server.ChargeRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "merchant_order_no",
+                "order_name"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "merchant_order_no": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "order_name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "oauth_server.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "order.TransactionListRequest": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_name": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "payee_username": {
+                    "type": "string"
+                },
+                "payer_username": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "pending",
+                        "failed",
+                        "expired",
+                        "disputing",
+                        "refund",
+                        "refused",
+                        "partial_refund"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "receive",
+                        "payment",
+                        "transfer",
+                        "community",
+                        "online",
+                        "adjustment"
+                    ]
+                }
+            }
+        },
+        "payment.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "order_name"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "merchant_order_no": {
+                    "type": "string"
+                },
+                "order_name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "payment_type": {
+                    "type": "string"
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "payment.OrderStatusEvent": {
+            "type": "object",
+            "properties": {
+                "order_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.OrderStatus"
+                }
+            }
+        },
+        "payment.PayOrderRequest": {
+            "type": "object",
+            "required": [
+                "order_no",
+                "pay_key"
+            ],
+            "properties": {
+                "order_no": {
+                    "type": "string"
+                },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "payment.QueryMerchantOrderResponse": {
+            "type": "object",
+            "properties": {
+                "addtime": {
+                    "type": "string",
+                    "example": "2023-12-08 12:00:00"
+                },
+                "code": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "endtime": {
+                    "type": "string",
+                    "example": "2023-12-08 12:05:00"
+                },
+                "money": {
+                    "type": "string",
+                    "example": "10.00"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "查询订单号成功！"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "商品名称"
+                },
+                "out_trade_no": {
+                    "type": "string",
+                    "example": "M202312080001"
+                },
+                "pid": {
+                    "type": "string",
+                    "example": "1001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "trade_no": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "epay"
+                }
+            }
+        },
+        "payment.RefundMerchantOrderResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "退款成功"
+                }
+            }
+        },
+        "payment.RefundOrderRequest": {
+            "type": "object",
+            "required": [
+                "key",
+                "money",
+                "pid",
+                "trade_no"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "money": {
+                    "type": "number"
+                },
+                "out_trade_no": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "string"
+                },
+                "trade_no": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payment.TransferRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "pay_key",
+                "recipient_id",
+                "recipient_username"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "recipient_id": {
+                    "type": "integer"
+                },
+                "recipient_username": {
+                    "type": "string"
+                },
+                "remark": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "payment_request.ApprovePaymentRequestRequest": {
+            "type": "object",
+            "required": [
+                "pay_key",
+                "token"
+            ],
+            "properties": {
+                "pay_key": {
+                    "type": "string",
+                    "maxLength": 6
+                },
+                "token": {
+                    "type": "string"
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "payment_request.CancelPaymentRequestRequest": {
+            "type": "object",
+            "required": [
+                "token"
             ],
             "properties": {
-                "order_id": {
-                    "type": "integer"
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "payment_request.CreatePaymentRequestRequest": {
+            "type": "object",
+            "required": [
+                "amount"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
                 },
-                "reason": {
+                "memo": {
                     "type": "string",
                     "maxLength": 100
+                },
+                "payer_username": {
+                    "type": "string",
+                    "maxLength": 64
                 }
             }
         },
-        "dispute.ListDisputesRequest": {
+        "payment_request.DeclinePaymentRequestRequest": {
             "type": "object",
+            "required": [
+                "token"
+            ],
             "properties": {
-                "dispute_id": {
-                    "type": "integer"
+                "reason": {
+                    "type": "string",
+                    "maxLength": 100
                 },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "payment_request.ListPaymentRequestsRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
                 "page": {
                     "type": "integer",
                     "minimum": 1
@@ -1308,120 +5198,149 @@ const docTemplate = `{
                     "maximum": 100,
                     "minimum": 1
                 },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "sent",
+                        "received"
+                    ]
+                },
                 "status": {
                     "type": "string",
                     "enum": [
-                        "disputing",
-                        "refund",
-                        "closed"
+                        "pending",
+                        "paid",
+                        "declined",
+                        "canceled",
+                        "expired"
                     ]
                 }
             }
         },
-        "dispute.RefundReviewRequest": {
+        "payout.CreatePayoutRequest": {
             "type": "object",
             "required": [
-                "dispute_id",
-                "status"
+                "idempotency_key",
+                "items"
             ],
             "properties": {
-                "dispute_id": {
-                    "type": "integer"
-                },
-                "reason": {
+                "idempotency_key": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 64
                 },
-                "status": {
-                    "type": "string",
-                    "enum": [
-                        "refund",
-                        "closed"
-                    ]
+                "items": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/payout.PayoutItemRequest"
+                    }
                 }
             }
         },
-        "link.CreatePaymentLinkRequest": {
+        "payout.PayoutItemRequest": {
             "type": "object",
             "required": [
-                "amount",
-                "product_name"
+                "amount"
             ],
             "properties": {
                 "amount": {
                     "type": "number"
                 },
-                "product_name": {
+                "memo": {
                     "type": "string",
-                    "maxLength": 30
+                    "maxLength": 100
                 },
-                "remark": {
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 64
                 }
             }
         },
-        "link.PayByLinkRequest": {
+        "red_packet.ClaimRedPacketRequest": {
             "type": "object",
             "required": [
-                "pay_key",
                 "token"
             ],
             "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "red_packet.CreateRedPacketRequest": {
+            "type": "object",
+            "required": [
+                "pay_key",
+                "shares",
+                "split_type",
+                "total_amount"
+            ],
+            "properties": {
+                "greeting": {
+                    "type": "string",
+                    "maxLength": 100
+                },
                 "pay_key": {
                     "type": "string",
                     "maxLength": 6
                 },
-                "remark": {
+                "shares": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "split_type": {
                     "type": "string",
-                    "maxLength": 100
+                    "enum": [
+                        "equal",
+                        "random"
+                    ]
                 },
-                "token": {
-                    "type": "string"
+                "total_amount": {
+                    "type": "number"
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
                 }
             }
         },
-        "model.PayLevel": {
-            "type": "integer",
-            "format": "int32",
-            "enum": [
-                0,
-                1,
-                2,
-                3
-            ],
-            "x-enum-varnames": [
-                "PayLevelFree",
-                "PayLevelBasic",
-                "PayLevelStandard",
-                "PayLevelPremium"
-            ]
-        },
-        "oauth.CallbackRequest": {
+        "red_packet.ListRedPacketsRequest": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string"
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "state": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "finished",
+                        "expired"
+                    ]
                 }
             }
         },
-        "order.TransactionListRequest": {
+        "risk_control.ListRiskDecisionsRequest": {
             "type": "object",
             "properties": {
-                "client_id": {
-                    "type": "string"
-                },
-                "endTime": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "order_name": {
-                    "type": "string"
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "review",
+                        "block"
+                    ]
                 },
                 "page": {
                     "type": "integer",
@@ -1432,170 +5351,174 @@ const docTemplate = `{
                     "maximum": 100,
                     "minimum": 1
                 },
-                "payee_username": {
-                    "type": "string"
-                },
-                "payer_username": {
-                    "type": "string"
-                },
-                "startTime": {
-                    "type": "string"
-                },
-                "status": {
+                "review_status": {
                     "type": "string",
                     "enum": [
-                        "success",
                         "pending",
-                        "failed",
-                        "expired",
-                        "disputing",
-                        "refund",
-                        "refused"
+                        "reviewed"
                     ]
                 },
-                "type": {
+                "scene": {
                     "type": "string",
                     "enum": [
-                        "receive",
                         "payment",
                         "transfer",
-                        "community",
-                        "online"
+                        "refund",
+                        "payout"
                     ]
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "payment.CreateOrderRequest": {
+        "risk_control.ListWashTradeFlagsRequest": {
             "type": "object",
-            "required": [
-                "amount",
-                "order_name"
-            ],
             "properties": {
-                "amount": {
-                    "type": "number"
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "merchant_order_no": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
                 },
-                "order_name": {
+                "pattern": {
                     "type": "string",
-                    "maxLength": 64
+                    "enum": [
+                        "cycle",
+                        "reciprocal",
+                        "star"
+                    ]
                 },
-                "payment_type": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "confirmed",
+                        "dismissed"
+                    ]
                 },
-                "remark": {
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "risk_control.ReviewRiskDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
                     "type": "string",
-                    "maxLength": 100
+                    "maxLength": 255
                 }
             }
         },
-        "payment.PayOrderRequest": {
+        "risk_control.ReviewWashTradeFlagRequest": {
             "type": "object",
             "required": [
-                "order_no",
-                "pay_key"
+                "status"
             ],
             "properties": {
-                "order_no": {
-                    "type": "string"
+                "clawback": {
+                    "type": "boolean"
                 },
-                "pay_key": {
+                "note": {
                     "type": "string",
-                    "maxLength": 6
+                    "maxLength": 255
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "dismissed"
+                    ]
                 }
             }
         },
-        "payment.QueryMerchantOrderResponse": {
+        "risk_control.RiskRuleRequest": {
             "type": "object",
+            "required": [
+                "action",
+                "name",
+                "params",
+                "type"
+            ],
             "properties": {
-                "addtime": {
-                    "type": "string",
-                    "example": "2023-12-08 12:00:00"
-                },
-                "code": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "endtime": {
-                    "type": "string",
-                    "example": "2023-12-08 12:05:00"
-                },
-                "money": {
+                "action": {
                     "type": "string",
-                    "example": "10.00"
+                    "enum": [
+                        "review",
+                        "block"
+                    ]
                 },
-                "msg": {
-                    "type": "string",
-                    "example": "查询订单号成功！"
+                "enabled": {
+                    "type": "boolean"
                 },
                 "name": {
                     "type": "string",
-                    "example": "商品名称"
-                },
-                "out_trade_no": {
-                    "type": "string",
-                    "example": "M202312080001"
+                    "maxLength": 64
                 },
-                "pid": {
-                    "type": "string",
-                    "example": "1001"
+                "params": {
+                    "type": "object"
                 },
-                "status": {
-                    "type": "integer",
-                    "example": 1
+                "priority": {
+                    "type": "integer"
                 },
-                "trade_no": {
-                    "type": "string",
-                    "example": "123456"
+                "scenes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "type": {
                     "type": "string",
-                    "example": "epay"
+                    "enum": [
+                        "velocity",
+                        "new_counterparty",
+                        "new_account",
+                        "large_amount",
+                        "funnel"
+                    ]
                 }
             }
         },
-        "payment.RefundMerchantOrderResponse": {
+        "role.ListUserRolesRequest": {
             "type": "object",
             "properties": {
-                "code": {
+                "page": {
                     "type": "integer",
-                    "example": 1
+                    "minimum": 1
                 },
-                "msg": {
-                    "type": "string",
-                    "example": "退款成功"
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "role": {
+                    "$ref": "#/definitions/model.AdminRole"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "payment.RefundOrderRequest": {
+        "role.UpdateUserRoleRequest": {
             "type": "object",
             "required": [
-                "key",
-                "money",
-                "pid",
-                "trade_no"
+                "role",
+                "user_id"
             ],
             "properties": {
-                "key": {
-                    "type": "string"
-                },
-                "money": {
-                    "type": "number"
-                },
-                "out_trade_no": {
-                    "type": "string"
-                },
-                "pid": {
-                    "type": "string"
+                "role": {
+                    "$ref": "#/definitions/model.AdminRole"
                 },
-                "trade_no": {
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "payment.TransferRequest": {
+        "scheduled_transfer.CreateScheduledTransferRequest": {
             "type": "object",
             "required": [
                 "amount",
@@ -1607,6 +5530,10 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "cron_expr": {
+                    "type": "string",
+                    "maxLength": 64
+                },
                 "pay_key": {
                     "type": "string",
                     "maxLength": 6
@@ -1620,6 +5547,67 @@ const docTemplate = `{
                 "remark": {
                     "type": "string",
                     "maxLength": 100
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "scheduled_transfer.ListScheduledTransferRunsRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                }
+            }
+        },
+        "scheduled_transfer.ListScheduledTransfersRequest": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "paused",
+                        "canceled",
+                        "completed"
+                    ]
+                }
+            }
+        },
+        "scheduled_transfer.ScheduledTransferIDRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
                 }
             }
         },
@@ -1654,12 +5642,77 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 255
                 },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
                 "value": {
                     "type": "string",
                     "maxLength": 255
                 }
             }
         },
+        "totp.ActivateRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "totp.CodeRequest": {
+            "type": "object",
+            "required": [
+                "totp_code"
+            ],
+            "properties": {
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "totp.UpdateThresholdRequest": {
+            "type": "object",
+            "required": [
+                "totp_code"
+            ],
+            "properties": {
+                "payment_threshold": {
+                    "type": "number"
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "user.BalanceEvent": {
+            "type": "object",
+            "properties": {
+                "available_balance": {
+                    "type": "number"
+                },
+                "community_balance": {
+                    "type": "number"
+                },
+                "pay_score": {
+                    "type": "integer"
+                },
+                "total_payment": {
+                    "type": "number"
+                },
+                "total_receive": {
+                    "type": "number"
+                },
+                "total_transfer": {
+                    "type": "number"
+                }
+            }
+        },
         "user.UpdatePayKeyRequest": {
             "type": "object",
             "required": [
@@ -1669,6 +5722,114 @@ const docTemplate = `{
                 "pay_key": {
                     "type": "string",
                     "maxLength": 6
+                },
+                "totp_code": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "user_manage.AdjustBalanceRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "direction",
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "credit",
+                        "debit"
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "user_manage.ListUserOperationsRequest": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                }
+            }
+        },
+        "user_manage.RevokeSessionsRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "user_manage.SearchUsersRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "keyword": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "page": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "trust_level": {
+                    "type": "integer",
+                    "maximum": 4,
+                    "minimum": 0
+                }
+            }
+        },
+        "user_manage.UnlockPayKeyRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "user_manage.UpdateUserStatusRequest": {
+            "type": "object",
+            "required": [
+                "is_active",
+                "reason"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
@@ -1682,6 +5843,9 @@ const docTemplate = `{
                 "daily_limit": {
                     "type": "integer"
                 },
+                "daily_transfer_limit": {
+                    "type": "integer"
+                },
                 "fee_rate": {
                     "type": "number"
                 },
@@ -1691,10 +5855,24 @@ const docTemplate = `{
                 "max_score": {
                     "type": "integer"
                 },
+                "max_transfer_amount": {
+                    "type": "integer"
+                },
                 "min_score": {
                     "type": "integer",
                     "minimum": 0
                 },
+                "min_transfer_account_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "monthly_transfer_limit": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
                 "score_rate": {
                     "type": "number"
                 }
@@ -1710,16 +5888,33 @@ const docTemplate = `{
                 "daily_limit": {
                     "type": "integer"
                 },
+                "daily_transfer_limit": {
+                    "type": "integer"
+                },
                 "fee_rate": {
                     "type": "number"
                 },
                 "max_score": {
                     "type": "integer"
                 },
+                "max_transfer_amount": {
+                    "type": "integer"
+                },
                 "min_score": {
                     "type": "integer",
                     "minimum": 0
                 },
+                "min_transfer_account_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "monthly_transfer_limit": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
                 "score_rate": {
                     "type": "number"
                 }
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+// Submission 一次待审批的配置变更，Before 为当前配置，After 为审批通过后要应用的请求
+// （删除时 After 为 nil）
+type Submission struct {
+	TargetType model.ChangeRequestTarget
+	TargetID   string
+	Action     model.ChangeRequestAction
+	Before     interface{}
+	After      interface{}
+	Reason     string
+}
+
+// HasPending 检查目标配置是否存在未过期的待审批变更
+func HasPending(tx *gorm.DB, targetType model.ChangeRequestTarget, targetID string) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.ChangeRequest{}).
+		Where("target_type = ? AND target_id = ? AND status = ? AND expires_at > ?",
+			targetType, targetID, model.ChangeRequestStatusPending, time.Now()).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Submit 在业务事务内创建变更申请，同一配置同时只允许存在一条待审批申请
+// 并发提交时由部分唯一索引拒绝后到的申请
+func Submit(c *gin.Context, tx *gorm.DB, submission *Submission) (*model.ChangeRequest, error) {
+	pending, err := HasPending(tx, submission.TargetType, submission.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New(ChangeRequestPending)
+	}
+
+	// 已过期但尚未被定时任务标记的申请会占用唯一索引，提交前先行标记
+	if err := tx.Model(&model.ChangeRequest{}).
+		Where("target_type = ? AND target_id = ? AND status = ? AND expires_at <= ?",
+			submission.TargetType, submission.TargetID, model.ChangeRequestStatusPending, time.Now()).
+		Update("status", model.ChangeRequestStatusExpired).Error; err != nil {
+		return nil, err
+	}
+
+	expireHours, err := model.GetIntByKey(c.Request.Context(), model.ConfigKeyChangeRequestExpireHours)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := json.Marshal(submission.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := json.Marshal(submission.After)
+	if err != nil {
+		return nil, err
+	}
+
+	maker, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	changeRequest := model.ChangeRequest{
+		TargetType:  submission.TargetType,
+		TargetID:    submission.TargetID,
+		Action:      submission.Action,
+		Before:      string(before),
+		After:       string(after),
+		Reason:      submission.Reason,
+		Status:      model.ChangeRequestStatusPending,
+		MakerUserID: maker.ID,
+		ExpiresAt:   time.Now().Add(time.Duration(expireHours) * time.Hour),
+	}
+	if err := tx.Create(&changeRequest).Error; err != nil {
+		if strings.Contains(err.Error(), "SQLSTATE 23505") {
+			return nil, errors.New(ChangeRequestPending)
+		}
+		return nil, err
+	}
+
+	if err := audit.Record(c, tx, &audit.Entry{
+		Actor:      maker,
+		Action:     audit.ActionChangeRequestSubmit,
+		TargetType: audit.TargetChangeRequest,
+		TargetID:   changeRequest.ID,
+		After:      &changeRequest,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &changeRequest, nil
+}
@@ -0,0 +1,26 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access_token
+
+const (
+	// MaxActiveTokens 单个用户最多持有的有效访问令牌数量
+	MaxActiveTokens = 20
+	// tokenRandomBytes 令牌随机部分的字节数
+	tokenRandomBytes = 24
+	// tokenPrefixLen 展示用的令牌前缀长度
+	tokenPrefixLen = 12
+)
@@ -0,0 +1,25 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access_token
+
+const (
+	AccessTokenNotFound = "访问令牌不存在"
+	InvalidTokenScope   = "无效的权限范围"
+	TooManyAccessTokens = "有效访问令牌数量已达上限"
+	AccessTokenRevoked  = "访问令牌已撤销"
+	GenerateTokenFailed = "生成访问令牌失败"
+)
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access_token
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateAccessTokenRequest 创建个人访问令牌请求，expires_in_days 为空表示永不过期
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=64"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+	TOTPCode      string   `json:"totp_code" binding:"omitempty,max=16"`
+}
+
+// CreateAccessTokenResponse 创建个人访问令牌响应，令牌明文仅返回一次
+type CreateAccessTokenResponse struct {
+	Token       string                     `json:"token"`
+	AccessToken *model.PersonalAccessToken `json:"access_token"`
+}
+
+// ListAccessTokens 获取当前用户的个人访问令牌列表
+// @Tags access_token
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/access-tokens [get]
+func ListAccessTokens(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var tokens []model.PersonalAccessToken
+	if err := db.DB(c.Request.Context()).
+		Where("user_id = ?", user.ID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(tokens))
+}
+
+// CreateAccessToken 创建个人访问令牌
+// @Tags access_token
+// @Accept json
+// @Produce json
+// @Param request body CreateAccessTokenRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/access-tokens [post]
+func CreateAccessToken(c *gin.Context) {
+	var req CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !slices.Contains(model.TokenScopes, model.TokenScope(scope)) {
+			c.JSON(http.StatusBadRequest, util.Err(InvalidTokenScope))
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	// 已启用 TOTP 时创建访问令牌必须通过二次验证
+	if !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
+	randomBytes := make([]byte, tokenRandomBytes)
+	if _, err := rand.Read(randomBytes); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(GenerateTokenFailed))
+		return
+	}
+	rawToken := oauth.AccessTokenPrefix + hex.EncodeToString(randomBytes)
+
+	token := model.PersonalAccessToken{
+		UserID:      user.ID,
+		Name:        req.Name,
+		TokenPrefix: rawToken[:tokenPrefixLen],
+		TokenHash:   model.HashAccessToken(rawToken),
+		Scopes:      strings.Join(scopes, " "),
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// 锁定用户行，串行化同一用户的并发创建以保证数量上限
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("id = ?", user.ID).
+			First(&model.User{}).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&model.PersonalAccessToken{}).
+			Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", user.ID, time.Now()).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= MaxActiveTokens {
+			return errors.New(TooManyAccessTokens)
+		}
+
+		if err := tx.Create(&token).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAccessTokenCreate,
+			TargetType: audit.TargetAccessToken,
+			TargetID:   token.ID,
+			After:      &token,
+		})
+	}); err != nil {
+		if err.Error() == TooManyAccessTokens {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(CreateAccessTokenResponse{Token: rawToken, AccessToken: &token}))
+}
+
+// RevokeAccessToken 撤销个人访问令牌
+// @Tags access_token
+// @Produce json
+// @Param id path uint64 true "访问令牌 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/access-tokens/{id} [delete]
+func RevokeAccessToken(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var token model.PersonalAccessToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+			First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(AccessTokenNotFound)
+			}
+			return err
+		}
+		if token.RevokedAt != nil {
+			return errors.New(AccessTokenRevoked)
+		}
+
+		now := time.Now()
+		if err := tx.Model(&token).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAccessTokenRevoke,
+			TargetType: audit.TargetAccessToken,
+			TargetID:   token.ID,
+			After:      map[string]interface{}{"revoked_at": now},
+		})
+	}); err != nil {
+		switch err.Error() {
+		case AccessTokenNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case AccessTokenRevoked:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
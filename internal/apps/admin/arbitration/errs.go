@@ -0,0 +1,24 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitration
+
+const (
+	DisputeNotFound       = "争议不存在"
+	DisputeNotArbitrating = "争议不在仲裁中"
+	OrderNotFound         = "争议关联订单不存在或状态异常"
+	PartialAmountInvalid  = "部分退款金额必须大于 0 且小于订单金额"
+)
@@ -0,0 +1,457 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitration
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/dispute"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListArbitrationsRequest 查询仲裁队列请求
+type ListArbitrationsRequest struct {
+	Page     int    `json:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" binding:"omitempty,oneof=arbitrating refund partial_refund closed"`
+}
+
+// ArbitrationItem 仲裁队列中的争议
+type ArbitrationItem struct {
+	model.Dispute
+	OrderName     string          `json:"order_name"`
+	Amount        decimal.Decimal `json:"amount"`
+	PayeeUserID   uint64          `json:"payee_user_id"`
+	PayeeUsername string          `json:"payee_username"`
+	TradeTime     time.Time       `json:"trade_time"`
+}
+
+// ListArbitrationsResponse 查询仲裁队列响应
+type ListArbitrationsResponse struct {
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	Disputes []ArbitrationItem `json:"disputes"`
+}
+
+// DisputeStats 用户历史争议统计
+type DisputeStats struct {
+	Total    int64                         `json:"total"`
+	ByStatus map[model.DisputeStatus]int64 `json:"by_status"`
+}
+
+// ArbitrationDetailResponse 仲裁详情响应
+type ArbitrationDetailResponse struct {
+	Dispute         model.Dispute          `json:"dispute"`
+	Order           model.Order            `json:"order"`
+	Messages        []model.DisputeMessage `json:"messages"`
+	Offers          []model.DisputeOffer   `json:"offers"`
+	PayerHistory    DisputeStats           `json:"payer_history"`
+	MerchantHistory DisputeStats           `json:"merchant_history"`
+}
+
+// ProposeOfferRequest 仲裁员提出部分退款方案请求
+type ProposeOfferRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required"`
+	Note   string          `json:"note" binding:"max=255"`
+}
+
+// SendArbitrationMessageRequest 管理员留言请求，附件通过 multipart 的 files 字段上传
+type SendArbitrationMessageRequest struct {
+	Content string `form:"content" binding:"max=1000"`
+}
+
+// RuleDisputeRequest 仲裁裁决请求
+type RuleDisputeRequest struct {
+	Ruling model.DisputeRuling `json:"ruling" binding:"required,oneof=refund partial_refund deny"`
+	Amount decimal.Decimal     `json:"amount"`
+	Note   string              `json:"note" binding:"required,max=500"`
+}
+
+// ListArbitrations 查询付款方申请平台仲裁的争议，默认仅返回待裁决的争议
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ListArbitrationsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes [post]
+func ListArbitrations(c *gin.Context) {
+	var req ListArbitrationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Status == "" {
+		req.Status = string(model.DisputeStatusArbitrating)
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.Dispute{}).
+		Joins("JOIN orders ON disputes.order_id = orders.id").
+		Where("disputes.appealed_at IS NOT NULL AND disputes.status = ?", req.Status)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListArbitrationsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Select("disputes.*, orders.order_name, orders.amount, orders.payee_user_id, orders.trade_time, payee_user.username AS payee_username, initiator_user.username AS initiator_username, handler_user.username AS handler_username").
+		Joins("JOIN users AS payee_user ON orders.payee_user_id = payee_user.id").
+		Joins("JOIN users AS initiator_user ON disputes.initiator_user_id = initiator_user.id").
+		Joins("LEFT JOIN users AS handler_user ON disputes.handler_user_id = handler_user.id").
+		Order("disputes.appealed_at ASC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&response.Disputes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetArbitration 查询仲裁详情，包含订单信息、双方陈述及双方历史争议统计
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "争议 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes/{id} [get]
+func GetArbitration(c *gin.Context) {
+	response := &ArbitrationDetailResponse{}
+
+	if err := db.DB(c.Request.Context()).Model(&model.Dispute{}).
+		Select("disputes.*, initiator_user.username AS initiator_username, handler_user.username AS handler_username").
+		Joins("JOIN users AS initiator_user ON disputes.initiator_user_id = initiator_user.id").
+		Joins("LEFT JOIN users AS handler_user ON disputes.handler_user_id = handler_user.id").
+		Where("disputes.id = ? AND disputes.appealed_at IS NOT NULL", c.Param("id")).
+		First(&response.Dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).
+		Select("orders.*, payer_user.username AS payer_username, payee_user.username AS payee_username").
+		Joins("LEFT JOIN users AS payer_user ON orders.payer_user_id = payer_user.id").
+		Joins("LEFT JOIN users AS payee_user ON orders.payee_user_id = payee_user.id").
+		Where("orders.id = ?", response.Dispute.OrderID).
+		First(&response.Order).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	messages, err := dispute.LoadMessages(db.DB(c.Request.Context()), []uint64{response.Dispute.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	response.Messages = messages[response.Dispute.ID]
+
+	offers, err := dispute.LoadOffers(db.DB(c.Request.Context()), []uint64{response.Dispute.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	response.Offers = offers[response.Dispute.ID]
+
+	if response.PayerHistory, err = getDisputeStats(c, "disputes.initiator_user_id = ?", response.Order.PayerUserID); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if response.MerchantHistory, err = getDisputeStats(c, "orders.payee_user_id = ?", response.Order.PayeeUserID); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// RuleDispute 对仲裁中的争议作出裁决：全额退款、部分退款或驳回
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "争议 ID"
+// @Param request body RuleDisputeRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes/{id}/rule [post]
+func RuleDispute(c *gin.Context) {
+	var req RuleDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	arbitrator, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var disputeRecord model.Dispute
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ?", c.Param("id")).
+				First(&disputeRecord).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(DisputeNotFound)
+				}
+				return err
+			}
+			if disputeRecord.Status != model.DisputeStatusArbitrating {
+				return errors.New(DisputeNotArbitrating)
+			}
+
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ? AND type = ?", disputeRecord.OrderID, model.OrderStatusDisputing, model.OrderTypePayment).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OrderNotFound)
+				}
+				return err
+			}
+
+			disputeStatus, orderStatus, refundAmount := model.DisputeStatusClosed, model.OrderStatusRefused, decimal.Zero
+			switch req.Ruling {
+			case model.DisputeRulingRefund:
+				disputeStatus, orderStatus, refundAmount = model.DisputeStatusRefund, model.OrderStatusRefund, order.Amount
+			case model.DisputeRulingPartialRefund:
+				if req.Amount.LessThanOrEqual(decimal.Zero) || req.Amount.GreaterThanOrEqual(order.Amount) || req.Amount.Exponent() < -2 {
+					return errors.New(PartialAmountInvalid)
+				}
+				disputeStatus, orderStatus, refundAmount = model.DisputeStatusPartialRefund, model.OrderStatusPartialRefund, req.Amount
+			}
+
+			if refundAmount.GreaterThan(decimal.Zero) {
+				if err := service.RefundOrder(tx, &order, refundAmount); err != nil {
+					return err
+				}
+			}
+
+			before := disputeRecord
+			if err := tx.Model(&disputeRecord).Updates(map[string]interface{}{
+				"status":             disputeStatus,
+				"handler_user_id":    arbitrator.ID,
+				"arbitrator_user_id": arbitrator.ID,
+				"ruling":             req.Ruling,
+				"ruling_note":        req.Note,
+				"ruled_at":           time.Now(),
+				"refund_amount":      refundAmount,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&order).Update("status", orderStatus).Error; err != nil {
+				return err
+			}
+
+			if err := dispute.CancelPendingOffers(tx, disputeRecord.ID); err != nil {
+				return err
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      arbitrator,
+				Action:     audit.ActionDisputeRule,
+				TargetType: audit.TargetDispute,
+				TargetID:   disputeRecord.ID,
+				Before:     &before,
+				After:      &disputeRecord,
+			})
+		},
+	); err != nil {
+		switch errMsg := err.Error(); errMsg {
+		case DisputeNotFound, OrderNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		case DisputeNotArbitrating, PartialAmountInvalid:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case common.RiskBlocked:
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	dispute.PublishOrderChange(c.Request.Context(), &order)
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// SendArbitrationMessage 管理员在争议下留言，付款方和商家均会收到通知
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path uint64 true "争议 ID"
+// @Param content formData string false "留言内容"
+// @Param files formData file false "附件（图片或文本文件）"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes/{id}/messages [post]
+func SendArbitrationMessage(c *gin.Context) {
+	var req SendArbitrationMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		files = form.File["files"]
+	}
+
+	adminUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var disputeRecord model.Dispute
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&disputeRecord).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+	if disputeRecord.Status != model.DisputeStatusDisputing && disputeRecord.Status != model.DisputeStatusArbitrating {
+		c.JSON(http.StatusBadRequest, util.Err(dispute.DisputeNotActive))
+		return
+	}
+
+	message, err := dispute.PostMessage(c, &disputeRecord, adminUser, model.DisputePartyAdmin, req.Content, files)
+	if err != nil {
+		switch errMsg := err.Error(); errMsg {
+		case dispute.MessageEmpty, dispute.TooManyAttachments, dispute.AttachmentTooLarge, dispute.AttachmentTypeNotAllowed:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(message))
+}
+
+// DownloadArbitrationAttachment 管理员下载争议附件
+// @Tags admin
+// @Produce octet-stream
+// @Param id path uint64 true "附件 ID"
+// @Success 200 {file} file
+// @Router /api/v1/admin/dispute-attachments/{id} [get]
+func DownloadArbitrationAttachment(c *gin.Context) {
+	var attachment model.DisputeAttachment
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(dispute.AttachmentNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if err := dispute.ServeAttachment(c, &attachment); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+}
+
+// ProposeOffer 仲裁员为仲裁中的争议提出部分退款方案，由付款方接受或还价
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "争议 ID"
+// @Param request body ProposeOfferRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/disputes/{id}/offers [post]
+func ProposeOffer(c *gin.Context) {
+	var req ProposeOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	arbitrator, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var offer *model.DisputeOffer
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var disputeRecord model.Dispute
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ?", c.Param("id")).
+				First(&disputeRecord).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(DisputeNotFound)
+				}
+				return err
+			}
+			if disputeRecord.Status != model.DisputeStatusArbitrating {
+				return errors.New(DisputeNotArbitrating)
+			}
+
+			var order model.Order
+			if err := tx.Where("id = ? AND status = ? AND type = ?", disputeRecord.OrderID, model.OrderStatusDisputing, model.OrderTypePayment).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OrderNotFound)
+				}
+				return err
+			}
+
+			var err error
+			if offer, err = dispute.CreateOffer(tx, &disputeRecord, &order, arbitrator, model.DisputePartyAdmin, req.Amount, req.Note); err != nil {
+				return err
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      arbitrator,
+				Action:     audit.ActionDisputeOffer,
+				TargetType: audit.TargetDispute,
+				TargetID:   disputeRecord.ID,
+				After:      offer,
+			})
+		},
+	); err != nil {
+		switch errMsg := err.Error(); errMsg {
+		case DisputeNotFound, OrderNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		case DisputeNotArbitrating, dispute.OfferAmountInvalid, dispute.OfferPending:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(offer))
+}
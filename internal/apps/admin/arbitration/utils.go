@@ -0,0 +1,46 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arbitration
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+)
+
+// getDisputeStats 按状态统计满足条件的历史争议数量
+func getDisputeStats(c *gin.Context, condition string, userID uint64) (DisputeStats, error) {
+	var rows []struct {
+		Status model.DisputeStatus
+		Count  int64
+	}
+	if err := db.DB(c.Request.Context()).Model(&model.Dispute{}).
+		Select("disputes.status, COUNT(*) AS count").
+		Joins("JOIN orders ON disputes.order_id = orders.id").
+		Where(condition, userID).
+		Group("disputes.status").
+		Scan(&rows).Error; err != nil {
+		return DisputeStats{}, err
+	}
+
+	stats := DisputeStats{ByStatus: make(map[model.DisputeStatus]int64, len(rows))}
+	for _, row := range rows {
+		stats.Total += row.Count
+		stats.ByStatus[row.Status] = row.Count
+	}
+	return stats, nil
+}
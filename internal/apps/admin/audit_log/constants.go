@@ -0,0 +1,26 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_log
+
+const (
+	// ExportBatchSize 导出时每批读取的记录数
+	ExportBatchSize = 1000
+	// ExportMaxRows 单次导出的最大记录数
+	ExportMaxRows = 100000
+	// ExportFileNameFormat 导出文件名
+	ExportFileNameFormat = "audit_logs_%s.csv"
+)
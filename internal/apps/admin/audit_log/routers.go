@@ -0,0 +1,204 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_log
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter 审计日志过滤条件
+type AuditLogFilter struct {
+	ActorUserID uint64     `json:"actor_user_id" form:"actor_user_id"`
+	Action      string     `json:"action" form:"action" binding:"max=64"`
+	TargetType  string     `json:"target_type" form:"target_type" binding:"max=32"`
+	TargetID    string     `json:"target_id" form:"target_id" binding:"max=64"`
+	StartTime   *time.Time `json:"start_time" form:"start_time"`
+	EndTime     *time.Time `json:"end_time" form:"end_time" binding:"omitempty,gtfield=StartTime"`
+}
+
+// ListAuditLogsRequest 查询审计日志请求
+type ListAuditLogsRequest struct {
+	AuditLogFilter
+	Page     int `json:"page" binding:"min=1"`
+	PageSize int `json:"page_size" binding:"min=1,max=100"`
+}
+
+// ListAuditLogsResponse 查询审计日志响应
+type ListAuditLogsResponse struct {
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+	Logs     []model.AuditLog `json:"logs"`
+}
+
+// applyFilter 应用过滤条件
+func applyFilter(query *gorm.DB, filter *AuditLogFilter) *gorm.DB {
+	if filter.ActorUserID != 0 {
+		query = query.Where("actor_user_id = ?", filter.ActorUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.StartTime != nil {
+		query = query.Where("created_at >= ?", filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		query = query.Where("created_at <= ?", filter.EndTime)
+	}
+	return query
+}
+
+// ListAuditLogs 查询审计日志
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ListAuditLogsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/audit-logs [post]
+func ListAuditLogs(c *gin.Context) {
+	var req ListAuditLogsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := applyFilter(db.DB(c.Request.Context()).Model(&model.AuditLog{}), &req.AuditLogFilter)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListAuditLogsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("id DESC").Offset(offset).Limit(req.PageSize).Find(&response.Logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ExportAuditLogs 按过滤条件导出审计日志（CSV）
+// @Tags admin
+// @Produce text/csv
+// @Param actor_user_id query uint64 false "操作者用户 ID"
+// @Param action query string false "动作"
+// @Param target_type query string false "对象类型"
+// @Param target_id query string false "对象 ID"
+// @Param start_time query string false "开始时间（RFC3339）"
+// @Param end_time query string false "结束时间（RFC3339）"
+// @Success 200 {file} file
+// @Router /api/v1/admin/audit-logs/export [get]
+func ExportAuditLogs(c *gin.Context) {
+	var filter AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename="+ExportFileNameFormat, time.Now().Format("20060102150405")))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{
+		"seq", "created_at", "actor_user_id", "actor_username", "action", "target_type", "target_id",
+		"before", "after", "diff", "ip", "user_agent", "trace_id", "prev_hash", "hash",
+	})
+
+	lastID := uint64(0)
+	exported := 0
+	for exported < ExportMaxRows {
+		var logs []model.AuditLog
+		if err := applyFilter(db.DB(c.Request.Context()).Model(&model.AuditLog{}), &filter).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(ExportBatchSize).
+			Find(&logs).Error; err != nil {
+			_ = c.Error(err)
+			break
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		for _, log := range logs {
+			_ = writer.Write([]string{
+				strconv.FormatInt(log.Seq, 10),
+				log.CreatedAt.Format(time.RFC3339Nano),
+				strconv.FormatUint(log.ActorUserID, 10),
+				log.ActorUsername,
+				log.Action,
+				log.TargetType,
+				log.TargetID,
+				log.Before,
+				log.After,
+				log.Diff,
+				log.IP,
+				log.UserAgent,
+				log.TraceID,
+				log.PrevHash,
+				log.Hash,
+			})
+		}
+		writer.Flush()
+
+		exported += len(logs)
+		lastID = logs[len(logs)-1].ID
+	}
+
+	writer.Flush()
+}
+
+// VerifyAuditLogs 校验审计日志哈希链完整性
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/audit-logs/verify [get]
+func VerifyAuditLogs(c *gin.Context) {
+	result, err := audit.Verify(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(result))
+}
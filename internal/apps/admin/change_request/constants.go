@@ -0,0 +1,28 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change_request
+
+import (
+	"github.com/linux-do/credit/internal/apps/admin"
+	"github.com/linux-do/credit/internal/model"
+)
+
+// reviewPermissions 路由要求的审批权限之外，审批各类变更申请还需拥有目标配置的写权限
+var reviewPermissions = map[model.ChangeRequestTarget]admin.Permission{
+	model.ChangeRequestTargetUserPayConfig: admin.PermPayConfigWrite,
+	model.ChangeRequestTargetSystemConfig:  admin.PermSystemConfigWrite,
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change_request
+
+const (
+	ChangeRequestNotFound   = "变更申请不存在"
+	ChangeRequestNotPending = "变更申请已处理"
+	ChangeRequestExpired    = "变更申请已过期"
+	CannotReviewOwnRequest  = "不能审批自己提交的变更申请"
+	TargetNotFound          = "变更目标配置不存在"
+	UnsupportedTargetType   = "不支持的变更目标类型"
+	UnsupportedAction       = "不支持的变更操作类型"
+)
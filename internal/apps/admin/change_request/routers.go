@@ -0,0 +1,207 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change_request
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/admin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListChangeRequestsRequest 查询变更申请请求
+type ListChangeRequestsRequest struct {
+	Page       int                       `json:"page" binding:"min=1"`
+	PageSize   int                       `json:"page_size" binding:"min=1,max=100"`
+	Status     model.ChangeRequestStatus `json:"status" binding:"omitempty,oneof=pending approved rejected expired"`
+	TargetType model.ChangeRequestTarget `json:"target_type" binding:"omitempty,oneof=user_pay_config system_config"`
+}
+
+// ListChangeRequestsResponse 查询变更申请响应
+type ListChangeRequestsResponse struct {
+	Total          int64                 `json:"total"`
+	Page           int                   `json:"page"`
+	PageSize       int                   `json:"page_size"`
+	ChangeRequests []model.ChangeRequest `json:"change_requests"`
+}
+
+// ReviewChangeRequestRequest 审批变更申请请求
+type ReviewChangeRequestRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+// ListChangeRequests 查询敏感配置变更申请
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ListChangeRequestsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/change-requests [post]
+func ListChangeRequests(c *gin.Context) {
+	var req ListChangeRequestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.ChangeRequest{})
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("change_requests.status = ?", req.Status)
+	}
+	if req.TargetType != "" {
+		baseQuery = baseQuery.Where("change_requests.target_type = ?", req.TargetType)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListChangeRequestsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Select("change_requests.*, maker.username AS maker_username, checker.username AS checker_username").
+		Joins("LEFT JOIN users AS maker ON maker.id = change_requests.maker_user_id").
+		Joins("LEFT JOIN users AS checker ON checker.id = change_requests.checker_user_id").
+		Order("change_requests.created_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&response.ChangeRequests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ApproveChangeRequest 审批通过变更申请并应用变更
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "变更申请 ID"
+// @Param request body ReviewChangeRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/change-requests/{id}/approve [post]
+func ApproveChangeRequest(c *gin.Context) {
+	reviewChangeRequest(c, model.ChangeRequestStatusApproved)
+}
+
+// RejectChangeRequest 驳回变更申请
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "变更申请 ID"
+// @Param request body ReviewChangeRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/change-requests/{id}/reject [post]
+func RejectChangeRequest(c *gin.Context) {
+	reviewChangeRequest(c, model.ChangeRequestStatusRejected)
+}
+
+// reviewChangeRequest 审批变更申请，审批人不能是申请人且需拥有目标配置的写权限
+func reviewChangeRequest(c *gin.Context, status model.ChangeRequestStatus) {
+	var req ReviewChangeRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	checker, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	roles, _ := util.GetFromContext[[]model.AdminRole](c, admin.RolesObjKey)
+
+	var changeRequest model.ChangeRequest
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ?", c.Param("id")).
+				First(&changeRequest).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(ChangeRequestNotFound)
+				}
+				return err
+			}
+
+			if changeRequest.Status != model.ChangeRequestStatusPending {
+				return errors.New(ChangeRequestNotPending)
+			}
+			if !changeRequest.ExpiresAt.After(time.Now()) {
+				return errors.New(ChangeRequestExpired)
+			}
+			if changeRequest.MakerUserID == checker.ID {
+				return errors.New(CannotReviewOwnRequest)
+			}
+
+			perm, ok := reviewPermissions[changeRequest.TargetType]
+			if !ok {
+				return errors.New(UnsupportedTargetType)
+			}
+			if !admin.HasPermission(roles, perm) {
+				return errors.New(admin.PermissionDenied)
+			}
+
+			if status == model.ChangeRequestStatusApproved {
+				if err := applyChangeRequest(c, tx, checker, &changeRequest); err != nil {
+					return err
+				}
+			}
+
+			before := changeRequest
+			now := time.Now()
+			if err := tx.Model(&changeRequest).Updates(map[string]interface{}{
+				"status":          status,
+				"checker_user_id": checker.ID,
+				"review_note":     req.Note,
+				"reviewed_at":     now,
+			}).Error; err != nil {
+				return err
+			}
+
+			action := audit.ActionChangeRequestApprove
+			if status == model.ChangeRequestStatusRejected {
+				action = audit.ActionChangeRequestReject
+			}
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      checker,
+				Action:     action,
+				TargetType: audit.TargetChangeRequest,
+				TargetID:   changeRequest.ID,
+				Before:     &before,
+				After:      &changeRequest,
+			})
+		},
+	); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(changeRequest))
+}
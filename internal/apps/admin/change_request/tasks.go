@@ -0,0 +1,44 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change_request
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+)
+
+// HandleExpireChangeRequests 将超过有效期仍未审批的变更申请标记为已过期
+func HandleExpireChangeRequests(ctx context.Context, t *asynq.Task) error {
+	result := db.DB(ctx).
+		Model(&model.ChangeRequest{}).
+		Where("status = ? AND expires_at <= ?", model.ChangeRequestStatusPending, time.Now()).
+		Update("status", model.ChangeRequestStatusExpired)
+	if result.Error != nil {
+		logger.ErrorF(ctx, "标记过期变更申请失败: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		logger.InfoF(ctx, "已将 %d 条变更申请标记为过期", result.RowsAffected)
+	}
+	return nil
+}
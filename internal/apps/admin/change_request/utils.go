@@ -0,0 +1,112 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change_request
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/admin"
+	"github.com/linux-do/credit/internal/apps/admin/system_config"
+	"github.com/linux-do/credit/internal/apps/admin/user_pay_config"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// applyChangeRequest 在审批事务内以审批人身份应用变更申请中保存的配置更新
+func applyChangeRequest(c *gin.Context, tx *gorm.DB, checker *model.User, changeRequest *model.ChangeRequest) error {
+	switch changeRequest.TargetType {
+	case model.ChangeRequestTargetUserPayConfig:
+		return applyUserPayConfigChange(c, tx, checker, changeRequest)
+	case model.ChangeRequestTargetSystemConfig:
+		var req system_config.UpdateSystemConfigRequest
+		if err := json.Unmarshal([]byte(changeRequest.After), &req); err != nil {
+			return err
+		}
+
+		var config model.SystemConfig
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", changeRequest.TargetID).
+			First(&config).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(TargetNotFound)
+			}
+			return err
+		}
+		return system_config.ApplyUpdate(c, tx, checker, &config, &req)
+	default:
+		return errors.New(UnsupportedTargetType)
+	}
+}
+
+// applyUserPayConfigChange 按操作类型应用支付配置的创建、更新或删除
+func applyUserPayConfigChange(c *gin.Context, tx *gorm.DB, checker *model.User, changeRequest *model.ChangeRequest) error {
+	if changeRequest.Action == model.ChangeRequestActionCreate {
+		var req user_pay_config.CreateUserPayConfigRequest
+		if err := json.Unmarshal([]byte(changeRequest.After), &req); err != nil {
+			return err
+		}
+		if err := util.ValidateRates(req.FeeRate, req.ScoreRate); err != nil {
+			return err
+		}
+		return user_pay_config.ApplyCreate(c, tx, checker, &req)
+	}
+
+	var config model.UserPayConfig
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", changeRequest.TargetID).
+		First(&config).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New(TargetNotFound)
+		}
+		return err
+	}
+
+	switch changeRequest.Action {
+	case model.ChangeRequestActionDelete:
+		return user_pay_config.ApplyDelete(c, tx, checker, &config)
+	case model.ChangeRequestActionUpdate:
+		var req user_pay_config.UpdateUserPayConfigRequest
+		if err := json.Unmarshal([]byte(changeRequest.After), &req); err != nil {
+			return err
+		}
+		if err := util.ValidateRates(req.FeeRate, req.ScoreRate); err != nil {
+			return err
+		}
+		return user_pay_config.ApplyUpdate(c, tx, checker, &config, &req)
+	default:
+		return errors.New(UnsupportedAction)
+	}
+}
+
+// handleError 将事务中的业务错误映射为 HTTP 响应
+func handleError(c *gin.Context, err error) {
+	switch errMsg := err.Error(); errMsg {
+	case ChangeRequestNotFound, TargetNotFound:
+		c.JSON(http.StatusNotFound, util.Err(errMsg))
+	case CannotReviewOwnRequest, admin.PermissionDenied:
+		c.JSON(http.StatusForbidden, util.Err(errMsg))
+	case ChangeRequestNotPending, ChangeRequestExpired, UnsupportedTargetType, UnsupportedAction, user_pay_config.LevelExists:
+		c.JSON(http.StatusBadRequest, util.Err(errMsg))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+	}
+}
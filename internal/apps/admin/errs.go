@@ -17,5 +17,6 @@ limitations under the License.
 package admin
 
 const (
-	AdminRequired = "未经授权访问"
+	AdminRequired    = "未经授权访问"
+	PermissionDenied = "没有执行该操作的权限"
 )
@@ -19,6 +19,7 @@ package admin
 import (
 	"net/http"
 
+	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
 	"github.com/linux-do/credit/internal/model"
 	"github.com/linux-do/credit/internal/otel_trace"
@@ -28,6 +29,7 @@ import (
 	"github.com/linux-do/credit/internal/apps/oauth"
 )
 
+// LoginAdminRequired 要求当前用户至少拥有一个管理角色，并将角色写入上下文
 func LoginAdminRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// init trace
@@ -36,15 +38,36 @@ func LoginAdminRequired() gin.HandlerFunc {
 
 		user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-		if !user.IsAdmin {
+		roles, err := user.GetRoles(db.DB(ctx))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error(), "data": nil})
+			return
+		}
+		if len(roles) == 0 {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error_msg": AdminRequired, "data": nil})
 			return
 		}
 
 		// log
-		logger.InfoF(ctx, "[LoginAdminRequired] %d %s", user.ID, user.Username)
+		logger.InfoF(ctx, "[LoginAdminRequired] %d %s %v", user.ID, user.Username, roles)
+
+		// set roles
+		util.SetToContext(c, RolesObjKey, roles)
 
 		// next
 		c.Next()
 	}
 }
+
+// RequirePermission 要求当前管理员拥有指定权限，需在 LoginAdminRequired 之后使用
+func RequirePermission(perm Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, _ := util.GetFromContext[[]model.AdminRole](c, RolesObjKey)
+		if !HasPermission(roles, perm) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error_msg": PermissionDenied, "data": nil})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"github.com/linux-do/credit/internal/model"
+)
+
+type Permission string
+
+const (
+	PermSystemConfigRead    Permission = "system_config:read"
+	PermSystemConfigWrite   Permission = "system_config:write"
+	PermPayConfigRead       Permission = "user_pay_config:read"
+	PermPayConfigWrite      Permission = "user_pay_config:write"
+	PermChangeRequestReview Permission = "change_request:review"
+	PermRiskRead            Permission = "risk:read"
+	PermRiskWrite           Permission = "risk:write"
+	PermRiskReview          Permission = "risk:review"
+	PermUserRead            Permission = "user:read"
+	PermUserBan             Permission = "user:ban"
+	PermUserAdjust          Permission = "user:adjust"
+	PermDisputeRead         Permission = "dispute:read"
+	PermDisputeWrite        Permission = "dispute:write"
+	PermAuditRead           Permission = "audit:read"
+	PermRoleRead            Permission = "role:read"
+	PermRoleManage          Permission = "role:manage"
+)
+
+// rolePermissions 角色与权限的对应关系，超级管理员拥有全部权限
+var rolePermissions = map[model.AdminRole][]Permission{
+	model.AdminRoleSuperAdmin: {
+		PermSystemConfigRead, PermSystemConfigWrite,
+		PermPayConfigRead, PermPayConfigWrite,
+		PermRiskRead, PermRiskWrite, PermRiskReview,
+		PermUserRead, PermUserBan, PermUserAdjust,
+		PermDisputeRead, PermDisputeWrite,
+		PermChangeRequestReview,
+		PermAuditRead,
+		PermRoleRead, PermRoleManage,
+	},
+	model.AdminRoleFinance: {
+		PermSystemConfigRead,
+		PermPayConfigRead, PermPayConfigWrite,
+		PermChangeRequestReview,
+		PermRiskRead,
+		PermUserRead, PermUserAdjust,
+		PermDisputeRead,
+		PermRoleRead,
+	},
+	model.AdminRoleSupport: {
+		PermRiskRead, PermRiskReview,
+		PermUserRead, PermUserBan,
+		PermDisputeRead, PermDisputeWrite,
+		PermRoleRead,
+	},
+	model.AdminRoleAuditor: {
+		PermSystemConfigRead,
+		PermPayConfigRead,
+		PermRiskRead,
+		PermUserRead,
+		PermDisputeRead,
+		PermAuditRead,
+		PermRoleRead,
+	},
+}
+
+// IsValidRole 检查角色是否存在
+func IsValidRole(role model.AdminRole) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// GetRolePermissions 获取全部角色及其权限
+func GetRolePermissions() map[model.AdminRole][]Permission {
+	return rolePermissions
+}
+
+// HasPermission 检查角色集合是否拥有指定权限
+func HasPermission(roles []model.AdminRole, perm Permission) bool {
+	for _, role := range roles {
+		for _, p := range rolePermissions[role] {
+			if p == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
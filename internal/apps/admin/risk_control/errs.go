@@ -0,0 +1,26 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk_control
+
+const (
+	RiskRuleNotFound         = "风控规则不存在"
+	RiskRuleTypeInvalid      = "风控规则类型不合法"
+	RiskSceneInvalid         = "风控场景不合法"
+	RiskDecisionNotFound     = "风控决策不存在或无需复核"
+	RiskRuleParamsInvalidFmt = "风控规则参数不合法: %s"
+	WashTradeFlagNotFound    = "刷单标记不存在或已复核"
+)
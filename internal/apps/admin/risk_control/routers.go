@@ -0,0 +1,527 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk_control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RiskRuleRequest 创建/更新风控规则请求
+type RiskRuleRequest struct {
+	Name     string          `json:"name" binding:"required,max=64"`
+	Type     string          `json:"type" binding:"required,oneof=velocity new_counterparty new_account large_amount funnel"`
+	Scenes   []string        `json:"scenes"`
+	Params   json.RawMessage `json:"params" binding:"required" swaggertype:"object"`
+	Action   string          `json:"action" binding:"required,oneof=review block"`
+	Enabled  bool            `json:"enabled"`
+	Priority int             `json:"priority"`
+}
+
+// ListRiskDecisionsRequest 查询风控决策请求
+type ListRiskDecisionsRequest struct {
+	Page         int    `json:"page" binding:"min=1"`
+	PageSize     int    `json:"page_size" binding:"min=1,max=100"`
+	Scene        string `json:"scene" binding:"omitempty,oneof=payment transfer refund payout"`
+	Outcome      string `json:"outcome" binding:"omitempty,oneof=allow review block"`
+	ReviewStatus string `json:"review_status" binding:"omitempty,oneof=pending reviewed"`
+	UserID       uint64 `json:"user_id"`
+}
+
+// ListRiskDecisionsResponse 查询风控决策响应
+type ListRiskDecisionsResponse struct {
+	Total     int64                `json:"total"`
+	Page      int                  `json:"page"`
+	PageSize  int                  `json:"page_size"`
+	Decisions []model.RiskDecision `json:"decisions"`
+}
+
+// ReviewRiskDecisionRequest 复核风控决策请求
+type ReviewRiskDecisionRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
+
+// validateRiskRule 校验规则类型、场景与参数，返回规则模型
+func validateRiskRule(req *RiskRuleRequest) (*model.RiskRule, error) {
+	impl, ok := risk.GetRule(model.RiskRuleType(req.Type))
+	if !ok {
+		return nil, errors.New(RiskRuleTypeInvalid)
+	}
+	for _, scene := range req.Scenes {
+		if !risk.IsValidScene(scene) {
+			return nil, errors.New(RiskSceneInvalid)
+		}
+	}
+	if err := impl.ValidateParams(req.Params); err != nil {
+		return nil, fmt.Errorf(RiskRuleParamsInvalidFmt, err.Error())
+	}
+
+	return &model.RiskRule{
+		Name:     req.Name,
+		Type:     model.RiskRuleType(req.Type),
+		Scenes:   risk.JoinScenes(req.Scenes),
+		Params:   string(req.Params),
+		Action:   model.RiskOutcome(req.Action),
+		Enabled:  req.Enabled,
+		Priority: req.Priority,
+	}, nil
+}
+
+// CreateRiskRule 创建风控规则
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body RiskRuleRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/risk-rules [post]
+func CreateRiskRule(c *gin.Context) {
+	var req RiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	rule, err := validateRiskRule(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rule).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      currentUser,
+			Action:     audit.ActionRiskRuleCreate,
+			TargetType: audit.TargetRiskRule,
+			TargetID:   rule.ID,
+			After:      rule,
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(rule))
+}
+
+// ListRiskRules 获取风控规则列表
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/risk-rules [get]
+func ListRiskRules(c *gin.Context) {
+	var rules []model.RiskRule
+	if err := db.DB(c.Request.Context()).
+		Order("priority ASC, id ASC").
+		Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(rules))
+}
+
+// UpdateRiskRule 更新风控规则
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "规则 ID"
+// @Param request body RiskRuleRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/risk-rules/{id} [put]
+func UpdateRiskRule(c *gin.Context) {
+	var req RiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	rule, err := validateRiskRule(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var existing model.RiskRule
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RiskRuleNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	before := existing
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&existing).
+			Updates(map[string]interface{}{
+				"name":     rule.Name,
+				"type":     rule.Type,
+				"scenes":   rule.Scenes,
+				"params":   rule.Params,
+				"action":   rule.Action,
+				"enabled":  rule.Enabled,
+				"priority": rule.Priority,
+			}).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      currentUser,
+			Action:     audit.ActionRiskRuleUpdate,
+			TargetType: audit.TargetRiskRule,
+			TargetID:   existing.ID,
+			Before:     &before,
+			After:      &existing,
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// DeleteRiskRule 删除风控规则
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "规则 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/risk-rules/{id} [delete]
+func DeleteRiskRule(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var rule model.RiskRule
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RiskRuleNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&rule).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      currentUser,
+			Action:     audit.ActionRiskRuleDelete,
+			TargetType: audit.TargetRiskRule,
+			TargetID:   rule.ID,
+			Before:     &rule,
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListRiskDecisions 查询风控决策记录（审计与复核队列）
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ListRiskDecisionsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/risk-decisions [post]
+func ListRiskDecisions(c *gin.Context) {
+	var req ListRiskDecisionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.RiskDecision{})
+	if req.Scene != "" {
+		baseQuery = baseQuery.Where("risk_decisions.scene = ?", req.Scene)
+	}
+	if req.Outcome != "" {
+		baseQuery = baseQuery.Where("risk_decisions.outcome = ?", req.Outcome)
+	}
+	if req.ReviewStatus != "" {
+		baseQuery = baseQuery.Where("risk_decisions.review_status = ?", req.ReviewStatus)
+	}
+	if req.UserID != 0 {
+		baseQuery = baseQuery.Where("risk_decisions.user_id = ?", req.UserID)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var decisions []model.RiskDecision
+	if err := baseQuery.
+		Select("risk_decisions.*, users.username AS username").
+		Joins("LEFT JOIN users ON users.id = risk_decisions.user_id").
+		Order("risk_decisions.created_at DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&decisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(&ListRiskDecisionsResponse{
+		Total:     total,
+		Page:      req.Page,
+		PageSize:  req.PageSize,
+		Decisions: decisions,
+	}))
+}
+
+// ReviewRiskDecision 复核待审核的风控决策
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "决策 ID"
+// @Param request body ReviewRiskDecisionRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/risk-decisions/{id}/review [post]
+func ReviewRiskDecision(c *gin.Context) {
+	var req ReviewRiskDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	reviewer, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	result := db.DB(c.Request.Context()).
+		Model(&model.RiskDecision{}).
+		Where("id = ? AND review_status = ?", c.Param("id"), model.RiskReviewStatusPending).
+		Updates(map[string]interface{}{
+			"review_status":    model.RiskReviewStatusReviewed,
+			"reviewer_user_id": reviewer.ID,
+			"review_note":      req.Note,
+			"reviewed_at":      time.Now(),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(RiskDecisionNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListWashTradeFlagsRequest 查询刷单标记请求
+type ListWashTradeFlagsRequest struct {
+	Page     int    `json:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" binding:"omitempty,oneof=pending confirmed dismissed"`
+	Pattern  string `json:"pattern" binding:"omitempty,oneof=cycle reciprocal star"`
+	UserID   uint64 `json:"user_id"`
+}
+
+// ListWashTradeFlagsResponse 查询刷单标记响应
+type ListWashTradeFlagsResponse struct {
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+	Flags    []model.WashTradeFlag `json:"flags"`
+}
+
+// ReviewWashTradeFlagRequest 复核刷单标记请求
+type ReviewWashTradeFlagRequest struct {
+	Status   string `json:"status" binding:"required,oneof=confirmed dismissed"`
+	Clawback bool   `json:"clawback"`
+	Note     string `json:"note" binding:"max=255"`
+}
+
+// ListWashTradeFlags 查询刷单检测标记
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ListWashTradeFlagsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/wash-trade-flags [post]
+func ListWashTradeFlags(c *gin.Context) {
+	var req ListWashTradeFlagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.WashTradeFlag{})
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("wash_trade_flags.status = ?", req.Status)
+	}
+	if req.Pattern != "" {
+		baseQuery = baseQuery.Where("wash_trade_flags.pattern = ?", req.Pattern)
+	}
+	if req.UserID != 0 {
+		baseQuery = baseQuery.Where("wash_trade_flags.user_id = ?", req.UserID)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var flags []model.WashTradeFlag
+	if err := baseQuery.
+		Select("wash_trade_flags.*, users.username AS username").
+		Joins("LEFT JOIN users ON users.id = wash_trade_flags.user_id").
+		Order("wash_trade_flags.created_at DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&flags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(&ListWashTradeFlagsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Flags:    flags,
+	}))
+}
+
+// ReviewWashTradeFlag 复核刷单标记，确认时可选择扣回可疑交易获得的支付积分
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "标记 ID"
+// @Param request body ReviewWashTradeFlagRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/wash-trade-flags/{id}/review [post]
+func ReviewWashTradeFlag(c *gin.Context) {
+	var req ReviewWashTradeFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	reviewer, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var flag model.WashTradeFlag
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ?", c.Param("id"), model.WashTradeFlagStatusPending).
+				First(&flag).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(WashTradeFlagNotFound)
+				}
+				return err
+			}
+
+			var clawbackScore int64
+			if req.Status == string(model.WashTradeFlagStatusConfirmed) && req.Clawback {
+				// 锁定用户，串行化同一用户多个标记的扣回，保证订单只被扣回一次
+				var user model.User
+				if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+					Where("id = ?", flag.UserID).
+					First(&user).Error; err != nil {
+					return err
+				}
+
+				score, orderIDs, err := risk.CalculateWashTradeClawback(tx, &flag)
+				if err != nil {
+					return err
+				}
+
+				if len(orderIDs) > 0 {
+					markers := make([]model.WashTradeClawbackOrder, len(orderIDs))
+					for i, orderID := range orderIDs {
+						markers[i] = model.WashTradeClawbackOrder{UserID: flag.UserID, OrderID: orderID, FlagID: flag.ID}
+					}
+					if err := tx.CreateInBatches(&markers, 500).Error; err != nil {
+						return err
+					}
+				}
+
+				// 支付积分不扣为负数
+				clawbackScore = min(score, max(user.PayScore, 0))
+				if clawbackScore > 0 {
+					if err := tx.Model(&model.User{}).
+						Where("id = ?", flag.UserID).
+						UpdateColumn("pay_score", gorm.Expr("pay_score - ?", clawbackScore)).Error; err != nil {
+						return err
+					}
+					if err := service.NotifyPayLevelChange(tx, flag.UserID, -clawbackScore); err != nil {
+						return err
+					}
+				}
+			}
+
+			before := flag
+			if err := tx.Model(&flag).
+				Updates(map[string]interface{}{
+					"status":           req.Status,
+					"clawback_score":   clawbackScore,
+					"reviewer_user_id": reviewer.ID,
+					"review_note":      req.Note,
+					"reviewed_at":      time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      reviewer,
+				Action:     audit.ActionWashTradeReview,
+				TargetType: audit.TargetWashTradeFlag,
+				TargetID:   flag.ID,
+				Before:     &before,
+				After:      &flag,
+			})
+		},
+	); err != nil {
+		if err.Error() == WashTradeFlagNotFound {
+			c.JSON(http.StatusNotFound, util.Err(WashTradeFlagNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
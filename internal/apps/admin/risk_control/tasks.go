@@ -0,0 +1,110 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk_control
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"gorm.io/gorm"
+)
+
+// HandleDetectWashTrading 分析时间窗口内的资金流向，标记疑似刷单账户等待复核
+func HandleDetectWashTrading(ctx context.Context, _ *asynq.Task) error {
+	params, windowDays, err := loadWashTradeParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	windowEnd := time.Now()
+	windowStart := windowEnd.AddDate(0, 0, -windowDays)
+
+	edges, err := risk.LoadEdges(ctx, windowStart, windowEnd)
+	if err != nil {
+		logger.ErrorF(ctx, "加载资金流向失败: %v", err)
+		return err
+	}
+
+	findings := risk.DetectWashTrading(edges, params)
+	logger.InfoF(ctx, "刷单检测完成: 资金流向 %d 条，可疑模式 %d 个", len(edges), len(findings))
+
+	flagged := 0
+	for i := range findings {
+		finding := &findings[i]
+		members := finding.MembersKey()
+		for _, userID := range finding.FlagUserIDs {
+			// 同一用户、同一模式、同一组对手方在窗口重叠期内已标记过的不再重复标记
+			var existing model.WashTradeFlag
+			err := db.DB(ctx).
+				Where("user_id = ? AND pattern = ? AND members = ? AND window_end > ?", userID, finding.Pattern, members, windowStart).
+				First(&existing).Error
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err := db.DB(ctx).Create(&model.WashTradeFlag{
+				UserID:      userID,
+				Pattern:     finding.Pattern,
+				Members:     members,
+				OrderCount:  finding.OrderCount,
+				Amount:      finding.Amount,
+				WindowStart: windowStart,
+				WindowEnd:   windowEnd,
+				Status:      model.WashTradeFlagStatusPending,
+			}).Error; err != nil {
+				return err
+			}
+			flagged++
+		}
+	}
+
+	logger.InfoF(ctx, "刷单检测新增标记 %d 条", flagged)
+	return nil
+}
+
+// loadWashTradeParams 读取刷单检测参数
+func loadWashTradeParams(ctx context.Context) (risk.WashTradeParams, int, error) {
+	var params risk.WashTradeParams
+
+	windowDays, err := model.GetIntByKey(ctx, model.ConfigKeyWashTradeWindowDays)
+	if err != nil {
+		return params, 0, err
+	}
+	if params.MinEdgeAmount, err = model.GetDecimalByKey(ctx, model.ConfigKeyWashTradeMinEdgeAmount, 2); err != nil {
+		return params, 0, err
+	}
+	if params.MaxCycleLength, err = model.GetIntByKey(ctx, model.ConfigKeyWashTradeMaxCycleLength); err != nil {
+		return params, 0, err
+	}
+	if params.StarMinDegree, err = model.GetIntByKey(ctx, model.ConfigKeyWashTradeStarMinDegree); err != nil {
+		return params, 0, err
+	}
+	if params.StarConcentration, err = model.GetDecimalByKey(ctx, model.ConfigKeyWashTradeStarConcentration, 4); err != nil {
+		return params, 0, err
+	}
+
+	return params, windowDays, nil
+}
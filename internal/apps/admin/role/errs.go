@@ -0,0 +1,25 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+const (
+	InvalidRole               = "角色不存在"
+	UserNotFound              = "用户不存在"
+	RoleAlreadyGranted        = "用户已拥有该角色"
+	RoleNotGranted            = "用户未拥有该角色"
+	CannotRevokeOwnSuperAdmin = "不能撤销自己的超级管理员角色"
+)
@@ -0,0 +1,260 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/admin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+// RoleView 角色及其权限
+type RoleView struct {
+	Role        model.AdminRole    `json:"role"`
+	Permissions []admin.Permission `json:"permissions"`
+}
+
+// ListUserRolesRequest 查询角色授予记录请求
+type ListUserRolesRequest struct {
+	Page     int             `json:"page" binding:"min=1"`
+	PageSize int             `json:"page_size" binding:"min=1,max=100"`
+	UserID   uint64          `json:"user_id"`
+	Role     model.AdminRole `json:"role"`
+}
+
+// ListUserRolesResponse 查询角色授予记录响应
+type ListUserRolesResponse struct {
+	Total     int64            `json:"total"`
+	Page      int              `json:"page"`
+	PageSize  int              `json:"page_size"`
+	UserRoles []model.UserRole `json:"user_roles"`
+}
+
+// UpdateUserRoleRequest 授予/撤销角色请求
+type UpdateUserRoleRequest struct {
+	UserID uint64          `json:"user_id" binding:"required"`
+	Role   model.AdminRole `json:"role" binding:"required"`
+}
+
+// ListRoles 获取全部角色及其权限
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/roles [get]
+func ListRoles(c *gin.Context) {
+	rolePermissions := admin.GetRolePermissions()
+	roles := make([]RoleView, 0, len(rolePermissions))
+	for _, role := range []model.AdminRole{
+		model.AdminRoleSuperAdmin,
+		model.AdminRoleFinance,
+		model.AdminRoleSupport,
+		model.AdminRoleAuditor,
+	} {
+		roles = append(roles, RoleView{Role: role, Permissions: rolePermissions[role]})
+	}
+
+	c.JSON(http.StatusOK, util.OK(roles))
+}
+
+// GetMyRoles 获取当前管理员的角色及权限
+// @Tags admin
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/roles/me [get]
+func GetMyRoles(c *gin.Context) {
+	roles, _ := util.GetFromContext[[]model.AdminRole](c, admin.RolesObjKey)
+
+	rolePermissions := admin.GetRolePermissions()
+	response := make([]RoleView, 0, len(roles))
+	for _, role := range roles {
+		response = append(response, RoleView{Role: role, Permissions: rolePermissions[role]})
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ListUserRoles 查询角色授予记录
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ListUserRolesRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/user-roles [post]
+func ListUserRoles(c *gin.Context) {
+	var req ListUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.UserRole{})
+	if req.UserID != 0 {
+		baseQuery = baseQuery.Where("user_roles.user_id = ?", req.UserID)
+	}
+	if req.Role != "" {
+		baseQuery = baseQuery.Where("user_roles.role = ?", req.Role)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListUserRolesResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Select("user_roles.*, users.username AS username").
+		Joins("LEFT JOIN users ON users.id = user_roles.user_id").
+		Order("user_roles.created_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&response.UserRoles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GrantUserRole 授予用户管理角色
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body UpdateUserRoleRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/user-roles/grant [post]
+func GrantUserRole(c *gin.Context) {
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if !admin.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidRole))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var user model.User
+			if err := tx.Where("id = ?", req.UserID).First(&user).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(UserNotFound)
+				}
+				return err
+			}
+
+			var count int64
+			if err := tx.Model(&model.UserRole{}).
+				Where("user_id = ? AND role = ?", req.UserID, req.Role).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.New(RoleAlreadyGranted)
+			}
+
+			userRole := model.UserRole{
+				UserID:          req.UserID,
+				Role:            req.Role,
+				GrantedByUserID: currentUser.ID,
+			}
+			if err := tx.Create(&userRole).Error; err != nil {
+				return err
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      currentUser,
+				Action:     audit.ActionRoleGrant,
+				TargetType: audit.TargetUser,
+				TargetID:   req.UserID,
+				After:      map[string]interface{}{"role": req.Role},
+			})
+		},
+	); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// RevokeUserRole 撤销用户管理角色
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body UpdateUserRoleRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/user-roles/revoke [post]
+func RevokeUserRole(c *gin.Context) {
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if !admin.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidRole))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	if req.UserID == currentUser.ID && req.Role == model.AdminRoleSuperAdmin {
+		c.JSON(http.StatusBadRequest, util.Err(CannotRevokeOwnSuperAdmin))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			result := tx.Where("user_id = ? AND role = ?", req.UserID, req.Role).Delete(&model.UserRole{})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New(RoleNotGranted)
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      currentUser,
+				Action:     audit.ActionRoleRevoke,
+				TargetType: audit.TargetUser,
+				TargetID:   req.UserID,
+				Before:     map[string]interface{}{"role": req.Role},
+			})
+		},
+	); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
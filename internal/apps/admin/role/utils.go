@@ -0,0 +1,36 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/util"
+)
+
+// handleError 将事务中的业务错误映射为 HTTP 响应
+func handleError(c *gin.Context, err error) {
+	switch errMsg := err.Error(); errMsg {
+	case UserNotFound:
+		c.JSON(http.StatusNotFound, util.Err(errMsg))
+	case RoleAlreadyGranted, RoleNotGranted:
+		c.JSON(http.StatusBadRequest, util.Err(errMsg))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+	}
+}
@@ -17,8 +17,9 @@ limitations under the License.
 package system_config
 
 const (
-	SystemConfigNotFound = "系统配置不存在"
-	ConfigKeyRequired    = "配置键不能为空"
-	ConfigValueRequired  = "配置值不能为空"
-	ConfigKeyExists      = "配置键已存在"
+	SystemConfigNotFound  = "系统配置不存在"
+	ConfigKeyRequired     = "配置键不能为空"
+	ConfigValueRequired   = "配置值不能为空"
+	ConfigKeyExists       = "配置键已存在"
+	ConfigDeleteForbidden = "该配置变更需审批，不允许直接删除"
 )
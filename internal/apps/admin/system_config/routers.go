@@ -21,6 +21,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/approval"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
 	"github.com/linux-do/credit/internal/util"
@@ -38,6 +41,7 @@ type CreateSystemConfigRequest struct {
 type UpdateSystemConfigRequest struct {
 	Value       string `json:"value" binding:"required,max=255"`
 	Description string `json:"description" binding:"max=255"`
+	Reason      string `json:"reason" binding:"max=255"`
 }
 
 // CreateSystemConfig 创建系统配置
@@ -54,6 +58,8 @@ func CreateSystemConfig(c *gin.Context) {
 		return
 	}
 
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
 	// 检查配置键是否已存在
 	var existing model.SystemConfig
 	if err := db.DB(c.Request.Context()).Where("key = ?", req.Key).First(&existing).Error; err == nil {
@@ -76,6 +82,16 @@ func CreateSystemConfig(c *gin.Context) {
 			return err
 		}
 
+		if err := audit.Record(c, tx, &audit.Entry{
+			Actor:      currentUser,
+			Action:     audit.ActionSystemConfigCreate,
+			TargetType: audit.TargetSystemConfig,
+			TargetID:   config.Key,
+			After:      &config,
+		}); err != nil {
+			return err
+		}
+
 		if err := db.HSetJSON(c.Request.Context(), model.SystemConfigRedisHashKey, req.Key, &config); err != nil {
 			return err
 		}
@@ -126,7 +142,7 @@ func GetSystemConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, util.OK(config))
 }
 
-// UpdateSystemConfig 更新系统配置
+// UpdateSystemConfig 更新系统配置，敏感配置的取值变更需审批
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -141,6 +157,8 @@ func UpdateSystemConfig(c *gin.Context) {
 		return
 	}
 
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
 	key := c.Param("key")
 
 	// 检查配置是否存在
@@ -154,23 +172,42 @@ func UpdateSystemConfig(c *gin.Context) {
 		return
 	}
 
+	var changeRequest *model.ChangeRequest
 	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
-		// 更新配置
-		if err := tx.Model(&config).
-			Updates(map[string]interface{}{
-				"value":       req.Value,
-				"description": req.Description,
-			}).Error; err != nil {
+		pending, err := approval.HasPending(tx, model.ChangeRequestTargetSystemConfig, key)
+		if err != nil {
 			return err
 		}
+		if pending {
+			return errors.New(approval.ChangeRequestPending)
+		}
 
-		if err := db.HSetJSON(c.Request.Context(), model.SystemConfigRedisHashKey, key, &config); err != nil {
-			return err
+		if !requiresApproval(&config, &req) {
+			return ApplyUpdate(c, tx, currentUser, &config, &req)
 		}
 
-		return nil
+		// 敏感配置提交审批
+		changeRequest, err = approval.Submit(c, tx, &approval.Submission{
+			TargetType: model.ChangeRequestTargetSystemConfig,
+			TargetID:   key,
+			Action:     model.ChangeRequestActionUpdate,
+			Before:     &config,
+			After:      &req,
+			Reason:     req.Reason,
+		})
+		return err
 	}); err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		switch err.Error() {
+		case approval.ChangeRequestPending:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if changeRequest != nil {
+		c.JSON(http.StatusOK, util.OK(changeRequest))
 		return
 	}
 
@@ -184,6 +221,8 @@ func UpdateSystemConfig(c *gin.Context) {
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/admin/system-configs/{key} [delete]
 func DeleteSystemConfig(c *gin.Context) {
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
 	key := c.Param("key")
 
 	// 检查配置是否存在
@@ -197,12 +236,28 @@ func DeleteSystemConfig(c *gin.Context) {
 		return
 	}
 
+	// 需审批的配置不允许直接删除
+	if _, ok := approvalRequiredKeys[key]; ok {
+		c.JSON(http.StatusBadRequest, util.Err(ConfigDeleteForbidden))
+		return
+	}
+
 	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
 		// 删除配置
 		if err := tx.Delete(&config).Error; err != nil {
 			return err
 		}
 
+		if err := audit.Record(c, tx, &audit.Entry{
+			Actor:      currentUser,
+			Action:     audit.ActionSystemConfigDelete,
+			TargetType: audit.TargetSystemConfig,
+			TargetID:   key,
+			Before:     &config,
+		}); err != nil {
+			return err
+		}
+
 		if err := db.Redis.HDel(c.Request.Context(), db.PrefixedKey(model.SystemConfigRedisHashKey), key).Err(); err != nil {
 			return err
 		}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system_config
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"gorm.io/gorm"
+)
+
+// ApplyUpdate 在事务内应用系统配置更新、记录审计日志并刷新缓存，审批通过时同样调用此方法，actor 为执行变更的管理员
+func ApplyUpdate(c *gin.Context, tx *gorm.DB, actor *model.User, config *model.SystemConfig, req *UpdateSystemConfigRequest) error {
+	before := *config
+	if err := tx.Model(config).
+		Updates(map[string]interface{}{
+			"value":       req.Value,
+			"description": req.Description,
+		}).Error; err != nil {
+		return err
+	}
+
+	if err := audit.Record(c, tx, &audit.Entry{
+		Actor:      actor,
+		Action:     audit.ActionSystemConfigUpdate,
+		TargetType: audit.TargetSystemConfig,
+		TargetID:   config.Key,
+		Before:     &before,
+		After:      config,
+	}); err != nil {
+		return err
+	}
+
+	return db.HSetJSON(c.Request.Context(), model.SystemConfigRedisHashKey, config.Key, config)
+}
+
+// requiresApproval 敏感配置的取值发生变化时需要审批
+func requiresApproval(config *model.SystemConfig, req *UpdateSystemConfigRequest) bool {
+	if _, ok := approvalRequiredKeys[config.Key]; !ok {
+		return false
+	}
+	return config.Value != req.Value
+}
@@ -0,0 +1,565 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user_manage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AdminUserView 管理员视角的用户信息（不含支付密码等敏感字段）
+type AdminUserView struct {
+	ID               uint64           `json:"id"`
+	Username         string           `json:"username"`
+	Nickname         string           `json:"nickname"`
+	AvatarUrl        string           `json:"avatar_url"`
+	TrustLevel       model.TrustLevel `json:"trust_level"`
+	PayScore         int64            `json:"pay_score"`
+	TotalReceive     decimal.Decimal  `json:"total_receive"`
+	TotalPayment     decimal.Decimal  `json:"total_payment"`
+	TotalTransfer    decimal.Decimal  `json:"total_transfer"`
+	TotalCommunity   decimal.Decimal  `json:"total_community"`
+	CommunityBalance decimal.Decimal  `json:"community_balance"`
+	AvailableBalance decimal.Decimal  `json:"available_balance"`
+	IsActive         bool             `json:"is_active"`
+	IsAdmin          bool             `json:"is_admin"`
+	LastLoginAt      time.Time        `json:"last_login_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// SearchUsersRequest 搜索用户请求
+type SearchUsersRequest struct {
+	Page       int    `json:"page" binding:"min=1"`
+	PageSize   int    `json:"page_size" binding:"min=1,max=100"`
+	Keyword    string `json:"keyword" binding:"max=64"`
+	IsActive   *bool  `json:"is_active"`
+	TrustLevel *int   `json:"trust_level" binding:"omitempty,min=0,max=4"`
+}
+
+// SearchUsersResponse 搜索用户响应
+type SearchUsersResponse struct {
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+	Users    []AdminUserView `json:"users"`
+}
+
+// UserDetailResponse 用户详情响应
+type UserDetailResponse struct {
+	User         AdminUserView  `json:"user"`
+	PayLevel     model.PayLevel `json:"pay_level"`
+	RecentOrders []model.Order  `json:"recent_orders"`
+}
+
+// UpdateUserStatusRequest 封禁/解封用户请求
+type UpdateUserStatusRequest struct {
+	IsActive *bool  `json:"is_active" binding:"required"`
+	Reason   string `json:"reason" binding:"required,max=255"`
+}
+
+// AdjustBalanceRequest 调整用户余额请求
+type AdjustBalanceRequest struct {
+	Direction string          `json:"direction" binding:"required,oneof=credit debit"`
+	Amount    decimal.Decimal `json:"amount" binding:"required"`
+	Reason    string          `json:"reason" binding:"required,max=200"`
+}
+
+// RevokeSessionsRequest 强制用户下线请求
+type RevokeSessionsRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// UnlockPayKeyRequest 解锁用户支付密码请求
+type UnlockPayKeyRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ListUserOperationsRequest 查询用户操作记录请求
+type ListUserOperationsRequest struct {
+	Page     int `json:"page" binding:"min=1"`
+	PageSize int `json:"page_size" binding:"min=1,max=100"`
+}
+
+// ListUserOperationsResponse 查询用户操作记录响应
+type ListUserOperationsResponse struct {
+	Total      int64                      `json:"total"`
+	Page       int                        `json:"page"`
+	PageSize   int                        `json:"page_size"`
+	Operations []model.AdminUserOperation `json:"operations"`
+}
+
+// SearchUsers 按用户名或用户 ID 搜索用户
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SearchUsersRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/search [post]
+func SearchUsers(c *gin.Context) {
+	var req SearchUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.User{})
+	if req.Keyword != "" {
+		if id, err := strconv.ParseUint(req.Keyword, 10, 64); err == nil {
+			baseQuery = baseQuery.Where("id = ? OR username LIKE ?", id, util.EscapeLike(req.Keyword)+"%")
+		} else {
+			baseQuery = baseQuery.Where("username LIKE ?", util.EscapeLike(req.Keyword)+"%")
+		}
+	}
+	if req.IsActive != nil {
+		baseQuery = baseQuery.Where("is_active = ?", *req.IsActive)
+	}
+	if req.TrustLevel != nil {
+		baseQuery = baseQuery.Where("trust_level = ?", *req.TrustLevel)
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &SearchUsersResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("id ASC").Offset(offset).Limit(req.PageSize).Find(&response.Users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetUserDetail 查询用户余额、支付等级及最近订单
+// @Tags admin
+// @Produce json
+// @Param id path uint64 true "用户 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id} [get]
+func GetUserDetail(c *gin.Context) {
+	var user model.User
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(UserNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	var payConfig model.UserPayConfig
+	if err := payConfig.GetByPayScore(db.DB(c.Request.Context()), user.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &UserDetailResponse{
+		User:     toAdminUserView(&user),
+		PayLevel: payConfig.Level,
+	}
+	if err := db.DB(c.Request.Context()).
+		Select("orders.*, payer_user.username AS payer_username, payee_user.username AS payee_username").
+		Joins("LEFT JOIN users AS payer_user ON orders.payer_user_id = payer_user.id").
+		Joins("LEFT JOIN users AS payee_user ON orders.payee_user_id = payee_user.id").
+		Where("orders.payer_user_id = ? OR orders.payee_user_id = ?", user.ID, user.ID).
+		Order("orders.created_at DESC").
+		Limit(RecentOrdersLimit).
+		Find(&response.RecentOrders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// UpdateUserStatus 封禁或解封用户，封禁时立即使其所有会话失效
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户 ID"
+// @Param request body UpdateUserStatusRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/status [post]
+func UpdateUserStatus(c *gin.Context) {
+	var req UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	admin, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var userID uint64
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var user model.User
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ?", c.Param("id")).
+				First(&user).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(UserNotFound)
+				}
+				return err
+			}
+			if user.ID == admin.ID {
+				return errors.New(CannotOperateSelf)
+			}
+			if user.IsActive == *req.IsActive {
+				return errors.New(UserStatusUnchanged)
+			}
+			userID = user.ID
+
+			if err := tx.Model(&user).UpdateColumn("is_active", *req.IsActive).Error; err != nil {
+				return err
+			}
+
+			action, auditAction := model.AdminUserActionUnban, audit.ActionUserUnban
+			if !*req.IsActive {
+				action, auditAction = model.AdminUserActionBan, audit.ActionUserBan
+			}
+			if err := tx.Create(&model.AdminUserOperation{
+				AdminUserID: admin.ID,
+				UserID:      user.ID,
+				Action:      action,
+				Amount:      decimal.Zero,
+				Reason:      req.Reason,
+			}).Error; err != nil {
+				return err
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      admin,
+				Action:     auditAction,
+				TargetType: audit.TargetUser,
+				TargetID:   user.ID,
+				Before:     map[string]interface{}{"is_active": !*req.IsActive},
+				After:      map[string]interface{}{"is_active": *req.IsActive, "reason": req.Reason},
+			})
+		},
+	); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	if !*req.IsActive {
+		if err := oauth.RevokeUserSessions(c.Request.Context(), userID); err != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// AdjustBalance 手动增加或扣减用户余额，生成调账订单计入用户交易记录
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户 ID"
+// @Param request body AdjustBalanceRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/adjust [post]
+func AdjustBalance(c *gin.Context) {
+	var req AdjustBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	admin, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var (
+		order model.Order
+		user  model.User
+	)
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ?", c.Param("id")).
+				First(&user).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(UserNotFound)
+				}
+				return err
+			}
+			if user.ID == admin.ID {
+				return errors.New(CannotOperateSelf)
+			}
+
+			now := time.Now()
+			order = model.Order{
+				OrderName: AdjustmentOrderName,
+				Amount:    req.Amount,
+				Status:    model.OrderStatusSuccess,
+				Type:      model.OrderTypeAdjustment,
+				Remark:    fmt.Sprintf(AdjustmentRemarkFormat, admin.Username, req.Reason),
+				TradeTime: now,
+				ExpiresAt: now,
+			}
+
+			action := model.AdminUserActionCredit
+			balanceExpr := gorm.Expr("available_balance + ?", req.Amount)
+			if req.Direction == string(model.AdminUserActionDebit) {
+				if user.AvailableBalance.LessThan(req.Amount) {
+					return errors.New(common.InsufficientBalance)
+				}
+				action = model.AdminUserActionDebit
+				balanceExpr = gorm.Expr("available_balance - ?", req.Amount)
+				order.PayerUserID = user.ID
+			} else {
+				order.PayeeUserID = user.ID
+			}
+
+			if err := tx.Model(&user).UpdateColumn("available_balance", balanceExpr).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&model.AdminUserOperation{
+				AdminUserID: admin.ID,
+				UserID:      user.ID,
+				Action:      action,
+				Amount:      req.Amount,
+				Reason:      req.Reason,
+				OrderID:     &order.ID,
+			}).Error; err != nil {
+				return err
+			}
+
+			return audit.Record(c, tx, &audit.Entry{
+				Actor:      admin,
+				Action:     audit.ActionUserAdjustBalance,
+				TargetType: audit.TargetUser,
+				TargetID:   user.ID,
+				Before:     map[string]interface{}{"available_balance": user.AvailableBalance},
+				After: map[string]interface{}{
+					"available_balance": balanceAfter(user.AvailableBalance, req.Amount, action),
+					"order_id":          order.ID,
+					"reason":            req.Reason,
+				},
+			})
+		},
+	); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	stream.PublishBalance(c.Request.Context(), user.ID)
+
+	c.JSON(http.StatusOK, util.OK(order))
+}
+
+// RevokeUserSessions 强制用户所有已登录会话立即失效
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户 ID"
+// @Param request body RevokeSessionsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/revoke-sessions [post]
+func RevokeUserSessions(c *gin.Context) {
+	var req RevokeSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	admin, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var user model.User
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(UserNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if err := oauth.RevokeUserSessions(c.Request.Context(), user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.AdminUserOperation{
+			AdminUserID: admin.ID,
+			UserID:      user.ID,
+			Action:      model.AdminUserActionRevokeSessions,
+			Amount:      decimal.Zero,
+			Reason:      req.Reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      admin,
+			Action:     audit.ActionUserRevokeSessions,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			After:      map[string]interface{}{"reason": req.Reason},
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// UnlockPayKey 清除用户支付密码的失败次数与锁定状态
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户 ID"
+// @Param request body UnlockPayKeyRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/unlock-pay-key [post]
+func UnlockPayKey(c *gin.Context) {
+	var req UnlockPayKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	admin, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var user model.User
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(UserNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	wasLocked, err := service.UnlockPayKey(c.Request.Context(), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.AdminUserOperation{
+			AdminUserID: admin.ID,
+			UserID:      user.ID,
+			Action:      model.AdminUserActionUnlockPayKey,
+			Amount:      decimal.Zero,
+			Reason:      req.Reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      admin,
+			Action:     audit.ActionUserUnlockPayKey,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     map[string]interface{}{"pay_key_locked": wasLocked},
+			After:      map[string]interface{}{"pay_key_locked": false, "reason": req.Reason},
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListUserOperations 查询管理员对用户的操作记录
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "用户 ID"
+// @Param request body ListUserOperationsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/admin/users/{id}/operations [post]
+func ListUserOperations(c *gin.Context) {
+	var req ListUserOperationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).
+		Model(&model.AdminUserOperation{}).
+		Where("admin_user_operations.user_id = ?", c.Param("id"))
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListUserOperationsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.
+		Select("admin_user_operations.*, users.username AS admin_username").
+		Joins("LEFT JOIN users ON users.id = admin_user_operations.admin_user_id").
+		Order("admin_user_operations.created_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&response.Operations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user_manage
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+)
+
+// toAdminUserView 转换为管理员视角的用户信息
+func toAdminUserView(user *model.User) AdminUserView {
+	return AdminUserView{
+		ID:               user.ID,
+		Username:         user.Username,
+		Nickname:         user.Nickname,
+		AvatarUrl:        user.AvatarUrl,
+		TrustLevel:       user.TrustLevel,
+		PayScore:         user.PayScore,
+		TotalReceive:     user.TotalReceive,
+		TotalPayment:     user.TotalPayment,
+		TotalTransfer:    user.TotalTransfer,
+		TotalCommunity:   user.TotalCommunity,
+		CommunityBalance: user.CommunityBalance,
+		AvailableBalance: user.AvailableBalance,
+		IsActive:         user.IsActive,
+		IsAdmin:          user.IsAdmin,
+		LastLoginAt:      user.LastLoginAt,
+		CreatedAt:        user.CreatedAt,
+	}
+}
+
+// balanceAfter 计算调账后的可用余额
+func balanceAfter(balance, amount decimal.Decimal, action model.AdminUserAction) decimal.Decimal {
+	if action == model.AdminUserActionDebit {
+		return balance.Sub(amount)
+	}
+	return balance.Add(amount)
+}
+
+// handleError 将事务中的业务错误映射为 HTTP 响应
+func handleError(c *gin.Context, err error) {
+	switch errMsg := err.Error(); errMsg {
+	case UserNotFound:
+		c.JSON(http.StatusNotFound, util.Err(errMsg))
+	case CannotOperateSelf, UserStatusUnchanged, common.InsufficientBalance:
+		c.JSON(http.StatusBadRequest, util.Err(errMsg))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+	}
+}
@@ -19,8 +19,11 @@ package user_pay_config
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/approval"
+	"github.com/linux-do/credit/internal/apps/oauth"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
 	"github.com/linux-do/credit/internal/util"
@@ -36,6 +39,18 @@ type CreateUserPayConfigRequest struct {
 	DailyLimit *int64          `json:"daily_limit"`
 	FeeRate    decimal.Decimal `json:"fee_rate" binding:"required"`
 	ScoreRate  decimal.Decimal `json:"score_rate" binding:"required"`
+
+	DailyTransferLimit     *int64 `json:"daily_transfer_limit"`
+	MonthlyTransferLimit   *int64 `json:"monthly_transfer_limit"`
+	MaxTransferAmount      *int64 `json:"max_transfer_amount"`
+	MinTransferAccountDays int    `json:"min_transfer_account_days" binding:"min=0"`
+
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// DeleteUserPayConfigRequest 删除支付配置请求
+type DeleteUserPayConfigRequest struct {
+	Reason string `form:"reason" binding:"max=255"`
 }
 
 // UpdateUserPayConfigRequest 更新支付配置请求
@@ -45,9 +60,16 @@ type UpdateUserPayConfigRequest struct {
 	DailyLimit *int64          `json:"daily_limit"`
 	FeeRate    decimal.Decimal `json:"fee_rate" binding:"required"`
 	ScoreRate  decimal.Decimal `json:"score_rate" binding:"required"`
+
+	DailyTransferLimit     *int64 `json:"daily_transfer_limit"`
+	MonthlyTransferLimit   *int64 `json:"monthly_transfer_limit"`
+	MaxTransferAmount      *int64 `json:"max_transfer_amount"`
+	MinTransferAccountDays int    `json:"min_transfer_account_days" binding:"min=0"`
+
+	Reason string `json:"reason" binding:"max=255"`
 }
 
-// CreateUserPayConfig 创建支付配置
+// CreateUserPayConfig 提交创建支付配置申请，审批通过后生效
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -67,31 +89,39 @@ func CreateUserPayConfig(c *gin.Context) {
 		return
 	}
 
-	// 检查等级是否已存在
-	var existing model.UserPayConfig
-	if err := db.DB(c.Request.Context()).Where("level = ?", req.Level).First(&existing).Error; err == nil {
-		c.JSON(http.StatusBadRequest, util.Err(LevelExists))
-		return
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	// 检查等级是否已存在，审批通过时会再次检查
+	if err := checkLevelAvailable(db.DB(c.Request.Context()), req.Level); err != nil {
+		if err.Error() == LevelExists {
+			c.JSON(http.StatusBadRequest, util.Err(LevelExists))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
 		return
 	}
 
-	config := model.UserPayConfig{
-		Level:      req.Level,
-		MinScore:   req.MinScore,
-		MaxScore:   req.MaxScore,
-		DailyLimit: req.DailyLimit,
-		FeeRate:    req.FeeRate,
-		ScoreRate:  req.ScoreRate,
-	}
-
-	if err := db.DB(c.Request.Context()).Create(&config).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	// 新增等级同样影响费率与限额，统一提交审批
+	var changeRequest *model.ChangeRequest
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		changeRequest, err = approval.Submit(c, tx, &approval.Submission{
+			TargetType: model.ChangeRequestTargetUserPayConfig,
+			TargetID:   string(req.Level),
+			Action:     model.ChangeRequestActionCreate,
+			After:      &req,
+			Reason:     req.Reason,
+		})
+		return err
+	}); err != nil {
+		switch err.Error() {
+		case approval.ChangeRequestPending:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, util.OK(config))
+	c.JSON(http.StatusOK, util.OK(changeRequest))
 }
 
 // ListUserPayConfigs 获取支付配置列表
@@ -131,7 +161,7 @@ func GetUserPayConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, util.OK(config))
 }
 
-// UpdateUserPayConfig 更新支付配置
+// UpdateUserPayConfig 更新支付配置，修改费率、积分倍率、限额、分数范围或转账限制时需审批
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -146,6 +176,8 @@ func UpdateUserPayConfig(c *gin.Context) {
 		return
 	}
 
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
 	// 验证费率和积分倍率
 	if err := util.ValidateRates(req.FeeRate, req.ScoreRate); err != nil {
 		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
@@ -163,30 +195,62 @@ func UpdateUserPayConfig(c *gin.Context) {
 		return
 	}
 
-	// 更新配置
-	if err := db.DB(c.Request.Context()).
-		Model(&config).
-		Updates(map[string]interface{}{
-			"min_score":   req.MinScore,
-			"max_score":   req.MaxScore,
-			"fee_rate":    req.FeeRate,
-			"score_rate":  req.ScoreRate,
-			"daily_limit": req.DailyLimit,
-		}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	// 更新配置，敏感字段变更提交审批
+	var changeRequest *model.ChangeRequest
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		pending, err := approval.HasPending(tx, model.ChangeRequestTargetUserPayConfig, strconv.FormatUint(config.ID, 10))
+		if err != nil {
+			return err
+		}
+		if pending {
+			return errors.New(approval.ChangeRequestPending)
+		}
+
+		if !requiresApproval(&config, &req) {
+			return ApplyUpdate(c, tx, currentUser, &config, &req)
+		}
+
+		changeRequest, err = approval.Submit(c, tx, &approval.Submission{
+			TargetType: model.ChangeRequestTargetUserPayConfig,
+			TargetID:   strconv.FormatUint(config.ID, 10),
+			Action:     model.ChangeRequestActionUpdate,
+			Before:     &config,
+			After:      &req,
+			Reason:     req.Reason,
+		})
+		return err
+	}); err != nil {
+		switch err.Error() {
+		case approval.ChangeRequestPending:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if changeRequest != nil {
+		c.JSON(http.StatusOK, util.OK(changeRequest))
 		return
 	}
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
-// DeleteUserPayConfig 删除支付配置
+// DeleteUserPayConfig 提交删除支付配置申请，审批通过后生效
 // @Tags admin
 // @Produce json
 // @Param id path string true "配置ID"
+// @Param request query DeleteUserPayConfigRequest false "删除原因"
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/admin/user-pay-configs/{id} [delete]
 func DeleteUserPayConfig(c *gin.Context) {
+	var req DeleteUserPayConfigRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
 	// 检查配置是否存在
 	var config model.UserPayConfig
 	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&config).Error; err != nil {
@@ -198,10 +262,27 @@ func DeleteUserPayConfig(c *gin.Context) {
 		return
 	}
 
-	if err := db.DB(c.Request.Context()).Delete(&config).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+	// 删除后可重建为不同费率或限额的等级，因此删除同样需要审批
+	var changeRequest *model.ChangeRequest
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		changeRequest, err = approval.Submit(c, tx, &approval.Submission{
+			TargetType: model.ChangeRequestTargetUserPayConfig,
+			TargetID:   strconv.FormatUint(config.ID, 10),
+			Action:     model.ChangeRequestActionDelete,
+			Before:     &config,
+			Reason:     req.Reason,
+		})
+		return err
+	}); err != nil {
+		switch err.Error() {
+		case approval.ChangeRequestPending:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, util.OKNil())
+	c.JSON(http.StatusOK, util.OK(changeRequest))
 }
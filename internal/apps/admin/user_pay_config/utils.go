@@ -0,0 +1,137 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user_pay_config
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/model"
+	"gorm.io/gorm"
+)
+
+// ApplyUpdate 在事务内应用支付配置更新并记录审计日志，审批通过时同样调用此方法，actor 为执行变更的管理员
+func ApplyUpdate(c *gin.Context, tx *gorm.DB, actor *model.User, config *model.UserPayConfig, req *UpdateUserPayConfigRequest) error {
+	before := *config
+	if err := tx.
+		Model(config).
+		Updates(map[string]interface{}{
+			"min_score":   req.MinScore,
+			"max_score":   req.MaxScore,
+			"fee_rate":    req.FeeRate,
+			"score_rate":  req.ScoreRate,
+			"daily_limit": req.DailyLimit,
+
+			"daily_transfer_limit":      req.DailyTransferLimit,
+			"monthly_transfer_limit":    req.MonthlyTransferLimit,
+			"max_transfer_amount":       req.MaxTransferAmount,
+			"min_transfer_account_days": req.MinTransferAccountDays,
+		}).Error; err != nil {
+		return err
+	}
+
+	return audit.Record(c, tx, &audit.Entry{
+		Actor:      actor,
+		Action:     audit.ActionUserPayConfigUpdate,
+		TargetType: audit.TargetUserPayConfig,
+		TargetID:   config.ID,
+		Before:     &before,
+		After:      config,
+	})
+}
+
+// ApplyCreate 在事务内创建支付配置并记录审计日志，仅在审批通过时调用
+func ApplyCreate(c *gin.Context, tx *gorm.DB, actor *model.User, req *CreateUserPayConfigRequest) error {
+	if err := checkLevelAvailable(tx, req.Level); err != nil {
+		return err
+	}
+
+	config := model.UserPayConfig{
+		Level:      req.Level,
+		MinScore:   req.MinScore,
+		MaxScore:   req.MaxScore,
+		DailyLimit: req.DailyLimit,
+		FeeRate:    req.FeeRate,
+		ScoreRate:  req.ScoreRate,
+
+		DailyTransferLimit:     req.DailyTransferLimit,
+		MonthlyTransferLimit:   req.MonthlyTransferLimit,
+		MaxTransferAmount:      req.MaxTransferAmount,
+		MinTransferAccountDays: req.MinTransferAccountDays,
+	}
+	if err := tx.Create(&config).Error; err != nil {
+		return err
+	}
+
+	return audit.Record(c, tx, &audit.Entry{
+		Actor:      actor,
+		Action:     audit.ActionUserPayConfigCreate,
+		TargetType: audit.TargetUserPayConfig,
+		TargetID:   config.ID,
+		After:      &config,
+	})
+}
+
+// ApplyDelete 在事务内删除支付配置并记录审计日志，仅在审批通过时调用
+func ApplyDelete(c *gin.Context, tx *gorm.DB, actor *model.User, config *model.UserPayConfig) error {
+	if err := tx.Delete(config).Error; err != nil {
+		return err
+	}
+
+	return audit.Record(c, tx, &audit.Entry{
+		Actor:      actor,
+		Action:     audit.ActionUserPayConfigDelete,
+		TargetType: audit.TargetUserPayConfig,
+		TargetID:   config.ID,
+		Before:     config,
+	})
+}
+
+// checkLevelAvailable 检查等级尚未被使用
+func checkLevelAvailable(tx *gorm.DB, level model.PayLevel) error {
+	var count int64
+	if err := tx.Model(&model.UserPayConfig{}).Where("level = ?", level).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New(LevelExists)
+	}
+	return nil
+}
+
+// requiresApproval 费率、积分倍率、每日限额、分数范围或转账限制发生变化时需要审批，
+// 这些字段都会影响该等级下的全部用户
+func requiresApproval(config *model.UserPayConfig, req *UpdateUserPayConfigRequest) bool {
+	return !config.FeeRate.Equal(req.FeeRate) ||
+		!config.ScoreRate.Equal(req.ScoreRate) ||
+		config.MinScore != req.MinScore ||
+		!int64PtrEqual(config.MaxScore, req.MaxScore) ||
+		!int64PtrEqual(config.DailyLimit, req.DailyLimit) ||
+		!int64PtrEqual(config.DailyTransferLimit, req.DailyTransferLimit) ||
+		!int64PtrEqual(config.MonthlyTransferLimit, req.MonthlyTransferLimit) ||
+		!int64PtrEqual(config.MaxTransferAmount, req.MaxTransferAmount) ||
+		config.MinTransferAccountDays != req.MinTransferAccountDays
+}
+
+// int64PtrEqual 比较两个可空整数是否相等
+func int64PtrEqual(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispute
+
+const (
+	MaxAttachmentsPerMessage = 5
+	AttachmentKeyFormat      = "disputes/%d/%d%s"
+	SniffLength              = 512
+)
+
+// allowedAttachmentTypes 允许上传的附件类型及其扩展名，类型由文件内容识别而非客户端声明
+var allowedAttachmentTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"text/plain": ".txt",
+}
@@ -23,4 +23,17 @@ const (
 	ReasonRequiredForRefusal = "拒绝退款时必须提供理由"
 	DisputeTimeWindowExpired = "订单已交易完成,超过争议时间窗口,无法发起争议"
 	DuplicateDispute         = "无法重复发起争议，如仍有疑问请联系商家或LINUX DO Credit 团队"
+	DisputeNotAppealable     = "争议不存在或当前状态无法申请仲裁"
+	AppealTimeWindowExpired  = "已超过仲裁申请时间窗口，无法申请仲裁"
+	NotDisputeParty          = "您不是该争议的当事方"
+	DisputeNotActive         = "争议已结束，无法继续留言"
+	MessageEmpty             = "留言内容和附件不能同时为空"
+	TooManyAttachments       = "附件数量超过上限"
+	AttachmentTooLarge       = "附件大小超过上限"
+	AttachmentTypeNotAllowed = "仅支持上传图片或文本文件"
+	AttachmentNotFound       = "附件不存在"
+	OfferAmountInvalid       = "部分退款金额必须大于 0 且小于订单金额"
+	OfferPending             = "已有待回应的部分退款方案"
+	OfferNotFound            = "部分退款方案不存在或已处理"
+	NotOfferResponder        = "您无权回应该部分退款方案"
 )
@@ -0,0 +1,212 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/storage"
+	"gorm.io/gorm"
+)
+
+// PostMessage 以指定身份在争议下留言并上传附件，通知其他当事方；事务失败时清理已写入的附件
+func PostMessage(c *gin.Context, dispute *model.Dispute, sender *model.User, role model.DisputeParty, content string, files []*multipart.FileHeader) (*model.DisputeMessage, error) {
+	if content == "" && len(files) == 0 {
+		return nil, errors.New(MessageEmpty)
+	}
+	if len(files) > MaxAttachmentsPerMessage {
+		return nil, errors.New(TooManyAttachments)
+	}
+	for _, file := range files {
+		if file.Size > config.Config.Storage.MaxFileSize {
+			return nil, errors.New(AttachmentTooLarge)
+		}
+	}
+
+	message := model.DisputeMessage{
+		DisputeID:    dispute.ID,
+		SenderUserID: sender.ID,
+		SenderRole:   role,
+		Content:      content,
+	}
+
+	var storedKeys []string
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var order model.Order
+			if err := tx.Where("id = ?", dispute.OrderID).First(&order).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&message).Error; err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				attachment, err := saveAttachment(c.Request.Context(), tx, &message, file)
+				if attachment != nil {
+					storedKeys = append(storedKeys, attachment.StorageKey)
+				}
+				if err != nil {
+					return err
+				}
+				message.Attachments = append(message.Attachments, *attachment)
+			}
+
+			return notifyParties(tx, dispute, &order, role)
+		},
+	); err != nil {
+		deleteBlobs(c.Request.Context(), storedKeys)
+		return nil, err
+	}
+
+	message.SenderUsername = sender.Username
+	return &message, nil
+}
+
+// saveAttachment 识别文件类型、写入附件记录并保存文件内容，文件写入后即返回附件以便失败时清理
+func saveAttachment(ctx context.Context, tx *gorm.DB, message *model.DisputeMessage, file *multipart.FileHeader) (*model.DisputeAttachment, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = src.Close() }()
+
+	head := make([]byte, SniffLength)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return nil, err
+	}
+	ext, ok := allowedAttachmentTypes[contentType]
+	if !ok {
+		return nil, errors.New(AttachmentTypeNotAllowed)
+	}
+
+	attachment := model.DisputeAttachment{
+		ID:          idgen.NextUint64ID(),
+		DisputeID:   message.DisputeID,
+		MessageID:   message.ID,
+		FileName:    filepath.Base(file.Filename),
+		ContentType: contentType,
+		Size:        file.Size,
+	}
+	attachment.StorageKey = fmt.Sprintf(AttachmentKeyFormat, message.DisputeID, attachment.ID, ext)
+
+	if err := storage.Store.Put(ctx, attachment.StorageKey, io.MultiReader(bytes.NewReader(head), src)); err != nil {
+		return nil, err
+	}
+	if err := tx.Create(&attachment).Error; err != nil {
+		return &attachment, err
+	}
+	return &attachment, nil
+}
+
+// notifyParties 通知留言方以外的争议当事方，管理员留言时通知付款方和商家
+func notifyParties(tx *gorm.DB, dispute *model.Dispute, order *model.Order, role model.DisputeParty) error {
+	var recipients []uint64
+	switch role {
+	case model.DisputePartyPayer:
+		recipients = []uint64{order.PayeeUserID}
+	case model.DisputePartyMerchant:
+		recipients = []uint64{dispute.InitiatorUserID}
+	case model.DisputePartyAdmin:
+		recipients = []uint64{dispute.InitiatorUserID, order.PayeeUserID}
+	}
+
+	for _, userID := range recipients {
+		if err := service.Notify(
+			tx,
+			userID,
+			model.NotificationCategoryDispute,
+			"争议有新的回复",
+			fmt.Sprintf("订单「%s」的争议有新的回复，请及时查看", order.OrderName),
+			&dispute.ID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteBlobs 清理事务回滚后遗留的附件文件
+func deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := storage.Store.Delete(ctx, key); err != nil {
+			logger.ErrorF(ctx, "清理争议附件[%s]失败: %v", key, err)
+		}
+	}
+}
+
+// LoadMessages 批量查询争议的沟通记录及附件，按争议 ID 分组
+func LoadMessages(tx *gorm.DB, disputeIDs []uint64) (map[uint64][]model.DisputeMessage, error) {
+	result := make(map[uint64][]model.DisputeMessage, len(disputeIDs))
+	if len(disputeIDs) == 0 {
+		return result, nil
+	}
+
+	var messages []model.DisputeMessage
+	if err := tx.Model(&model.DisputeMessage{}).
+		Select("dispute_messages.*, users.username AS sender_username").
+		Joins("LEFT JOIN users ON users.id = dispute_messages.sender_user_id").
+		Where("dispute_messages.dispute_id IN ?", disputeIDs).
+		Preload("Attachments").
+		Order("dispute_messages.created_at ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	for _, message := range messages {
+		result[message.DisputeID] = append(result[message.DisputeID], message)
+	}
+	return result, nil
+}
+
+// ServeAttachment 输出附件内容，统一以下载方式返回以避免浏览器直接渲染
+func ServeAttachment(c *gin.Context, attachment *model.DisputeAttachment) error {
+	reader, err := storage.Store.Get(c.Request.Context(), attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, reader, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispute
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CreateOffer 在事务内提出部分退款方案并通知需要回应的一方，同一争议同时只允许一条待回应方案
+func CreateOffer(tx *gorm.DB, dispute *model.Dispute, order *model.Order, proposer *model.User, role model.DisputeParty, amount decimal.Decimal, note string) (*model.DisputeOffer, error) {
+	if amount.LessThanOrEqual(decimal.Zero) || amount.GreaterThanOrEqual(order.Amount) || amount.Exponent() < -2 {
+		return nil, errors.New(OfferAmountInvalid)
+	}
+
+	var count int64
+	if err := tx.Model(&model.DisputeOffer{}).
+		Where("dispute_id = ? AND status = ?", dispute.ID, model.DisputeOfferStatusPending).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New(OfferPending)
+	}
+
+	offer := model.DisputeOffer{
+		DisputeID:      dispute.ID,
+		ProposerUserID: proposer.ID,
+		ProposerRole:   role,
+		Amount:         amount,
+		Note:           note,
+		Status:         model.DisputeOfferStatusPending,
+	}
+	if err := tx.Create(&offer).Error; err != nil {
+		return nil, err
+	}
+
+	// 付款方的方案由商家回应，商家或仲裁员的方案由付款方回应
+	recipient := dispute.InitiatorUserID
+	if role == model.DisputePartyPayer {
+		recipient = order.PayeeUserID
+	}
+	if err := service.Notify(
+		tx,
+		recipient,
+		model.NotificationCategoryDispute,
+		"收到部分退款方案",
+		fmt.Sprintf("订单「%s」的争议收到部分退款 %s 的方案，请及时回应", order.OrderName, amount.StringFixed(2)),
+		&dispute.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	offer.ProposerUsername = proposer.Username
+	return &offer, nil
+}
+
+// SettlePartialRefund 按约定金额部分退款并结束争议，结果同时记录在争议和订单上
+// 在事务内执行，调用方需在事务提交后调用 PublishOrderChange 推送订单状态与双方余额
+func SettlePartialRefund(tx *gorm.DB, dispute *model.Dispute, order *model.Order, amount decimal.Decimal, handlerUserID uint64) error {
+	if err := service.RefundOrder(tx, order, amount); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.Dispute{}).
+		Where("id = ?", dispute.ID).
+		Updates(map[string]interface{}{
+			"status":          model.DisputeStatusPartialRefund,
+			"handler_user_id": handlerUserID,
+			"refund_amount":   amount,
+		}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.Order{}).
+		Where("id = ?", order.ID).
+		Update("status", model.OrderStatusPartialRefund).Error; err != nil {
+		return err
+	}
+
+	return CancelPendingOffers(tx, dispute.ID)
+}
+
+// CancelPendingOffers 争议结束时取消尚未回应的方案
+func CancelPendingOffers(tx *gorm.DB, disputeID uint64) error {
+	return tx.Model(&model.DisputeOffer{}).
+		Where("dispute_id = ? AND status = ?", disputeID, model.DisputeOfferStatusPending).
+		Update("status", model.DisputeOfferStatusCancelled).Error
+}
+
+// LoadOffers 批量查询争议的协商方案，按争议 ID 分组
+func LoadOffers(tx *gorm.DB, disputeIDs []uint64) (map[uint64][]model.DisputeOffer, error) {
+	result := make(map[uint64][]model.DisputeOffer, len(disputeIDs))
+	if len(disputeIDs) == 0 {
+		return result, nil
+	}
+
+	var offers []model.DisputeOffer
+	if err := tx.Model(&model.DisputeOffer{}).
+		Select("dispute_offers.*, users.username AS proposer_username").
+		Joins("LEFT JOIN users ON users.id = dispute_offers.proposer_user_id").
+		Where("dispute_offers.dispute_id IN ?", disputeIDs).
+		Order("dispute_offers.created_at ASC").
+		Find(&offers).Error; err != nil {
+		return nil, err
+	}
+
+	for _, offer := range offers {
+		result[offer.DisputeID] = append(result[offer.DisputeID], offer)
+	}
+	return result, nil
+}
@@ -18,14 +18,20 @@ package dispute
 
 import (
 	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/email"
 	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/common"
 	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/mailer"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/linux-do/credit/internal/util"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -36,21 +42,27 @@ import (
 type ListDisputesRequest struct {
 	Page      int     `json:"page" form:"page" binding:"min=1"`
 	PageSize  int     `json:"page_size" form:"page_size" binding:"min=1,max=100"`
-	Status    string  `json:"status" form:"status" binding:"omitempty,oneof=disputing refund closed"`
+	Status    string  `json:"status" form:"status" binding:"omitempty,oneof=disputing refund closed arbitrating partial_refund"`
 	DisputeID *uint64 `json:"dispute_id" form:"dispute_id" binding:"omitempty"`
 }
 
+// DisputeListItem 争议列表项
+type DisputeListItem struct {
+	model.Dispute
+	OrderName     string                 `json:"order_name"`
+	PayeeUsername string                 `json:"payee_username"`
+	Amount        decimal.Decimal        `json:"amount"`
+	Messages      []model.DisputeMessage `json:"messages" gorm:"-"`
+	Offers        []model.DisputeOffer   `json:"offers" gorm:"-"`
+	RespondBy     *time.Time             `json:"respond_by,omitempty" gorm:"-"`
+}
+
 // ListDisputesResponse 查询争议列表响应
 type ListDisputesResponse struct {
-	Total    int64 `json:"total"`
-	Page     int   `json:"page"`
-	PageSize int   `json:"page_size"`
-	Disputes []struct {
-		model.Dispute
-		OrderName     string          `json:"order_name"`
-		PayeeUsername string          `json:"payee_username"`
-		Amount        decimal.Decimal `json:"amount"`
-	} `json:"disputes"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	Disputes []DisputeListItem `json:"disputes"`
 }
 
 // ListDisputes 查询当前用户作为发起者的争议订单
@@ -102,6 +114,11 @@ func ListDisputes(c *gin.Context) {
 		return
 	}
 
+	if err := fillDetails(db.DB(c.Request.Context()), response.Disputes); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
 	c.JSON(http.StatusOK, util.OK(response))
 }
 
@@ -154,6 +171,24 @@ func ListMerchantDisputes(c *gin.Context) {
 		return
 	}
 
+	if err := fillDetails(db.DB(c.Request.Context()), response.Disputes); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	// 待处理的争议给出商家处理截止时间
+	responseHours, err := model.GetIntByKey(c.Request.Context(), model.ConfigKeyDisputeResponseHours)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	for i := range response.Disputes {
+		if response.Disputes[i].Status == model.DisputeStatusDisputing {
+			respondBy := RespondBy(response.Disputes[i].CreatedAt, responseHours)
+			response.Disputes[i].RespondBy = &respondBy
+		}
+	}
+
 	c.JSON(http.StatusOK, util.OK(response))
 }
 
@@ -185,6 +220,11 @@ func CreateDispute(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, util.Err(errKey.Error()))
 		return
 	}
+	responseHours, errKey := model.GetIntByKey(c.Request.Context(), model.ConfigKeyDisputeResponseHours)
+	if errKey != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errKey.Error()))
+		return
+	}
 
 	dispute := model.Dispute{
 		OrderID:         req.OrderID,
@@ -221,7 +261,29 @@ func CreateDispute(c *gin.Context) {
 				return err
 			}
 
-			return nil
+			if err := service.Notify(
+				tx,
+				order.PayeeUserID,
+				model.NotificationCategoryDispute,
+				"收到新的争议",
+				fmt.Sprintf("订单「%s」的付款方发起了争议：%s", order.OrderName, req.Reason),
+				&dispute.ID,
+			); err != nil {
+				return err
+			}
+
+			var payeeUser model.User
+			if err := payeeUser.GetByID(tx, order.PayeeUserID); err != nil {
+				return err
+			}
+			return email.Enqueue(tx, payeeUser.ID, mailer.EventDisputeCreated, map[string]interface{}{
+				"Username":      payeeUser.Username,
+				"PayerUsername": user.Username,
+				"OrderName":     order.OrderName,
+				"Amount":        order.Amount.StringFixed(2),
+				"Reason":        req.Reason,
+				"RespondBy":     RespondBy(dispute.CreatedAt, responseHours).Format(time.DateTime),
+			})
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -240,14 +302,15 @@ func CreateDispute(c *gin.Context) {
 	c.JSON(http.StatusOK, util.OK(dispute))
 }
 
-// RefundReviewRequest 退款审核请求
+// RefundReviewRequest 退款审核请求，status 为 partial_refund 时 amount 为提议的部分退款金额
 type RefundReviewRequest struct {
-	DisputeID uint64 `json:"dispute_id" binding:"required"`
-	Status    string `json:"status" binding:"required,oneof=refund closed"`
-	Reason    string `json:"reason" binding:"omitempty,max=100"`
+	DisputeID uint64          `json:"dispute_id" binding:"required"`
+	Status    string          `json:"status" binding:"required,oneof=refund closed partial_refund"`
+	Reason    string          `json:"reason" binding:"omitempty,max=100"`
+	Amount    decimal.Decimal `json:"amount"`
 }
 
-// RefundReview 退款审核（同意/拒绝）
+// RefundReview 退款审核（同意/拒绝/提议部分退款）
 // @Tags order
 // @Accept json
 // @Produce json
@@ -269,6 +332,7 @@ func RefundReview(c *gin.Context) {
 
 	merchantUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
+	var refunded *model.Order
 	if err := db.DB(c.Request.Context()).Transaction(
 		func(tx *gorm.DB) error {
 			var dispute model.Dispute
@@ -291,36 +355,17 @@ func RefundReview(c *gin.Context) {
 				return err
 			}
 
-			if status == model.DisputeStatusRefund {
-				var payerUser model.User
-				if err := payerUser.GetByID(tx, order.PayerUserID); err != nil {
-					return err
-				}
-
-				// 获取商家的支付配置
-				var merchantPayConfig model.UserPayConfig
-				if err := merchantPayConfig.GetByPayScore(tx, merchantUser.PayScore); err != nil {
-					return err
-				}
+			if status == model.DisputeStatusPartialRefund {
+				_, err := CreateOffer(tx, &dispute, &order, merchantUser, model.DisputePartyMerchant, req.Amount, req.Reason)
+				return err
+			}
 
-				merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
-				if err := tx.Model(&model.User{}).
-					Where("id = ?", merchantUser.ID).
-					UpdateColumns(map[string]interface{}{
-						"available_balance": gorm.Expr("available_balance - ?", order.Amount),
-						"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
-						"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
-					}).Error; err != nil {
-					return err
-				}
+			if err := CancelPendingOffers(tx, dispute.ID); err != nil {
+				return err
+			}
 
-				if err := tx.Model(&model.User{}).
-					Where("id = ?", payerUser.ID).
-					UpdateColumns(map[string]interface{}{
-						"available_balance": gorm.Expr("available_balance + ?", order.Amount),
-						"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
-						"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
-					}).Error; err != nil {
+			if status == model.DisputeStatusRefund {
+				if err := service.RefundOrder(tx, &order, order.Amount); err != nil {
 					return err
 				}
 
@@ -329,6 +374,7 @@ func RefundReview(c *gin.Context) {
 					Updates(map[string]interface{}{
 						"status":          model.DisputeStatusRefund,
 						"handler_user_id": merchantUser.ID,
+						"refund_amount":   order.Amount,
 					}).Error; err != nil {
 					return err
 				}
@@ -338,11 +384,12 @@ func RefundReview(c *gin.Context) {
 					Update("status", model.OrderStatusRefund).Error; err != nil {
 					return err
 				}
+				refunded = &order
 			} else if status == model.DisputeStatusClosed {
 				updateData := map[string]interface{}{
 					"status":          model.DisputeStatusClosed,
 					"handler_user_id": merchantUser.ID,
-					"reason":          dispute.Reason + " [受托方拒绝理由: " + req.Reason + "]",
+					"refused_at":      time.Now(),
 				}
 
 				if err := tx.Model(&model.Dispute{}).
@@ -351,6 +398,19 @@ func RefundReview(c *gin.Context) {
 					return err
 				}
 
+				// 拒绝理由作为商家留言记录到沟通记录中
+				if err := tx.Create(&model.DisputeMessage{
+					DisputeID:    dispute.ID,
+					SenderUserID: merchantUser.ID,
+					SenderRole:   model.DisputePartyMerchant,
+					Content:      req.Reason,
+				}).Error; err != nil {
+					return err
+				}
+				if err := notifyParties(tx, &dispute, &order, model.DisputePartyMerchant); err != nil {
+					return err
+				}
+
 				if err := tx.Model(&model.Order{}).
 					Where("id = ?", order.ID).
 					Update("status", model.OrderStatusRefused).Error; err != nil {
@@ -364,12 +424,20 @@ func RefundReview(c *gin.Context) {
 		errMsg := err.Error()
 		if errMsg == DisputeNotFound {
 			c.JSON(http.StatusNotFound, util.Err(DisputeNotFound))
+		} else if errMsg == common.RiskBlocked {
+			c.JSON(http.StatusForbidden, util.Err(common.RiskBlocked))
+		} else if errMsg == OfferAmountInvalid || errMsg == OfferPending {
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	if refunded != nil {
+		PublishOrderChange(c.Request.Context(), refunded)
+	}
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
@@ -431,7 +499,7 @@ func CloseDispute(c *gin.Context) {
 				return err
 			}
 
-			return nil
+			return CancelPendingOffers(tx, dispute.ID)
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -447,3 +515,285 @@ func CloseDispute(c *gin.Context) {
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
+
+// AppealDisputeRequest 申请平台仲裁请求
+type AppealDisputeRequest struct {
+	DisputeID uint64 `json:"dispute_id" binding:"required"`
+	Reason    string `json:"reason" binding:"required,max=500"`
+}
+
+// AppealDispute 商家拒绝退款后，付款方在仲裁时间窗口内申请平台仲裁
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body AppealDisputeRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/dispute/appeal [post]
+func AppealDispute(c *gin.Context) {
+	var req AppealDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	// 获取仲裁申请时间窗口配置（小时）
+	appealWindowHours, errKey := model.GetIntByKey(c.Request.Context(), model.ConfigKeyDisputeAppealWindowHours)
+	if errKey != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(errKey.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var dispute model.Dispute
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND initiator_user_id = ? AND status = ? AND refused_at IS NOT NULL", req.DisputeID, user.ID, model.DisputeStatusClosed).
+				First(&dispute).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(DisputeNotAppealable)
+				}
+				return err
+			}
+
+			// 商家拒绝时间 + 仲裁时间窗口 <= 当前时间，则无法申请仲裁
+			if time.Now().After(dispute.RefusedAt.Add(time.Duration(appealWindowHours) * time.Hour)) {
+				return errors.New(AppealTimeWindowExpired)
+			}
+
+			var order model.Order
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ? AND type = ?", dispute.OrderID, model.OrderStatusRefused, model.OrderTypePayment).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OrderNotFoundForDispute)
+				}
+				return err
+			}
+
+			if err := tx.Model(&model.Dispute{}).
+				Where("id = ?", dispute.ID).
+				Updates(map[string]interface{}{
+					"status":        model.DisputeStatusArbitrating,
+					"appeal_reason": req.Reason,
+					"appealed_at":   time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+
+			// 仲裁期间订单恢复为争议中
+			if err := tx.Model(&model.Order{}).
+				Where("id = ?", order.ID).
+				Update("status", model.OrderStatusDisputing).Error; err != nil {
+				return err
+			}
+
+			return nil
+		},
+	); err != nil {
+		errMsg := err.Error()
+		if errMsg == DisputeNotAppealable || errMsg == OrderNotFoundForDispute {
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		} else if errMsg == AppealTimeWindowExpired {
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// SendDisputeMessageRequest 争议留言请求，附件通过 multipart 的 files 字段上传
+type SendDisputeMessageRequest struct {
+	DisputeID uint64 `form:"dispute_id" binding:"required"`
+	Content   string `form:"content" binding:"max=1000"`
+}
+
+// SendDisputeMessage 付款方或商家在争议下留言
+// @Tags order
+// @Accept multipart/form-data
+// @Produce json
+// @Param dispute_id formData uint64 true "争议 ID"
+// @Param content formData string false "留言内容"
+// @Param files formData file false "附件（图片或文本文件）"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/dispute/messages [post]
+func SendDisputeMessage(c *gin.Context) {
+	var req SendDisputeMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		files = form.File["files"]
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	dispute, role, err := getDisputeAsParty(c, req.DisputeID, user.ID)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+	if dispute.Status != model.DisputeStatusDisputing && dispute.Status != model.DisputeStatusArbitrating {
+		c.JSON(http.StatusBadRequest, util.Err(DisputeNotActive))
+		return
+	}
+
+	message, err := PostMessage(c, dispute, user, role, req.Content, files)
+	if err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(message))
+}
+
+// DownloadDisputeAttachment 争议当事方下载附件
+// @Tags order
+// @Produce octet-stream
+// @Param id path uint64 true "附件 ID"
+// @Success 200 {file} file
+// @Router /api/v1/order/dispute/attachments/{id} [get]
+func DownloadDisputeAttachment(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var attachment model.DisputeAttachment
+	if err := db.DB(c.Request.Context()).Where("id = ?", c.Param("id")).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(AttachmentNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if _, _, err := getDisputeAsParty(c, attachment.DisputeID, user.ID); err != nil {
+		handleMessageError(c, err)
+		return
+	}
+
+	if err := ServeAttachment(c, &attachment); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+}
+
+// RespondDisputeOfferRequest 回应部分退款方案请求，action 为 counter 时 amount 为还价金额
+type RespondDisputeOfferRequest struct {
+	OfferID uint64          `json:"offer_id" binding:"required"`
+	Action  string          `json:"action" binding:"required,oneof=accept reject counter"`
+	Amount  decimal.Decimal `json:"amount"`
+	Note    string          `json:"note" binding:"max=255"`
+}
+
+// RespondDisputeOffer 回应部分退款方案（接受/拒绝/还价），接受后按约定金额完成部分退款
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param request body RespondDisputeOfferRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/order/dispute/offer/respond [post]
+func RespondDisputeOffer(c *gin.Context) {
+	var req RespondDisputeOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var refunded *model.Order
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var offer model.DisputeOffer
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ?", req.OfferID, model.DisputeOfferStatusPending).
+				First(&offer).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OfferNotFound)
+				}
+				return err
+			}
+
+			var dispute model.Dispute
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status IN ?", offer.DisputeID, []model.DisputeStatus{model.DisputeStatusDisputing, model.DisputeStatusArbitrating}).
+				First(&dispute).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(DisputeNotActive)
+				}
+				return err
+			}
+
+			var order model.Order
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND status = ? AND type = ?", dispute.OrderID, model.OrderStatusDisputing, model.OrderTypePayment).
+				First(&order).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(OrderNotFoundForDispute)
+				}
+				return err
+			}
+
+			// 付款方的方案由商家回应，商家或仲裁员的方案由付款方回应
+			role := model.DisputePartyPayer
+			responderID := dispute.InitiatorUserID
+			if offer.ProposerRole == model.DisputePartyPayer {
+				role = model.DisputePartyMerchant
+				responderID = order.PayeeUserID
+			}
+			if user.ID != responderID {
+				return errors.New(NotOfferResponder)
+			}
+
+			offerStatus := model.DisputeOfferStatusRejected
+			switch req.Action {
+			case "accept":
+				offerStatus = model.DisputeOfferStatusAccepted
+			case "counter":
+				offerStatus = model.DisputeOfferStatusCountered
+			}
+			if err := tx.Model(&offer).Updates(map[string]interface{}{
+				"status":            offerStatus,
+				"responder_user_id": user.ID,
+				"responded_at":      time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+
+			switch req.Action {
+			case "accept":
+				refunded = &order
+				return SettlePartialRefund(tx, &dispute, &order, offer.Amount, user.ID)
+			case "counter":
+				_, err := CreateOffer(tx, &dispute, &order, user, role, req.Amount, req.Note)
+				return err
+			}
+			return nil
+		},
+	); err != nil {
+		errMsg := err.Error()
+		if errMsg == OfferNotFound || errMsg == OrderNotFoundForDispute {
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		} else if errMsg == NotOfferResponder || errMsg == common.RiskBlocked {
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		} else if errMsg == DisputeNotActive || errMsg == OfferAmountInvalid || errMsg == OfferPending {
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	if refunded != nil {
+		PublishOrderChange(c.Request.Context(), refunded)
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
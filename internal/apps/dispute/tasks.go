@@ -21,13 +21,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/apps/email"
+	"github.com/linux-do/credit/internal/common"
 	"github.com/linux-do/credit/internal/config"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/mailer"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/linux-do/credit/internal/task"
 	"github.com/linux-do/credit/internal/task/scheduler"
 	"gorm.io/gorm"
@@ -36,10 +41,10 @@ import (
 
 // HandleAutoRefundExpiredDisputes 处理所有过期争议的批量任务
 func HandleAutoRefundExpiredDisputes(ctx context.Context, t *asynq.Task) error {
-	// 获取争议时间窗口配置（小时）
-	disputeTimeHours, errGet := model.GetIntByKey(ctx, model.ConfigKeyDisputeTimeWindowHours)
+	// 获取商家处理时限配置（小时）
+	responseHours, errGet := model.GetIntByKey(ctx, model.ConfigKeyDisputeResponseHours)
 	if errGet != nil {
-		logger.ErrorF(ctx, "获取争议时间窗口配置失败: %v", errGet)
+		logger.ErrorF(ctx, "获取商家处理时限配置失败: %v", errGet)
 		return errGet
 	}
 
@@ -48,7 +53,7 @@ func HandleAutoRefundExpiredDisputes(ctx context.Context, t *asynq.Task) error {
 	currentDelay := 0 * time.Second
 
 	// 计算过期时间阈值：created_at < deadline 的争议需要自动退款
-	deadline := time.Now().Add(-time.Duration(disputeTimeHours) * time.Hour)
+	deadline := time.Now().Add(-time.Duration(responseHours) * time.Hour)
 
 	for {
 		var disputes []model.Dispute
@@ -91,6 +96,104 @@ func HandleAutoRefundExpiredDisputes(ctx context.Context, t *asynq.Task) error {
 	return nil
 }
 
+// HandleRemindPendingDisputes 在自动退款前的各提醒时间点通知商家处理争议
+func HandleRemindPendingDisputes(ctx context.Context, t *asynq.Task) error {
+	responseHours, err := model.GetIntByKey(ctx, model.ConfigKeyDisputeResponseHours)
+	if err != nil {
+		logger.ErrorF(ctx, "获取商家处理时限配置失败: %v", err)
+		return err
+	}
+	reminderHours, err := model.GetIntListByKey(ctx, model.ConfigKeyDisputeReminderHours)
+	if err != nil {
+		logger.ErrorF(ctx, "获取争议提醒时间点配置失败: %v", err)
+		return err
+	}
+	if len(reminderHours) == 0 {
+		return nil
+	}
+
+	maxReminder := slices.Max(reminderHours)
+	now := time.Now()
+	// 仅距截止时间不超过最早提醒点的争议需要处理
+	createdAfter := now.Add(-time.Duration(responseHours) * time.Hour)
+	createdBefore := createdAfter.Add(time.Duration(maxReminder) * time.Hour)
+
+	pageSize := 1000
+	lastID := uint64(0)
+
+	for {
+		var disputes []struct {
+			model.Dispute
+			OrderName   string
+			PayeeUserID uint64
+		}
+		if err := db.DB(ctx).Model(&model.Dispute{}).
+			Select("disputes.*, orders.order_name, orders.payee_user_id").
+			Joins("JOIN orders ON disputes.order_id = orders.id").
+			Where("disputes.id > ? AND disputes.status = ? AND disputes.created_at >= ? AND disputes.created_at < ?",
+				lastID, model.DisputeStatusDisputing, createdAfter, createdBefore).
+			Order("disputes.id ASC").
+			Limit(pageSize).
+			Find(&disputes).Error; err != nil {
+			logger.ErrorF(ctx, "查询待提醒争议失败: %v", err)
+			return err
+		}
+
+		if len(disputes) == 0 {
+			break
+		}
+
+		for _, dispute := range disputes {
+			respondBy := RespondBy(dispute.CreatedAt, responseHours)
+			point := dueReminderPoint(reminderHours, respondBy.Sub(now))
+			// 已发送过同一或更临近时间点的提醒
+			if point == 0 || (dispute.RemindedHours != 0 && dispute.RemindedHours <= point) {
+				continue
+			}
+
+			if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+				result := tx.Model(&model.Dispute{}).
+					Where("id = ? AND status = ? AND (reminded_hours = 0 OR reminded_hours > ?)",
+						dispute.ID, model.DisputeStatusDisputing, point).
+					Update("reminded_hours", point)
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					return nil
+				}
+
+				if err := service.Notify(
+					tx,
+					dispute.PayeeUserID,
+					model.NotificationCategoryDispute,
+					"争议即将自动退款",
+					fmt.Sprintf("订单「%s」的争议请在 %s 前处理，逾期将自动全额退款", dispute.OrderName, respondBy.Format(time.DateTime)),
+					&dispute.ID,
+				); err != nil {
+					return err
+				}
+
+				var payeeUser model.User
+				if err := payeeUser.GetByID(tx, dispute.PayeeUserID); err != nil {
+					return err
+				}
+				return email.Enqueue(tx, payeeUser.ID, mailer.EventDisputeReminder, map[string]interface{}{
+					"Username":  payeeUser.Username,
+					"OrderName": dispute.OrderName,
+					"RespondBy": respondBy.Format(time.DateTime),
+				})
+			}); err != nil {
+				logger.ErrorF(ctx, "发送争议[ID:%d]处理提醒失败: %v", dispute.ID, err)
+				return err
+			}
+		}
+
+		lastID = disputes[len(disputes)-1].ID
+	}
+	return nil
+}
+
 // HandleAutoRefundSingleDispute 处理单个争议的自动退款任务
 func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 	// 解析任务参数
@@ -101,6 +204,7 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("解析任务参数失败: %w", err)
 	}
 
+	var refunded *model.Order
 	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
 		var dispute model.Dispute
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
@@ -133,35 +237,12 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 			return fmt.Errorf("查询收款方用户失败: %w", err)
 		}
 
-		// 获取商家的支付配置
-		var merchantPayConfig model.UserPayConfig
-		if err := merchantPayConfig.GetByPayScore(tx, payeeUser.PayScore); err != nil {
-			return fmt.Errorf("查询商家支付配置失败: %w", err)
-		}
-
-		// 计算商家积分减少：订单金额 × 商家的 score_rate
-		merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
-
-		// 商家(收款方)退款：扣除可用余额、总收款和积分
-		if err := tx.Model(&model.User{}).
-			Where("id = ?", payeeUser.ID).
-			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance - ?", order.Amount),
-				"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
-				"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
-			}).Error; err != nil {
-			return fmt.Errorf("商家退款失败: %w", err)
-		}
-
-		// 付款方收到退款：增加可用余额，减少总支付和支付积分
-		if err := tx.Model(&model.User{}).
-			Where("id = ?", payerUser.ID).
-			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance + ?", order.Amount),
-				"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
-				"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
-			}).Error; err != nil {
-			return fmt.Errorf("付款方退款失败: %w", err)
+		if err := service.RefundOrder(tx, &order, order.Amount); err != nil {
+			// 风控拦截时重试无意义，保留争议等待人工处理
+			if err.Error() == common.RiskBlocked {
+				return fmt.Errorf("%w: 风控拦截自动退款，需人工处理", asynq.SkipRetry)
+			}
+			return fmt.Errorf("退款失败: %w", err)
 		}
 
 		// 更新争议状态为已退款，handler_user_id 设为 0（系统自动处理）
@@ -170,6 +251,7 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 			Updates(map[string]interface{}{
 				"status":          model.DisputeStatusRefund,
 				"handler_user_id": 0,
+				"refund_amount":   order.Amount,
 			}).Error; err != nil {
 			return fmt.Errorf("更新争议状态失败: %w", err)
 		}
@@ -181,14 +263,22 @@ func HandleAutoRefundSingleDispute(ctx context.Context, t *asynq.Task) error {
 			return fmt.Errorf("更新订单状态失败: %w", err)
 		}
 
+		if err := CancelPendingOffers(tx, dispute.ID); err != nil {
+			return fmt.Errorf("取消部分退款方案失败: %w", err)
+		}
+
 		logger.InfoF(ctx, "自动退款成功: 争议[ID:%d] 订单[ID:%d] 金额[%s] 付款方[%s] 商家[%s]",
 			dispute.ID, order.ID, order.Amount.String(), payerUser.Username, payeeUser.Username)
 
+		refunded = &order
 		return nil
 	}); err != nil {
 		logger.ErrorF(ctx, "处理争议[ID:%d]自动退款失败: %v", payload.DisputeID, err)
 		return err
 	}
 
+	if refunded != nil {
+		PublishOrderChange(ctx, refunded)
+	}
 	return nil
 }
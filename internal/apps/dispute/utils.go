@@ -0,0 +1,115 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispute
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+// getDisputeAsParty 查询争议并确认用户为付款方或商家
+func getDisputeAsParty(c *gin.Context, disputeID, userID uint64) (*model.Dispute, model.DisputeParty, error) {
+	var dispute model.Dispute
+	if err := db.DB(c.Request.Context()).Where("id = ?", disputeID).First(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New(DisputeNotFound)
+		}
+		return nil, "", err
+	}
+	if dispute.InitiatorUserID == userID {
+		return &dispute, model.DisputePartyPayer, nil
+	}
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).Where("id = ?", dispute.OrderID).First(&order).Error; err != nil {
+		return nil, "", err
+	}
+	if order.PayeeUserID == userID {
+		return &dispute, model.DisputePartyMerchant, nil
+	}
+	return nil, "", errors.New(NotDisputeParty)
+}
+
+// fillDetails 为争议列表填充沟通记录和协商方案
+func fillDetails(tx *gorm.DB, items []DisputeListItem) error {
+	disputeIDs := make([]uint64, 0, len(items))
+	for _, item := range items {
+		disputeIDs = append(disputeIDs, item.ID)
+	}
+
+	messages, err := LoadMessages(tx, disputeIDs)
+	if err != nil {
+		return err
+	}
+	offers, err := LoadOffers(tx, disputeIDs)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		items[i].Messages = messages[items[i].ID]
+		items[i].Offers = offers[items[i].ID]
+	}
+	return nil
+}
+
+// handleMessageError 将留言与附件相关的业务错误映射为 HTTP 响应
+func handleMessageError(c *gin.Context, err error) {
+	switch errMsg := err.Error(); errMsg {
+	case DisputeNotFound, AttachmentNotFound:
+		c.JSON(http.StatusNotFound, util.Err(errMsg))
+	case NotDisputeParty:
+		c.JSON(http.StatusForbidden, util.Err(errMsg))
+	case MessageEmpty, TooManyAttachments, AttachmentTooLarge, AttachmentTypeNotAllowed:
+		c.JSON(http.StatusBadRequest, util.Err(errMsg))
+	default:
+		c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+	}
+}
+
+// RespondBy 计算商家处理争议的截止时间，逾期未处理将自动退款
+func RespondBy(createdAt time.Time, responseHours int) time.Time {
+	return createdAt.Add(time.Duration(responseHours) * time.Hour)
+}
+
+// dueReminderPoint 返回剩余时间已进入的最临近提醒点（小时），未进入任何提醒点时返回 0
+func dueReminderPoint(reminderHours []int, remaining time.Duration) int {
+	point := 0
+	for _, hours := range reminderHours {
+		if hours <= 0 || remaining > time.Duration(hours)*time.Hour {
+			continue
+		}
+		if point == 0 || hours < point {
+			point = hours
+		}
+	}
+	return point
+}
+
+// PublishOrderChange 在事务提交后推送订单状态与双方余额的变化
+func PublishOrderChange(ctx context.Context, order *model.Order) {
+	stream.PublishOrderStatus(ctx, order.ID)
+	stream.PublishBalance(ctx, order.PayerUserID, order.PayeeUserID)
+}
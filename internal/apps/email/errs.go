@@ -0,0 +1,26 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package email
+
+const (
+	EmailDisabled          = "邮件服务未启用"
+	EmailNotBound          = "尚未绑定邮箱"
+	EmailAlreadyVerified   = "该邮箱已完成验证"
+	VerifyTooFrequent      = "验证码发送过于频繁，请稍后再试"
+	VerifyCodeInvalid      = "验证码错误或已过期"
+	VerifyAttemptsExceeded = "验证码错误次数过多，请重新获取"
+)
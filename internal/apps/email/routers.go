@@ -0,0 +1,294 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package email
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/mailer"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// verifyCode Redis 中暂存的待验证邮箱与验证码
+type verifyCode struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+// GetEmail 查询当前用户绑定的邮箱
+// @Tags email
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/email [get]
+func GetEmail(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var userEmail model.UserEmail
+	if err := db.DB(c.Request.Context()).Where("user_id = ?", user.ID).First(&userEmail).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, util.OKNil())
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(userEmail))
+}
+
+// BindEmailRequest 绑定邮箱请求
+type BindEmailRequest struct {
+	Email  string `json:"email" binding:"required,email,max=255"`
+	Locale string `json:"locale" binding:"omitempty,oneof=zh en"`
+}
+
+// BindEmail 绑定或更换邮箱并发送验证码，验证通过前不会投递通知邮件
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param request body BindEmailRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/email [post]
+func BindEmail(c *gin.Context) {
+	var req BindEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+	if req.Locale == "" {
+		req.Locale = mailer.LocaleZH
+	}
+
+	if !mailer.Enabled() {
+		c.JSON(http.StatusBadRequest, util.Err(EmailDisabled))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	ctx := c.Request.Context()
+	cfg := config.Config.Email
+
+	var existing model.UserEmail
+	if err := db.DB(ctx).Where("user_id = ?", user.ID).First(&existing).Error; err == nil {
+		if existing.Email == req.Email && existing.VerifiedAt != nil && existing.BouncedAt == nil {
+			c.JSON(http.StatusBadRequest, util.Err(EmailAlreadyVerified))
+			return
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	cooldownKey := db.PrefixedKey(fmt.Sprintf(VerifyCooldownKeyFormat, user.ID))
+	if ok, err := db.Redis.SetNX(ctx, cooldownKey, 1, time.Duration(cfg.VerifyCooldown)*time.Second).Result(); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	} else if !ok {
+		c.JSON(http.StatusTooManyRequests, util.Err(VerifyTooFrequent))
+		return
+	}
+
+	code, err := generateVerifyCode()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	userEmail := model.UserEmail{
+		UserID: user.ID,
+		Email:  req.Email,
+		Locale: req.Locale,
+	}
+	if err = db.DB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"email":         req.Email,
+			"locale":        req.Locale,
+			"verified_at":   nil,
+			"bounced_at":    nil,
+			"bounce_reason": "",
+			"updated_at":    time.Now(),
+		}),
+	}).Create(&userEmail).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	ttl := time.Duration(cfg.VerifyCodeTTL) * time.Minute
+	value, _ := json.Marshal(verifyCode{Email: req.Email, Code: code})
+	pipe := db.Redis.TxPipeline()
+	pipe.Set(ctx, db.PrefixedKey(fmt.Sprintf(VerifyCodeKeyFormat, user.ID)), value, ttl)
+	pipe.Del(ctx, db.PrefixedKey(fmt.Sprintf(VerifyAttemptsKeyFormat, user.ID)))
+	if _, err = pipe.Exec(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	// 验证邮件发往尚未验证的地址，不经过 Enqueue 的可投递检查
+	if err = enqueueSend(&sendPayload{
+		UserID: user.ID,
+		To:     req.Email,
+		Locale: req.Locale,
+		Event:  mailer.EventVerifyEmail,
+		Data: map[string]interface{}{
+			"Username": user.Username,
+			"Code":     code,
+			"TTL":      cfg.VerifyCodeTTL,
+		},
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// VerifyEmailRequest 验证邮箱请求
+type VerifyEmailRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// VerifyEmail 使用邮件中的验证码完成邮箱验证
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/email/verify [post]
+func VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	ctx := c.Request.Context()
+	codeKey := db.PrefixedKey(fmt.Sprintf(VerifyCodeKeyFormat, user.ID))
+	attemptsKey := db.PrefixedKey(fmt.Sprintf(VerifyAttemptsKeyFormat, user.ID))
+
+	raw, err := db.Redis.Get(ctx, codeKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			c.JSON(http.StatusBadRequest, util.Err(VerifyCodeInvalid))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	var pending verifyCode
+	if err = json.Unmarshal(raw, &pending); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(pending.Code), []byte(req.Code)) != 1 {
+		attempts, errIncr := db.Redis.Incr(ctx, attemptsKey).Result()
+		if errIncr != nil {
+			c.JSON(http.StatusInternalServerError, util.Err(errIncr.Error()))
+			return
+		}
+		_ = db.Redis.Expire(ctx, attemptsKey, time.Duration(config.Config.Email.VerifyCodeTTL)*time.Minute).Err()
+		if attempts >= int64(config.Config.Email.VerifyMaxAttempts) {
+			_ = db.Redis.Del(ctx, codeKey, attemptsKey).Err()
+			c.JSON(http.StatusBadRequest, util.Err(VerifyAttemptsExceeded))
+			return
+		}
+		c.JSON(http.StatusBadRequest, util.Err(VerifyCodeInvalid))
+		return
+	}
+
+	// 验证码发出后邮箱被再次更换时，旧验证码失效
+	result := db.DB(ctx).Model(&model.UserEmail{}).
+		Where("user_id = ? AND email = ?", user.ID, pending.Email).
+		Updates(map[string]interface{}{
+			"verified_at":   time.Now(),
+			"bounced_at":    nil,
+			"bounce_reason": "",
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	_ = db.Redis.Del(ctx, codeKey, attemptsKey).Err()
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, util.Err(VerifyCodeInvalid))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// UpdateEmailSettingsRequest 更新邮件设置请求
+type UpdateEmailSettingsRequest struct {
+	OptOut *bool  `json:"opt_out"`
+	Locale string `json:"locale" binding:"omitempty,oneof=zh en"`
+}
+
+// UpdateEmailSettings 退订/恢复邮件通知或切换邮件语言
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param request body UpdateEmailSettingsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/email/settings [put]
+func UpdateEmailSettings(c *gin.Context) {
+	var req UpdateEmailSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	updates := make(map[string]interface{})
+	if req.OptOut != nil {
+		updates["opt_out"] = *req.OptOut
+	}
+	if req.Locale != "" {
+		updates["locale"] = req.Locale
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusOK, util.OKNil())
+		return
+	}
+
+	result := db.DB(c.Request.Context()).Model(&model.UserEmail{}).Where("user_id = ?", user.ID).Updates(updates)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, util.Err(EmailNotBound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/mailer"
+	"github.com/linux-do/credit/internal/model"
+)
+
+// HandleSendEmail 渲染模板并通过 SMTP 发送邮件，收件服务器永久拒收时标记退信并停止重试
+func HandleSendEmail(ctx context.Context, t *asynq.Task) error {
+	var payload sendPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	subject, body, err := mailer.Render(payload.Event, payload.Locale, payload.Data)
+	if err != nil {
+		logger.ErrorF(ctx, "渲染邮件[%s]失败: %v", payload.Event, err)
+		return fmt.Errorf("%w: %v", asynq.SkipRetry, err)
+	}
+
+	if err = mailer.Send(ctx, &mailer.Message{To: payload.To, Subject: subject, Body: body}); err != nil {
+		if !mailer.IsPermanentFailure(err) {
+			logger.ErrorF(ctx, "发送邮件[%s]给用户[%d]失败，稍后重试: %v", payload.Event, payload.UserID, err)
+			return err
+		}
+
+		// 退信后停止向该地址投递，用户重新验证邮箱后恢复
+		reason := []rune(err.Error())
+		if len(reason) > 255 {
+			reason = reason[:255]
+		}
+		if errUpdate := db.DB(ctx).Model(&model.UserEmail{}).
+			Where("user_id = ? AND email = ?", payload.UserID, payload.To).
+			Updates(map[string]interface{}{
+				"bounced_at":    time.Now(),
+				"bounce_reason": string(reason),
+			}).Error; errUpdate != nil {
+			return errUpdate
+		}
+		logger.ErrorF(ctx, "邮件[%s]被用户[%d]的收件服务器拒收，已标记退信: %v", payload.Event, payload.UserID, err)
+		return fmt.Errorf("%w: %v", asynq.SkipRetry, err)
+	}
+
+	logger.InfoF(ctx, "发送邮件[%s]给用户[%d]成功", payload.Event, payload.UserID)
+	return nil
+}
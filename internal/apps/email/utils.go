@@ -0,0 +1,100 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package email
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/mailer"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/task"
+	"github.com/linux-do/credit/internal/task/scheduler"
+	"gorm.io/gorm"
+)
+
+// sendPayload 邮件发送任务参数
+type sendPayload struct {
+	UserID uint64                 `json:"user_id"`
+	To     string                 `json:"to"`
+	Locale string                 `json:"locale"`
+	Event  string                 `json:"event"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+// Enqueue 向用户已验证的邮箱投递事件邮件，未启用邮件、未绑定、已退订或已退信时静默跳过
+func Enqueue(tx *gorm.DB, userID uint64, event string, data map[string]interface{}) error {
+	if !mailer.Enabled() {
+		return nil
+	}
+
+	var userEmail model.UserEmail
+	if err := tx.Where("user_id = ?", userID).First(&userEmail).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !userEmail.Deliverable() {
+		return nil
+	}
+
+	return enqueueSend(&sendPayload{
+		UserID: userID,
+		To:     userEmail.Email,
+		Locale: userEmail.Locale,
+		Event:  event,
+		Data:   data,
+	})
+}
+
+// enqueueSend 下发邮件发送任务到独立的邮件队列
+func enqueueSend(payload *sendPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if _, err = scheduler.AsynqClient.Enqueue(
+		asynq.NewTask(task.SendEmailTask, body),
+		asynq.Queue(task.QueueEmail),
+		asynq.MaxRetry(5),
+		asynq.Timeout(SendEmailTimeoutSeconds*time.Second),
+	); err != nil {
+		return fmt.Errorf("下发邮件任务失败: %w", err)
+	}
+	return nil
+}
+
+// generateVerifyCode 生成数字验证码
+func generateVerifyCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < VerifyCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", VerifyCodeDigits, n.Int64()), nil
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api_key
+
+const (
+	// MaxActiveSecrets 单个应用同时有效的密钥数量上限（含轮换过渡期内的旧密钥）
+	MaxActiveSecrets = 3
+	// DefaultSecretOverlapHours 轮换后旧密钥默认继续有效的小时数
+	DefaultSecretOverlapHours = 24
+)
@@ -17,6 +17,10 @@ limitations under the License.
 package api_key
 
 const (
-	APIKeyNotFound   = "API Key 不存在"
-	NoFieldsToUpdate = "没有需要更新的字段"
+	APIKeyNotFound         = "API Key 不存在"
+	NoFieldsToUpdate       = "没有需要更新的字段"
+	TooManyActiveSecrets   = "有效密钥数量已达上限，请先撤销不再使用的密钥"
+	SecretNotFound         = "密钥不存在"
+	SecretAlreadyExpired   = "密钥已失效"
+	CannotRevokeLastSecret = "不能撤销唯一有效的密钥，请先轮换"
 )
@@ -17,14 +17,20 @@ limitations under the License.
 package api_key
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/credit/internal/apps/merchant"
 	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CreateAPIKeyRequest struct {
@@ -33,6 +39,7 @@ type CreateAPIKeyRequest struct {
 	AppDescription string `json:"app_description" binding:"max=100"`
 	RedirectURI    string `json:"redirect_uri" binding:"omitempty,max=100,url"`
 	NotifyURL      string `json:"notify_url" binding:"required,max=100,url"`
+	TOTPCode       string `json:"totp_code" binding:"omitempty,max=16"`
 }
 
 type UpdateAPIKeyRequest struct {
@@ -43,6 +50,18 @@ type UpdateAPIKeyRequest struct {
 	NotifyURL      string `json:"notify_url" binding:"omitempty,max=100,url"`
 }
 
+// RotateSecretRequest 轮换密钥请求，overlap_hours 为旧密钥继续有效的小时数，0 表示立即失效
+type RotateSecretRequest struct {
+	OverlapHours *int   `json:"overlap_hours" binding:"omitempty,min=0,max=168"`
+	TOTPCode     string `json:"totp_code" binding:"omitempty,max=16"`
+}
+
+// RotateSecretResponse 轮换结果，client_secret 明文仅此一次返回
+type RotateSecretResponse struct {
+	Secret       *model.MerchantClientSecret `json:"secret"`
+	ClientSecret string                      `json:"client_secret"`
+}
+
 type APIKeyListResponse struct {
 	Total int64                  `json:"total"`
 	Data  []model.MerchantAPIKey `json:"data"`
@@ -64,10 +83,14 @@ func CreateAPIKey(c *gin.Context) {
 
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
+	// 已启用 TOTP 时创建 API Key 必须通过二次验证
+	if !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
 	apiKey := model.MerchantAPIKey{
 		UserID:         user.ID,
 		ClientID:       util.GenerateUniqueIDSimple(),
-		ClientSecret:   util.GenerateUniqueIDSimple(),
 		AppName:        req.AppName,
 		AppHomepageURL: req.AppHomepageURL,
 		AppDescription: req.AppDescription,
@@ -75,11 +98,31 @@ func CreateAPIKey(c *gin.Context) {
 		NotifyURL:      req.NotifyURL,
 	}
 
-	if err := db.DB(c.Request.Context()).Create(&apiKey).Error; err != nil {
+	var clientSecret string
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&apiKey).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if clientSecret, _, err = service.IssueClientSecret(tx, &apiKey); err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAPIKeyCreate,
+			TargetType: audit.TargetAPIKey,
+			TargetID:   apiKey.ID,
+			After:      &apiKey,
+		})
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
 
+	// 密钥明文仅在创建时返回一次
+	apiKey.ClientSecret = clientSecret
 	c.JSON(http.StatusOK, util.OK(apiKey))
 }
 
@@ -129,6 +172,7 @@ func UpdateAPIKey(c *gin.Context) {
 		return
 	}
 
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
 
 	updates := map[string]interface{}{
@@ -139,9 +183,23 @@ func UpdateAPIKey(c *gin.Context) {
 		"notify_url":       req.NotifyURL,
 	}
 
-	if err := db.DB(c.Request.Context()).
-		Model(&apiKey).
-		Updates(updates).Error; err != nil {
+	before := *apiKey
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&apiKey).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAPIKeyUpdate,
+			TargetType: audit.TargetAPIKey,
+			TargetID:   apiKey.ID,
+			Before:     &before,
+			After:      apiKey,
+		})
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
@@ -156,12 +214,199 @@ func UpdateAPIKey(c *gin.Context) {
 // @Success 200 {object} util.ResponseAny
 // @Router /api/v1/merchant/api-keys/{id} [delete]
 func DeleteAPIKey(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&apiKey).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAPIKeyDelete,
+			TargetType: audit.TargetAPIKey,
+			TargetID:   apiKey.ID,
+			Before:     apiKey,
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListSecrets 获取应用的密钥列表（不含明文）
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/secrets [get]
+func ListSecrets(c *gin.Context) {
 	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
 
-	if err := db.DB(c.Request.Context()).Delete(&apiKey).Error; err != nil {
+	var secrets []model.MerchantClientSecret
+	if err := db.DB(c.Request.Context()).
+		Where("api_key_id = ?", apiKey.ID).
+		Order("created_at DESC").
+		Find(&secrets).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
 
+	c.JSON(http.StatusOK, util.OK(secrets))
+}
+
+// RotateSecret 轮换应用密钥，过渡期内新旧密钥同时有效
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param request body RotateSecretRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/secrets/rotate [post]
+func RotateSecret(c *gin.Context) {
+	var req RotateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	// 已启用 TOTP 时轮换密钥必须通过二次验证
+	if !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
+	overlapHours := DefaultSecretOverlapHours
+	if req.OverlapHours != nil {
+		overlapHours = *req.OverlapHours
+	}
+
+	var response RotateSecretResponse
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// 锁定应用，串行化同一应用的密钥变更
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("id = ?", apiKey.ID).
+			First(&model.MerchantAPIKey{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var activeIDs []uint64
+		if err := tx.Model(&model.MerchantClientSecret{}).
+			Where("api_key_id = ?", apiKey.ID).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Pluck("id", &activeIDs).Error; err != nil {
+			return err
+		}
+		if len(activeIDs) >= MaxActiveSecrets {
+			return errors.New(TooManyActiveSecrets)
+		}
+
+		// 旧密钥在过渡期结束时失效，已更早到期的保持不变
+		overlapUntil := now.Add(time.Duration(overlapHours) * time.Hour)
+		if err := tx.Model(&model.MerchantClientSecret{}).
+			Where("api_key_id = ?", apiKey.ID).
+			Where("expires_at IS NULL OR expires_at > ?", overlapUntil).
+			Update("expires_at", overlapUntil).Error; err != nil {
+			return err
+		}
+
+		clientSecret, secret, err := service.IssueClientSecret(tx, apiKey)
+		if err != nil {
+			return err
+		}
+		response = RotateSecretResponse{Secret: secret, ClientSecret: clientSecret}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAPIKeySecretRotate,
+			TargetType: audit.TargetAPIKey,
+			TargetID:   apiKey.ID,
+			Before:     map[string]interface{}{"active_secret_ids": activeIDs},
+			After:      map[string]interface{}{"secret_id": secret.ID, "previous_expires_at": overlapUntil},
+		})
+	}); err != nil {
+		if err.Error() == TooManyActiveSecrets {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// RevokeSecret 立即撤销应用的某个密钥，至少保留一个有效密钥
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "API Key ID"
+// @Param secretId path uint64 true "密钥 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/api-keys/{id}/secrets/{secretId} [delete]
+func RevokeSecret(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, merchant.APIKeyObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("id = ?", apiKey.ID).
+			First(&model.MerchantAPIKey{}).Error; err != nil {
+			return err
+		}
+
+		var secret model.MerchantClientSecret
+		if err := tx.Where("id = ? AND api_key_id = ?", c.Param("secretId"), apiKey.ID).First(&secret).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(SecretNotFound)
+			}
+			return err
+		}
+		if !secret.Active() {
+			return errors.New(SecretAlreadyExpired)
+		}
+
+		now := time.Now()
+		var others int64
+		if err := tx.Model(&model.MerchantClientSecret{}).
+			Where("api_key_id = ? AND id <> ?", apiKey.ID, secret.ID).
+			Where("expires_at IS NULL OR expires_at > ?", now).
+			Count(&others).Error; err != nil {
+			return err
+		}
+		if others == 0 {
+			return errors.New(CannotRevokeLastSecret)
+		}
+
+		before := secret
+		if err := tx.Model(&secret).Update("expires_at", now).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionAPIKeySecretRevoke,
+			TargetType: audit.TargetAPIKey,
+			TargetID:   apiKey.ID,
+			Before:     &before,
+			After:      &secret,
+		})
+	}); err != nil {
+		switch err.Error() {
+		case SecretNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case SecretAlreadyExpired, CannotRevokeLastSecret:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
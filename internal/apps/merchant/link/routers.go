@@ -30,7 +30,9 @@ import (
 	"github.com/linux-do/credit/internal/common"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
 	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
 	"github.com/linux-do/credit/internal/task"
     "github.com/linux-do/credit/internal/task/scheduler"
 	"github.com/linux-do/credit/internal/util"
@@ -40,9 +42,10 @@ import (
 
 // PayByLinkRequest 通过支付链接支付请求
 type PayByLinkRequest struct {
-	Token  string `json:"token" binding:"required"`
-	PayKey string `json:"pay_key" binding:"required,max=6"`
-	Remark string `json:"remark" binding:"max=100"`
+	Token    string `json:"token" binding:"required"`
+	PayKey   string `json:"pay_key" binding:"required,max=6"`
+	TOTPCode string `json:"totp_code" binding:"omitempty,max=16"`
+	Remark   string `json:"remark" binding:"max=100"`
 }
 
 // CreatePaymentLinkRequest 创建支付链接请求
@@ -205,8 +208,11 @@ func PayByLink(c *gin.Context) {
 
 	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	if !currentUser.VerifyPayKey(req.PayKey) {
-		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+	if !service.CheckPayKey(c, currentUser, req.PayKey) {
+		return
+	}
+
+	if !service.CheckTOTPForAmount(c, currentUser, paymentLink.Amount, req.TOTPCode) {
 		return
 	}
 
@@ -258,7 +264,7 @@ func PayByLink(c *gin.Context) {
 			}
 
 			// 计算手续费
-			_, merchantAmount, feePercent := service.CalculateFee(paymentLink.Amount, merchantPayConfig.FeeRate)
+			fee, merchantAmount, feePercent := service.CalculateFee(paymentLink.Amount, merchantPayConfig.FeeRate)
 			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
 
 			remark := req.Remark
@@ -275,6 +281,7 @@ func PayByLink(c *gin.Context) {
 				PayeeUserID: merchantUser.ID,
 				ClientID:    merchantAPIKey.ClientID,
 				Amount:      paymentLink.Amount,
+				Fee:         fee,
 				Status:      model.OrderStatusSuccess,
 				Type:        model.OrderTypeOnline,
 				Remark:      remark,
@@ -285,6 +292,17 @@ func PayByLink(c *gin.Context) {
 				return err
 			}
 
+			// 风控评估
+			if _, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+				Scene:              risk.ScenePayment,
+				User:               currentUser,
+				CounterpartyUserID: merchantUser.ID,
+				Amount:             order.Amount,
+				ReferenceID:        &order.ID,
+			}); err != nil {
+				return err
+			}
+
 			// 扣减用户余额
 			if err := service.DeductUserBalance(tx, currentUser.ID, paymentLink.Amount); err != nil {
 				return err
@@ -309,7 +327,14 @@ func PayByLink(c *gin.Context) {
 				return fmt.Errorf("下发商户回调任务失败: %w", errTask)
 			}
 
-			return nil
+			return service.Notify(
+				tx,
+				merchantUser.ID,
+				model.NotificationCategoryPayment,
+				"收到付款",
+				fmt.Sprintf("%s 通过支付链接支付了「%s」%s", currentUser.Username, order.OrderName, order.Amount.StringFixed(2)),
+				&order.ID,
+			)
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -318,11 +343,15 @@ func PayByLink(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(common.InsufficientBalance))
 		case common.DailyLimitExceeded:
 			c.JSON(http.StatusBadRequest, util.Err(common.DailyLimitExceeded))
+		case common.RiskBlocked:
+			c.JSON(http.StatusForbidden, util.Err(common.RiskBlocked))
 		default:
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	stream.PublishBalance(c.Request.Context(), currentUser.ID, merchantUser.ID)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
@@ -0,0 +1,29 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payout
+
+const (
+	PayoutNotFound         = "批量付款不存在"
+	InvalidPayoutItems     = "批量付款明细校验失败"
+	RecipientRequired      = "必须指定收款人用户 ID 或用户名"
+	RecipientNotFound      = "收款人不存在"
+	RecipientMismatch      = "收款人用户 ID 与用户名不匹配"
+	RecipientInactive      = "收款人账号已被封禁"
+	CannotPayoutToSelf     = "不能向自己付款"
+	MerchantUserNotFound   = "商户用户不存在"
+	DuplicateIdempotentKey = "幂等键已被使用，请稍后查询批次结果"
+)
@@ -0,0 +1,203 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/apps/payment"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/task"
+	"github.com/linux-do/credit/internal/task/scheduler"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PayoutItemRequest 批量付款明细
+type PayoutItemRequest struct {
+	UserID   uint64          `json:"user_id"`
+	Username string          `json:"username" binding:"max=64"`
+	Amount   decimal.Decimal `json:"amount" binding:"required"`
+	Memo     string          `json:"memo" binding:"max=100"`
+}
+
+// CreatePayoutRequest 创建批量付款请求
+type CreatePayoutRequest struct {
+	IdempotencyKey string              `json:"idempotency_key" binding:"required,max=64"`
+	Items          []PayoutItemRequest `json:"items" binding:"required,min=1,max=500,dive"`
+}
+
+// PayoutItemError 批量付款明细校验错误
+type PayoutItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// PayoutDetail 批量付款详情
+type PayoutDetail struct {
+	model.MerchantPayout
+	Items []model.MerchantPayoutItem `json:"items"`
+}
+
+// CreatePayout 商户批量付款（Basic Auth: ClientID:ClientSecret）
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Param request body CreatePayoutRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/payouts [post]
+func CreatePayout(c *gin.Context) {
+	var req CreatePayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, payment.APIKeyObjKey)
+
+	// 幂等：相同幂等键直接返回已创建的批次
+	if detail, err := getPayoutDetail(db.DB(c.Request.Context()), apiKey.ID, "idempotency_key = ?", req.IdempotencyKey); err == nil {
+		c.JSON(http.StatusOK, util.OK(detail))
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var merchantUser model.User
+	if err := merchantUser.GetByID(db.DB(c.Request.Context()), apiKey.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(MerchantUserNotFound))
+		return
+	}
+	if err := merchantUser.CheckActive(); err != nil {
+		c.JSON(http.StatusForbidden, util.Err(err.Error()))
+		return
+	}
+
+	items, totalAmount, itemErrors, err := resolveItems(db.DB(c.Request.Context()), &merchantUser, req.Items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if len(itemErrors) > 0 {
+		c.JSON(http.StatusBadRequest, util.Response[[]PayoutItemError]{ErrorMsg: InvalidPayoutItems, Data: itemErrors})
+		return
+	}
+
+	payout := model.MerchantPayout{
+		MerchantAPIKeyID: apiKey.ID,
+		IdempotencyKey:   req.IdempotencyKey,
+		MerchantUserID:   merchantUser.ID,
+		TotalAmount:      totalAmount,
+		ItemCount:        len(items),
+		RefundedAmount:   decimal.Zero,
+		Status:           model.MerchantPayoutStatusProcessing,
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 一次性扣减商户余额
+			if err := service.DebitTransferPayer(tx, merchantUser.ID, totalAmount); err != nil {
+				return err
+			}
+
+			if err := tx.Create(&payout).Error; err != nil {
+				return err
+			}
+
+			// 风控评估，批量付款按批次总金额评估
+			if _, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+				Scene:       risk.ScenePayout,
+				User:        &merchantUser,
+				Amount:      totalAmount,
+				ReferenceID: &payout.ID,
+			}); err != nil {
+				return err
+			}
+
+			for i := range items {
+				items[i].PayoutID = payout.ID
+			}
+			if err := tx.CreateInBatches(&items, 100).Error; err != nil {
+				return err
+			}
+
+			payload, _ := json.Marshal(map[string]interface{}{
+				"payout_id": payout.ID,
+			})
+			if _, errTask := scheduler.AsynqClient.Enqueue(
+				asynq.NewTask(task.ProcessMerchantPayoutTask, payload),
+				asynq.MaxRetry(10),
+			); errTask != nil {
+				return fmt.Errorf("下发批量付款任务失败: %w", errTask)
+			}
+
+			return nil
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch {
+		case errMsg == common.InsufficientBalance:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case errMsg == common.RiskBlocked:
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		case strings.Contains(errMsg, "SQLSTATE 23505"):
+			c.JSON(http.StatusConflict, util.Err(DuplicateIdempotentKey))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	stream.PublishBalance(c.Request.Context(), merchantUser.ID)
+
+	c.JSON(http.StatusOK, util.OK(PayoutDetail{MerchantPayout: payout, Items: items}))
+}
+
+// GetPayout 查询批量付款批次及逐条状态（Basic Auth: ClientID:ClientSecret）
+// @Tags merchant
+// @Produce json
+// @Param id path uint64 true "批次 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/merchant/payouts/{id} [get]
+func GetPayout(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, payment.APIKeyObjKey)
+
+	detail, err := getPayoutDetail(db.DB(c.Request.Context()), apiKey.ID, "id = ?", c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(PayoutNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(detail))
+}
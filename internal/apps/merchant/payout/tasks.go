@@ -0,0 +1,186 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleProcessMerchantPayout 逐条处理批量付款明细
+// 每条明细独立事务：成功则创建转账子订单并为收款人入账，失败则将金额退回商户
+func HandleProcessMerchantPayout(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		PayoutID uint64 `json:"payout_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	var payout model.MerchantPayout
+	if err := db.DB(ctx).Where("id = ?", payload.PayoutID).First(&payout).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.ErrorF(ctx, "批量付款[ID:%d]不存在", payload.PayoutID)
+			return nil
+		}
+		return err
+	}
+	if payout.Status == model.MerchantPayoutStatusCompleted {
+		return nil
+	}
+
+	var merchantAPIKey model.MerchantAPIKey
+	if err := db.DB(ctx).Unscoped().Where("id = ?", payout.MerchantAPIKeyID).First(&merchantAPIKey).Error; err != nil {
+		return err
+	}
+
+	var items []model.MerchantPayoutItem
+	if err := db.DB(ctx).
+		Where("payout_id = ? AND status = ?", payout.ID, model.MerchantPayoutItemStatusPending).
+		Order("seq ASC").
+		Find(&items).Error; err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := processItem(ctx, &payout, &merchantAPIKey, item.ID); err != nil {
+			logger.ErrorF(ctx, "处理批量付款[ID:%d]明细[ID:%d]失败: %v", payout.ID, item.ID, err)
+			return err
+		}
+	}
+
+	return finishPayout(ctx, payout.ID)
+}
+
+// processItem 处理单条付款明细，提交后推送余额变化（入账通知收款人，退回通知商户）
+func processItem(ctx context.Context, payout *model.MerchantPayout, merchantAPIKey *model.MerchantAPIKey, itemID uint64) error {
+	var balanceUserID uint64
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var item model.MerchantPayoutItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", itemID, model.MerchantPayoutItemStatusPending).
+			First(&item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		var recipient model.User
+		failureReason := ""
+		if err := recipient.GetByID(tx, item.RecipientUserID); err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			failureReason = RecipientNotFound
+		} else if !recipient.IsActive {
+			failureReason = RecipientInactive
+		}
+
+		// 收款人不可用，退回商户
+		if failureReason != "" {
+			if err := service.RefundTransferPayer(tx, payout.MerchantUserID, item.Amount); err != nil {
+				return err
+			}
+			balanceUserID = payout.MerchantUserID
+			return tx.Model(&item).Updates(map[string]interface{}{
+				"status":         model.MerchantPayoutItemStatusFailed,
+				"failure_reason": failureReason,
+			}).Error
+		}
+
+		now := time.Now()
+		order := model.Order{
+			OrderName:   PayoutOrderName,
+			ClientID:    merchantAPIKey.ClientID,
+			PayerUserID: payout.MerchantUserID,
+			PayeeUserID: recipient.ID,
+			Amount:      item.Amount,
+			Status:      model.OrderStatusSuccess,
+			Type:        model.OrderTypeTransfer,
+			Remark:      item.Memo,
+			TradeTime:   now,
+			ExpiresAt:   now.Add(24 * time.Hour),
+		}
+		if err := service.CreditTransferPayee(tx, &order); err != nil {
+			return err
+		}
+		balanceUserID = recipient.ID
+
+		return tx.Model(&item).Updates(map[string]interface{}{
+			"status":   model.MerchantPayoutItemStatusSuccess,
+			"order_id": order.ID,
+		}).Error
+	}); err != nil {
+		return err
+	}
+
+	if balanceUserID != 0 {
+		stream.PublishBalance(ctx, balanceUserID)
+	}
+	return nil
+}
+
+// finishPayout 汇总明细状态并完成批次
+func finishPayout(ctx context.Context, payoutID uint64) error {
+	var stats []struct {
+		Status model.MerchantPayoutItemStatus
+		Count  int
+		Amount decimal.Decimal
+	}
+	if err := db.DB(ctx).Model(&model.MerchantPayoutItem{}).
+		Select("status, COUNT(*) as count, COALESCE(SUM(amount), 0) as amount").
+		Where("payout_id = ?", payoutID).
+		Group("status").
+		Scan(&stats).Error; err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"success_count":   0,
+		"failed_count":    0,
+		"refunded_amount": decimal.Zero,
+		"status":          model.MerchantPayoutStatusCompleted,
+	}
+	for _, stat := range stats {
+		switch stat.Status {
+		case model.MerchantPayoutItemStatusSuccess:
+			updates["success_count"] = stat.Count
+		case model.MerchantPayoutItemStatusFailed:
+			updates["failed_count"] = stat.Count
+			updates["refunded_amount"] = stat.Amount
+		case model.MerchantPayoutItemStatusPending:
+			// 仍有未处理的明细，保持处理中
+			updates["status"] = model.MerchantPayoutStatusProcessing
+		}
+	}
+
+	return db.DB(ctx).Model(&model.MerchantPayout{}).Where("id = ?", payoutID).Updates(updates).Error
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payout
+
+import (
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// getPayoutDetail 查询商户的批量付款批次及明细
+func getPayoutDetail(tx *gorm.DB, apiKeyID uint64, query string, args ...interface{}) (*PayoutDetail, error) {
+	var detail PayoutDetail
+	if err := tx.Where("merchant_api_key_id = ?", apiKeyID).
+		Where(query, args...).
+		First(&detail.MerchantPayout).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("payout_id = ?", detail.ID).
+		Order("seq ASC").
+		Find(&detail.Items).Error; err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// resolveItems 校验付款明细并解析收款人，返回待创建的明细、总金额与校验错误
+func resolveItems(tx *gorm.DB, merchantUser *model.User, reqItems []PayoutItemRequest) ([]model.MerchantPayoutItem, decimal.Decimal, []PayoutItemError, error) {
+	var itemErrors []PayoutItemError
+	totalAmount := decimal.Zero
+
+	// 批量查询收款人
+	var userIDs []uint64
+	var usernames []string
+	for _, item := range reqItems {
+		if item.UserID != 0 {
+			userIDs = append(userIDs, item.UserID)
+		} else if item.Username != "" {
+			usernames = append(usernames, item.Username)
+		}
+	}
+
+	var users []model.User
+	query := tx.Model(&model.User{})
+	switch {
+	case len(userIDs) > 0 && len(usernames) > 0:
+		query = query.Where("id IN ? OR username IN ?", userIDs, usernames)
+	case len(userIDs) > 0:
+		query = query.Where("id IN ?", userIDs)
+	default:
+		query = query.Where("username IN ?", usernames)
+	}
+	if len(userIDs) > 0 || len(usernames) > 0 {
+		if err := query.Find(&users).Error; err != nil {
+			return nil, decimal.Zero, nil, err
+		}
+	}
+
+	usersByID := make(map[uint64]*model.User, len(users))
+	usersByName := make(map[string]*model.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+		usersByName[users[i].Username] = &users[i]
+	}
+
+	items := make([]model.MerchantPayoutItem, 0, len(reqItems))
+	for index, item := range reqItems {
+		addError := func(msg string) {
+			itemErrors = append(itemErrors, PayoutItemError{Index: index, Error: msg})
+		}
+
+		if item.Amount.LessThanOrEqual(decimal.Zero) {
+			addError(common.AmountMustBeGreaterThanZero)
+			continue
+		}
+		if item.Amount.Exponent() < -2 {
+			addError(common.AmountDecimalPlacesExceeded)
+			continue
+		}
+
+		var recipient *model.User
+		switch {
+		case item.UserID != 0:
+			recipient = usersByID[item.UserID]
+			if recipient != nil && item.Username != "" && recipient.Username != item.Username {
+				addError(RecipientMismatch)
+				continue
+			}
+		case item.Username != "":
+			recipient = usersByName[item.Username]
+		default:
+			addError(RecipientRequired)
+			continue
+		}
+
+		if recipient == nil {
+			addError(RecipientNotFound)
+			continue
+		}
+		if !recipient.IsActive {
+			addError(RecipientInactive)
+			continue
+		}
+		if recipient.ID == merchantUser.ID {
+			addError(CannotPayoutToSelf)
+			continue
+		}
+
+		totalAmount = totalAmount.Add(item.Amount)
+		items = append(items, model.MerchantPayoutItem{
+			Seq:               index,
+			RecipientUserID:   recipient.ID,
+			RecipientUsername: recipient.Username,
+			Amount:            item.Amount,
+			Memo:              item.Memo,
+			Status:            model.MerchantPayoutItemStatusPending,
+		})
+	}
+
+	return items, totalAmount, itemErrors, nil
+}
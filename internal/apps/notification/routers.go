@@ -0,0 +1,253 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm/clause"
+)
+
+// ListNotificationsRequest 查询通知列表请求
+type ListNotificationsRequest struct {
+	Page       int    `json:"page" form:"page" binding:"min=1"`
+	PageSize   int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Category   string `json:"category" form:"category" binding:"omitempty,max=32"`
+	UnreadOnly bool   `json:"unread_only" form:"unread_only"`
+}
+
+// ListNotificationsResponse 查询通知列表响应
+type ListNotificationsResponse struct {
+	Total         int64                `json:"total"`
+	Page          int                  `json:"page"`
+	PageSize      int                  `json:"page_size"`
+	Notifications []model.Notification `json:"notifications"`
+}
+
+// ListNotifications 查询当前用户的站内通知
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param request body ListNotificationsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notification/list [post]
+func ListNotifications(c *gin.Context) {
+	var req ListNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.Notification{}).Where("user_id = ?", user.ID)
+	if req.Category != "" {
+		baseQuery = baseQuery.Where("category = ?", model.NotificationCategory(req.Category))
+	}
+	if req.UnreadOnly {
+		baseQuery = baseQuery.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListNotificationsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// UnreadCountResponse 未读通知数量响应
+type UnreadCountResponse struct {
+	Total      int64                                `json:"total"`
+	Categories map[model.NotificationCategory]int64 `json:"categories"`
+}
+
+// GetUnreadCount 查询当前用户的未读通知数量（含各分类数量）
+// @Tags notification
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notification/unread-count [get]
+func GetUnreadCount(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var rows []struct {
+		Category model.NotificationCategory
+		Count    int64
+	}
+	if err := db.DB(c.Request.Context()).Model(&model.Notification{}).
+		Select("category, COUNT(*) AS count").
+		Where("user_id = ? AND read_at IS NULL", user.ID).
+		Group("category").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &UnreadCountResponse{Categories: make(map[model.NotificationCategory]int64, len(rows))}
+	for _, row := range rows {
+		response.Categories[row.Category] = row.Count
+		response.Total += row.Count
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// MarkReadRequest 标记已读请求，ids 为空时标记全部（可按分类）为已读
+type MarkReadRequest struct {
+	IDs      []uint64 `json:"ids" binding:"max=100"`
+	Category string   `json:"category" binding:"omitempty,max=32"`
+}
+
+// MarkRead 将当前用户的通知标记为已读
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param request body MarkReadRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notification/read [post]
+func MarkRead(c *gin.Context) {
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	query := db.DB(c.Request.Context()).Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", user.ID)
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+	if req.Category != "" {
+		query = query.Where("category = ?", model.NotificationCategory(req.Category))
+	}
+
+	if err := query.Update("read_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// PreferenceItem 通知分类的接收偏好
+type PreferenceItem struct {
+	Category  model.NotificationCategory `json:"category"`
+	Enabled   bool                       `json:"enabled"`
+	Mandatory bool                       `json:"mandatory"`
+}
+
+// ListPreferences 查询当前用户各分类通知的接收偏好
+// @Tags notification
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notification/preferences [get]
+func ListPreferences(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var preferences []model.NotificationPreference
+	if err := db.DB(c.Request.Context()).Where("user_id = ?", user.ID).Find(&preferences).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	enabled := make(map[model.NotificationCategory]bool, len(preferences))
+	for _, preference := range preferences {
+		enabled[preference.Category] = preference.Enabled
+	}
+
+	items := make([]PreferenceItem, 0, len(model.NotificationCategories))
+	for _, category := range model.NotificationCategories {
+		_, mandatory := mandatoryCategories[category]
+		item := PreferenceItem{Category: category, Enabled: true, Mandatory: mandatory}
+		if value, ok := enabled[category]; ok && !mandatory {
+			item.Enabled = value
+		}
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, util.OK(items))
+}
+
+// UpdatePreferenceRequest 更新通知偏好请求
+type UpdatePreferenceRequest struct {
+	Category string `json:"category" binding:"required,max=32"`
+	Enabled  *bool  `json:"enabled" binding:"required"`
+}
+
+// UpdatePreference 开启或关闭某一分类的通知
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param request body UpdatePreferenceRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/notification/preferences [put]
+func UpdatePreference(c *gin.Context) {
+	var req UpdatePreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	category := model.NotificationCategory(req.Category)
+	if !slices.Contains(model.NotificationCategories, category) {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidCategory))
+		return
+	}
+	if _, ok := mandatoryCategories[category]; ok && !*req.Enabled {
+		c.JSON(http.StatusBadRequest, util.Err(CategoryMandatory))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	preference := model.NotificationPreference{
+		UserID:   user.ID,
+		Category: category,
+		Enabled:  *req.Enabled,
+	}
+	if err := db.DB(c.Request.Context()).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preference).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(preference))
+}
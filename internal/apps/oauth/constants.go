@@ -24,6 +24,22 @@ const (
 	UserNameKey = "username"
 	UserIDKey   = "user_id"
 	UserObjKey  = "user_obj"
+	LoginAtKey  = "login_at"
+
+	// AccessTokenObjKey 使用个人访问令牌认证时，上下文中的令牌对象
+	AccessTokenObjKey = "access_token_obj"
+)
+
+const (
+	// AccessTokenPrefix 个人访问令牌前缀，便于识别与泄露扫描
+	AccessTokenPrefix = "ldc_pat_"
+	// accessTokenTouchInterval 最近使用时间的最小更新间隔，避免每次请求都写库
+	accessTokenTouchInterval = time.Minute
+)
+
+const (
+	// SessionRevokedAtKeyFormat 用户会话失效时间（毫秒时间戳），早于该时间登录的会话均视为失效
+	SessionRevokedAtKeyFormat = "oauth:session_revoked_at:%d"
 )
 
 const (
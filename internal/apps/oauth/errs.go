@@ -21,4 +21,8 @@ const (
 	InvalidState        = "非法登录请求"
 	IDTokenVerifyFailed = "ID Token 验证失败"
 	NonceMismatch       = "nonce 不匹配，可能存在重放攻击"
+
+	InvalidAccessToken         = "访问令牌无效或已过期"
+	AccessTokenRouteNotAllowed = "该接口不支持使用访问令牌调用"
+	AccessTokenScopeDenied     = "访问令牌缺少该接口所需的权限范围"
 )
@@ -22,14 +22,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/credit/internal/common"
 	"github.com/linux-do/credit/internal/config"
 	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
 	"github.com/linux-do/credit/internal/model"
 	"github.com/linux-do/credit/internal/otel_trace"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel/codes"
 	"gorm.io/gorm"
 )
@@ -47,6 +53,93 @@ func GetUserIDFromContext(c *gin.Context) uint64 {
 	return GetUserIDFromSession(session)
 }
 
+// RevokeUserSessions 使用户在此之前登录的所有会话立即失效，并撤销全部个人访问令牌
+func RevokeUserSessions(ctx context.Context, userID uint64) error {
+	if err := db.Redis.Set(
+		ctx,
+		db.PrefixedKey(fmt.Sprintf(SessionRevokedAtKeyFormat, userID)),
+		time.Now().UnixMilli(),
+		time.Duration(config.Config.App.SessionAge)*time.Second,
+	).Err(); err != nil {
+		return err
+	}
+
+	return db.DB(ctx).
+		Model(&model.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// bearerToken 从 Authorization 头中取出个人访问令牌
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || !strings.HasPrefix(token, AccessTokenPrefix) {
+		return "", false
+	}
+	return token, true
+}
+
+// authenticateAccessToken 校验个人访问令牌及其是否拥有 scope 权限范围，失败时直接中止请求
+func authenticateAccessToken(c *gin.Context, rawToken string, scope model.TokenScope) (*model.User, bool) {
+	ctx := c.Request.Context()
+
+	var token model.PersonalAccessToken
+	if err := db.DB(ctx).Where("token_hash = ?", model.HashAccessToken(rawToken)).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error_msg": InvalidAccessToken, "data": nil})
+		} else {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error(), "data": nil})
+		}
+		return nil, false
+	}
+	if !token.Active() {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error_msg": InvalidAccessToken, "data": nil})
+		return nil, false
+	}
+
+	if !token.HasScope(scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error_msg": AccessTokenScopeDenied, "data": nil})
+		return nil, false
+	}
+
+	var user model.User
+	if err := db.DB(ctx).Where("id = ? AND is_active = ?", token.UserID, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error_msg": InvalidAccessToken, "data": nil})
+		} else {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error(), "data": nil})
+		}
+		return nil, false
+	}
+
+	// 记录最近使用时间与 IP，间隔内的重复请求不再写库
+	now := time.Now()
+	if err := db.DB(ctx).
+		Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", token.ID, now.Add(-accessTokenTouchInterval)).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": c.ClientIP()}).Error; err != nil {
+		logger.ErrorF(ctx, "更新访问令牌[%d]最近使用时间失败: %v", token.ID, err)
+	}
+
+	util.SetToContext(c, AccessTokenObjKey, &token)
+	return &user, true
+}
+
+// isSessionRevoked 检查会话是否已被管理员强制失效
+func isSessionRevoked(c *gin.Context, userID uint64) (bool, error) {
+	revokedAt, err := db.Redis.Get(c.Request.Context(), db.PrefixedKey(fmt.Sprintf(SessionRevokedAtKeyFormat, userID))).Int64()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// 登录时间与失效时间均为毫秒时间戳，失效后同一秒内重新登录的会话不受影响
+	loginAt, _ := sessions.Default(c).Get(LoginAtKey).(int64)
+	return loginAt < revokedAt, nil
+}
+
 // doOAuth 执行 OAuth2/OIDC 认证流程
 func doOAuth(ctx context.Context, code string, nonce string) (*model.User, error) {
 	ctx, span := otel_trace.Start(ctx, "OAuth")
@@ -19,6 +19,7 @@ package oauth
 import (
 	"net/http"
 
+	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
@@ -27,12 +28,40 @@ import (
 	"github.com/linux-do/credit/internal/util"
 )
 
+// LoginRequired 要求浏览器会话登录，不接受个人访问令牌
 func LoginRequired() gin.HandlerFunc {
+	return loginRequired(nil)
+}
+
+// LoginOrAccessTokenRequired 要求浏览器会话登录，或携带拥有 scope 权限范围的个人访问令牌
+// 只有在路由上显式使用该中间件的接口才允许个人访问令牌调用
+func LoginOrAccessTokenRequired(scope model.TokenScope) gin.HandlerFunc {
+	return loginRequired(&scope)
+}
+
+// loginRequired scope 为空时仅接受会话，否则同时接受拥有该权限范围的个人访问令牌
+func loginRequired(scope *model.TokenScope) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// init trace
 		ctx, span := otel_trace.Start(c.Request.Context(), "LoginRequired")
 		defer span.End()
 
+		// 携带 Bearer 令牌时按个人访问令牌认证，不再回退到会话
+		if rawToken, ok := bearerToken(c); ok {
+			if scope == nil {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error_msg": AccessTokenRouteNotAllowed, "data": nil})
+				return
+			}
+			user, ok := authenticateAccessToken(c, rawToken, *scope)
+			if !ok {
+				return
+			}
+			logger.InfoF(ctx, "[LoginRequired] %d %s via access token", user.ID, user.Username)
+			util.SetToContext(c, UserObjKey, user)
+			c.Next()
+			return
+		}
+
 		// load user
 		userId := GetUserIDFromContext(c)
 		if userId <= 0 {
@@ -40,6 +69,20 @@ func LoginRequired() gin.HandlerFunc {
 			return
 		}
 
+		// check session revoked by admin
+		revoked, err := isSessionRevoked(c, userId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error_msg": err.Error(), "data": nil})
+			return
+		}
+		if revoked {
+			session := sessions.Default(c)
+			session.Clear()
+			_ = session.Save()
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error_msg": UnAuthorized, "data": nil})
+			return
+		}
+
 		// load user from db to make sure is active
 		var user model.User
 		tx := db.DB(ctx).Where("id = ? AND is_active = ?", userId, true).First(&user)
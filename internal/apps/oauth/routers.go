@@ -19,6 +19,7 @@ package oauth
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-contrib/sessions"
@@ -98,6 +99,7 @@ func Callback(c *gin.Context) {
 	session := sessions.Default(c)
 	session.Set(UserIDKey, user.ID)
 	session.Set(UserNameKey, user.Username)
+	session.Set(LoginAtKey, time.Now().UnixMilli())
 	if err := session.Save(); err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
@@ -107,23 +109,29 @@ func Callback(c *gin.Context) {
 }
 
 type BasicUserInfo struct {
-	ID               uint64           `json:"id"`
-	Username         string           `json:"username"`
-	Nickname         string           `json:"nickname"`
-	TrustLevel       model.TrustLevel `json:"trust_level"`
-	AvatarUrl        string           `json:"avatar_url"`
-	TotalReceive     decimal.Decimal  `json:"total_receive"`
-	TotalPayment     decimal.Decimal  `json:"total_payment"`
-	TotalTransfer    decimal.Decimal  `json:"total_transfer"`
-	TotalCommunity   decimal.Decimal  `json:"total_community"`
-	CommunityBalance decimal.Decimal  `json:"community_balance"`
-	AvailableBalance decimal.Decimal  `json:"available_balance"`
-	PayScore         int64            `json:"pay_score"`
-	IsPayKey         bool             `json:"is_pay_key"`
-	IsAdmin          bool             `json:"is_admin"`
-	RemainQuota      decimal.Decimal  `json:"remain_quota"`
-	PayLevel         model.PayLevel   `json:"pay_level"`
-	DailyLimit       *int64           `json:"daily_limit"`
+	ID               uint64            `json:"id"`
+	Username         string            `json:"username"`
+	Nickname         string            `json:"nickname"`
+	TrustLevel       model.TrustLevel  `json:"trust_level"`
+	AvatarUrl        string            `json:"avatar_url"`
+	TotalReceive     decimal.Decimal   `json:"total_receive"`
+	TotalPayment     decimal.Decimal   `json:"total_payment"`
+	TotalTransfer    decimal.Decimal   `json:"total_transfer"`
+	TotalCommunity   decimal.Decimal   `json:"total_community"`
+	CommunityBalance decimal.Decimal   `json:"community_balance"`
+	AvailableBalance decimal.Decimal   `json:"available_balance"`
+	PayScore         int64             `json:"pay_score"`
+	IsPayKey         bool              `json:"is_pay_key"`
+	IsAdmin          bool              `json:"is_admin"`
+	Roles            []model.AdminRole `json:"roles"`
+	RemainQuota      decimal.Decimal   `json:"remain_quota"`
+	PayLevel         model.PayLevel    `json:"pay_level"`
+	DailyLimit       *int64            `json:"daily_limit"`
+
+	RemainTransferQuota  decimal.Decimal `json:"remain_transfer_quota"`
+	DailyTransferLimit   *int64          `json:"daily_transfer_limit"`
+	MonthlyTransferLimit *int64          `json:"monthly_transfer_limit"`
+	MaxTransferAmount    *int64          `json:"max_transfer_amount"`
 }
 
 // UserInfo godoc
@@ -151,6 +159,19 @@ func UserInfo(c *gin.Context) {
 		remainQuota = decimal.NewFromInt(*payConfig.DailyLimit).Sub(todayUsed)
 	}
 
+	// 计算剩余转账额度（-1 表示无限额）
+	transferQuota, err := service.GetTransferQuota(db.DB(c.Request.Context()), user.ID, &payConfig)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	roles, err := user.GetRoles(db.DB(c.Request.Context()))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
 	c.JSON(
 		http.StatusOK,
 		util.OK(BasicUserInfo{
@@ -168,9 +189,15 @@ func UserInfo(c *gin.Context) {
 			PayScore:         user.PayScore,
 			IsPayKey:         user.PayKey != "",
 			IsAdmin:          user.IsAdmin,
+			Roles:            roles,
 			RemainQuota:      remainQuota,
 			PayLevel:         payConfig.Level,
 			DailyLimit:       payConfig.DailyLimit,
+
+			RemainTransferQuota:  transferQuota.RemainingQuota,
+			DailyTransferLimit:   payConfig.DailyTransferLimit,
+			MonthlyTransferLimit: payConfig.MonthlyTransferLimit,
+			MaxTransferAmount:    payConfig.MaxTransferAmount,
 		}),
 	)
 }
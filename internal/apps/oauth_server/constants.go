@@ -0,0 +1,49 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth_server
+
+import "time"
+
+const (
+	// AuthorizationCodeKeyFormat 授权码在 Redis 中的存储 key，授权码只能使用一次
+	AuthorizationCodeKeyFormat = "oauth2:code:%s"
+	AuthorizationCodeTTL       = 10 * time.Minute
+
+	AccessTokenTTL  = time.Hour
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	// AccessTokenPrefix / RefreshTokenPrefix 令牌前缀，便于识别与泄露扫描
+	AccessTokenPrefix  = "ldc_oat_"
+	RefreshTokenPrefix = "ldc_ort_"
+
+	tokenRandomBytes = 32
+)
+
+const (
+	AuthorizationObjKey = "oauth2_authorization_obj"
+	ClientObjKey        = "oauth2_client_obj"
+)
+
+// OAuth2 标准错误码（RFC 6749）
+const (
+	ErrInvalidRequest       = "invalid_request"
+	ErrInvalidClient        = "invalid_client"
+	ErrInvalidGrant         = "invalid_grant"
+	ErrUnsupportedGrantType = "unsupported_grant_type"
+	ErrAccessDenied         = "access_denied"
+	ErrServerError          = "server_error"
+)
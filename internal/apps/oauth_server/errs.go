@@ -0,0 +1,39 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth_server
+
+const (
+	ClientNotFound         = "应用不存在"
+	RedirectURIMismatch    = "回调地址与应用配置不一致"
+	InvalidScope           = "无效的权限范围"
+	CannotAuthorizeOwnApp  = "不能授权自己的应用"
+	SpendCapRequired       = "授权免密扣款时必须设置扣款额度"
+	InvalidSpendCap        = "扣款额度必须大于0且最多保留两位小数"
+	PayKeyRequired         = "授权免密扣款时需要验证支付密码"
+	AuthorizationNotFound  = "授权记录不存在"
+	AuthorizationRevoked   = "授权已撤销"
+	InvalidAuthCode        = "授权码无效或已过期"
+	PKCEVerifyFailed       = "code_verifier 校验失败"
+	InvalidRefreshToken    = "刷新令牌无效或已过期"
+	ClientAuthFailed       = "应用认证失败"
+	InvalidAccessToken     = "访问令牌无效或已过期"
+	InsufficientScope      = "访问令牌缺少该接口所需的权限范围"
+	SpendCapExceeded       = "超出用户授权的扣款额度"
+	DuplicateMerchantOrder = "商户订单号已存在且金额不一致"
+	MerchantNotFound       = "商户不存在"
+	MerchantUnavailable    = "商户账户不可用"
+)
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth_server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+// RequireAccessToken 校验商户应用代表用户调用时携带的 OAuth2 访问令牌及所需权限范围
+func RequireAccessToken(scope model.OAuthScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, rawToken, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || !strings.HasPrefix(rawToken, AccessTokenPrefix) {
+			abortInvalidToken(c)
+			return
+		}
+
+		tx := db.DB(c.Request.Context())
+
+		var token model.OAuthToken
+		if err := tx.Where("access_token_hash = ?", model.HashAccessToken(rawToken)).First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				abortInvalidToken(c)
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(err.Error()))
+			}
+			return
+		}
+		if token.RevokedAt != nil || !token.AccessExpiresAt.After(time.Now()) {
+			abortInvalidToken(c)
+			return
+		}
+
+		var authorization model.OAuthAuthorization
+		if err := tx.Where("id = ? AND revoked_at IS NULL", token.AuthorizationID).First(&authorization).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				abortInvalidToken(c)
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, util.Err(err.Error()))
+			}
+			return
+		}
+		if !authorization.HasScope(scope) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+string(scope)+`"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, util.Err(InsufficientScope))
+			return
+		}
+
+		var apiKey model.MerchantAPIKey
+		if err := apiKey.GetByID(tx, authorization.MerchantAPIKeyID); err != nil {
+			abortInvalidToken(c)
+			return
+		}
+
+		var user model.User
+		if err := tx.Where("id = ? AND is_active = ?", authorization.UserID, true).First(&user).Error; err != nil {
+			abortInvalidToken(c)
+			return
+		}
+
+		util.SetToContext(c, AuthorizationObjKey, &authorization)
+		util.SetToContext(c, ClientObjKey, &apiKey)
+		util.SetToContext(c, oauth.UserObjKey, &user)
+
+		c.Next()
+	}
+}
+
+// abortInvalidToken 返回访问令牌无效响应
+func abortInvalidToken(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, util.Err(InvalidAccessToken))
+}
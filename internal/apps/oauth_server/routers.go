@@ -0,0 +1,744 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth_server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/task"
+	"github.com/linux-do/credit/internal/task/scheduler"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorizeRequest 授权请求参数（授权码模式 + PKCE，仅支持 S256）
+type AuthorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required,eq=code"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required,max=64"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required,max=100"`
+	Scope               string `form:"scope" json:"scope" binding:"required,max=255"`
+	State               string `form:"state" json:"state" binding:"max=255"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required,min=43,max=128"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required,eq=S256"`
+}
+
+// ConsentResponse 授权确认页展示信息
+type ConsentResponse struct {
+	AppName        string                    `json:"app_name"`
+	AppHomepageURL string                    `json:"app_homepage_url"`
+	AppDescription string                    `json:"app_description"`
+	Scopes         []string                  `json:"scopes"`
+	Authorization  *model.OAuthAuthorization `json:"authorization"`
+}
+
+// ApproveRequest 用户确认或拒绝授权请求，授权免密扣款时需设置额度并验证支付密码
+type ApproveRequest struct {
+	AuthorizeRequest
+	Approve  *bool            `json:"approve" binding:"required"`
+	SpendCap *decimal.Decimal `json:"spend_cap"`
+	PayKey   string           `json:"pay_key" binding:"omitempty,max=6"`
+	TOTPCode string           `json:"totp_code" binding:"omitempty,max=16"`
+}
+
+// ApproveResponse 授权结果，前端跳转至 redirect_url
+type ApproveResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+// TokenRequest 令牌请求，客户端凭证可通过 Basic Auth 或表单传递
+type TokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// TokenResponse 令牌响应（RFC 6749）
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// UserInfoResponse 授权用户基本信息
+type UserInfoResponse struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	Nickname  string `json:"nickname"`
+	AvatarUrl string `json:"avatar_url"`
+}
+
+// BalanceResponse 授权用户余额信息
+type BalanceResponse struct {
+	AvailableBalance decimal.Decimal `json:"available_balance"`
+	RemainingSpend   decimal.Decimal `json:"remaining_spend"`
+}
+
+// ChargeRequest 商户免密扣款请求，同一 merchant_order_no 重复请求返回首次扣款结果
+type ChargeRequest struct {
+	Amount          decimal.Decimal `json:"amount" binding:"required"`
+	OrderName       string          `json:"order_name" binding:"required,max=64"`
+	MerchantOrderNo string          `json:"merchant_order_no" binding:"required,max=64"`
+	Remark          string          `json:"remark" binding:"max=100"`
+}
+
+// GetConsent 校验授权请求并返回授权确认页所需信息
+// @Tags oauth2
+// @Produce json
+// @Param request query AuthorizeRequest true "授权请求参数"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/authorize [get]
+func GetConsent(c *gin.Context) {
+	var req AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, scopes, errMsg := validateAuthorizeRequest(c, &req)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	response := ConsentResponse{
+		AppName:        apiKey.AppName,
+		AppHomepageURL: apiKey.AppHomepageURL,
+		AppDescription: apiKey.AppDescription,
+		Scopes:         scopes,
+	}
+
+	var authorization model.OAuthAuthorization
+	if err := db.DB(c.Request.Context()).
+		Where("user_id = ? AND merchant_api_key_id = ? AND revoked_at IS NULL", user.ID, apiKey.ID).
+		First(&authorization).Error; err == nil {
+		response.Authorization = &authorization
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// Authorize 用户确认或拒绝授权，确认后签发一次性授权码
+// @Tags oauth2
+// @Accept json
+// @Produce json
+// @Param request body ApproveRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/authorize [post]
+func Authorize(c *gin.Context) {
+	var req ApproveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	apiKey, scopes, errMsg := validateAuthorizeRequest(c, &req.AuthorizeRequest)
+	if errMsg != "" {
+		c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		return
+	}
+
+	if !*req.Approve {
+		redirectURL, err := buildRedirectURL(req.RedirectURI, map[string]string{"error": ErrAccessDenied, "state": req.State})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, util.Err(RedirectURIMismatch))
+			return
+		}
+		c.JSON(http.StatusOK, util.OK(ApproveResponse{RedirectURL: redirectURL}))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if apiKey.UserID == user.ID {
+		c.JSON(http.StatusBadRequest, util.Err(CannotAuthorizeOwnApp))
+		return
+	}
+
+	// 授权免密扣款等同于预先确认一笔额度内的支付，需验证支付密码与 TOTP
+	spendCap := decimal.Zero
+	if strings.Contains(" "+strings.Join(scopes, " ")+" ", " "+string(model.OAuthScopeChargeLimited)+" ") {
+		if req.SpendCap == nil {
+			c.JSON(http.StatusBadRequest, util.Err(SpendCapRequired))
+			return
+		}
+		if !req.SpendCap.IsPositive() || req.SpendCap.Exponent() < -2 {
+			c.JSON(http.StatusBadRequest, util.Err(InvalidSpendCap))
+			return
+		}
+		if req.PayKey == "" {
+			c.JSON(http.StatusBadRequest, util.Err(PayKeyRequired))
+			return
+		}
+		if !service.CheckPayKey(c, user, req.PayKey) {
+			return
+		}
+		if !service.CheckTOTPForAmount(c, user, *req.SpendCap, req.TOTPCode) {
+			return
+		}
+		spendCap = *req.SpendCap
+	}
+
+	code, err := generateToken("")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var authorization model.OAuthAuthorization
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND merchant_api_key_id = ?", user.ID, apiKey.ID).
+			First(&authorization).Error
+		before := authorization
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			authorization = model.OAuthAuthorization{
+				UserID:           user.ID,
+				MerchantAPIKeyID: apiKey.ID,
+				Scopes:           strings.Join(scopes, " "),
+				SpendCap:         spendCap,
+			}
+			if err := tx.Create(&authorization).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			// 重新授权时以本次确认的范围与额度为准，已用额度清零
+			if err := tx.Model(&authorization).Updates(map[string]interface{}{
+				"scopes":       strings.Join(scopes, " "),
+				"spend_cap":    spendCap,
+				"spent_amount": decimal.Zero,
+				"revoked_at":   nil,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		payload, _ := json.Marshal(authorizationCode{
+			AuthorizationID: authorization.ID,
+			ClientID:        apiKey.ClientID,
+			RedirectURI:     req.RedirectURI,
+			CodeChallenge:   req.CodeChallenge,
+		})
+		if err := db.Redis.Set(
+			c.Request.Context(),
+			db.PrefixedKey(fmt.Sprintf(AuthorizationCodeKeyFormat, code)),
+			payload,
+			AuthorizationCodeTTL,
+		).Err(); err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionOAuthAuthorize,
+			TargetType: audit.TargetOAuthAuthorization,
+			TargetID:   authorization.ID,
+			Before:     map[string]interface{}{"scopes": before.Scopes, "spend_cap": before.SpendCap},
+			After:      map[string]interface{}{"client_id": apiKey.ClientID, "scopes": strings.Join(scopes, " "), "spend_cap": spendCap},
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	redirectURL, err := buildRedirectURL(req.RedirectURI, map[string]string{"code": code, "state": req.State})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(RedirectURIMismatch))
+		return
+	}
+	c.JSON(http.StatusOK, util.OK(ApproveResponse{RedirectURL: redirectURL}))
+}
+
+// Token 使用授权码或刷新令牌换取访问令牌
+// @Tags oauth2
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param request formData TokenRequest true "令牌请求"
+// @Success 200 {object} TokenResponse
+// @Router /api/v1/oauth2/token [post]
+func Token(c *gin.Context) {
+	var req TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		oauthError(c, http.StatusBadRequest, ErrInvalidRequest, err.Error())
+		return
+	}
+
+	clientID, clientSecret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, clientSecret = req.ClientID, req.ClientSecret
+	}
+	apiKey, err := service.AuthenticateMerchant(db.DB(c.Request.Context()), clientID, clientSecret)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Basic realm="oauth2"`)
+		oauthError(c, http.StatusUnauthorized, ErrInvalidClient, ClientAuthFailed)
+		return
+	}
+
+	var response *TokenResponse
+	switch req.GrantType {
+	case "authorization_code":
+		response, err = exchangeAuthorizationCode(c, apiKey, &req)
+	case "refresh_token":
+		response, err = refreshAccessToken(c, apiKey, &req)
+	default:
+		oauthError(c, http.StatusBadRequest, ErrUnsupportedGrantType, req.GrantType)
+		return
+	}
+	if err != nil {
+		switch err.Error() {
+		case InvalidAuthCode, PKCEVerifyFailed, InvalidRefreshToken, AuthorizationRevoked, RedirectURIMismatch:
+			oauthError(c, http.StatusBadRequest, ErrInvalidGrant, err.Error())
+		default:
+			oauthError(c, http.StatusInternalServerError, ErrServerError, err.Error())
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// UserInfo 商户读取授权用户的基本信息
+// @Tags oauth2
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/userinfo [get]
+func UserInfo(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	c.JSON(http.StatusOK, util.OK(UserInfoResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Nickname:  user.Nickname,
+		AvatarUrl: user.AvatarUrl,
+	}))
+}
+
+// GetBalance 商户读取授权用户的可用余额及剩余授权额度
+// @Tags oauth2
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/balance [get]
+func GetBalance(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	authorization, _ := util.GetFromContext[*model.OAuthAuthorization](c, AuthorizationObjKey)
+
+	c.JSON(http.StatusOK, util.OK(BalanceResponse{
+		AvailableBalance: user.AvailableBalance,
+		RemainingSpend:   authorization.RemainingSpend(),
+	}))
+}
+
+// Charge 商户在用户授权额度内免密扣款
+// @Tags oauth2
+// @Accept json
+// @Produce json
+// @Param request body ChargeRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/charge [post]
+func Charge(c *gin.Context) {
+	var req ChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, ClientObjKey)
+	authorization, _ := util.GetFromContext[*model.OAuthAuthorization](c, AuthorizationObjKey)
+
+	var merchantUser model.User
+	if err := merchantUser.GetByID(db.DB(c.Request.Context()), apiKey.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(MerchantNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if err := merchantUser.CheckActive(); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(MerchantUnavailable))
+		return
+	}
+
+	var payerPayConfig, merchantPayConfig model.UserPayConfig
+	if err := payerPayConfig.GetByPayScore(db.DB(c.Request.Context()), user.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if err := merchantPayConfig.GetByPayScore(db.DB(c.Request.Context()), merchantUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		// 锁定授权记录，串行化同一授权下的扣款以保证额度不超限
+		var locked model.OAuthAuthorization
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", authorization.ID).
+			First(&locked).Error; err != nil {
+			return err
+		}
+		if locked.RevokedAt != nil || !locked.HasScope(model.OAuthScopeChargeLimited) {
+			return errors.New(AuthorizationRevoked)
+		}
+
+		// 幂等：同一商户订单号已扣款成功时直接返回原订单
+		if err := tx.Where("client_id = ? AND merchant_order_no = ? AND payer_user_id = ? AND payment_type = ?",
+			apiKey.ClientID, req.MerchantOrderNo, user.ID, common.PayTypeOAuth).
+			First(&order).Error; err == nil {
+			if !order.Amount.Equal(req.Amount) {
+				return errors.New(DuplicateMerchantOrder)
+			}
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if locked.SpentAmount.Add(req.Amount).GreaterThan(locked.SpendCap) {
+			return errors.New(SpendCapExceeded)
+		}
+
+		if err := service.CheckDailyLimit(tx, user.ID, req.Amount, payerPayConfig.DailyLimit); err != nil {
+			return err
+		}
+
+		fee, merchantAmount, feePercent := service.CalculateFee(req.Amount, merchantPayConfig.FeeRate)
+		remark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
+		if req.Remark != "" {
+			remark = req.Remark + " " + remark
+		}
+
+		// 先完成风控评估再落库，被拦截时不产生成功订单；预分配订单 ID 以关联风控决策
+		now := time.Now()
+		order = model.Order{
+			ID:              idgen.NextUint64ID(),
+			OrderName:       req.OrderName,
+			ClientID:        apiKey.ClientID,
+			MerchantOrderNo: req.MerchantOrderNo,
+			PayerUserID:     user.ID,
+			PayeeUserID:     merchantUser.ID,
+			Amount:          req.Amount,
+			Fee:             fee,
+			Status:          model.OrderStatusSuccess,
+			Type:            model.OrderTypePayment,
+			Remark:          remark,
+			PaymentType:     common.PayTypeOAuth,
+			TradeTime:       now,
+			ExpiresAt:       now,
+		}
+		if _, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+			Scene:              risk.ScenePayment,
+			User:               user,
+			CounterpartyUserID: merchantUser.ID,
+			Amount:             req.Amount,
+			ReferenceID:        &order.ID,
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+
+		if err := service.DeductUserBalance(tx, user.ID, req.Amount); err != nil {
+			return err
+		}
+
+		merchantScoreIncrease := req.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+		if err := service.AddMerchantBalance(tx, merchantUser.ID, merchantAmount, merchantScoreIncrease); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&locked).
+			UpdateColumn("spent_amount", gorm.Expr("spent_amount + ?", req.Amount)).Error; err != nil {
+			return err
+		}
+
+		// 与收银台支付一致，下发商户回调任务
+		notifyPayload, _ := json.Marshal(map[string]interface{}{
+			"order_id":  order.ID,
+			"client_id": order.ClientID,
+		})
+		if _, errTask := scheduler.AsynqClient.Enqueue(
+			asynq.NewTask(task.MerchantPaymentNotifyTask, notifyPayload),
+			asynq.Queue(task.QueueWebhook),
+			asynq.MaxRetry(10),
+			asynq.Timeout(30*time.Second),
+		); errTask != nil {
+			return fmt.Errorf("下发商户回调任务失败: %w", errTask)
+		}
+
+		return service.Notify(
+			tx,
+			user.ID,
+			model.NotificationCategoryPayment,
+			"授权扣款",
+			fmt.Sprintf("%s 通过授权扣款 %s（%s），剩余授权额度 %s",
+				apiKey.AppName, req.Amount.StringFixed(2), req.OrderName,
+				locked.SpendCap.Sub(locked.SpentAmount).Sub(req.Amount).StringFixed(2)),
+			&order.ID,
+		)
+	}); err != nil {
+		switch errMsg := err.Error(); errMsg {
+		case SpendCapExceeded, DuplicateMerchantOrder, common.InsufficientBalance, common.DailyLimitExceeded:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case AuthorizationRevoked:
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		case common.RiskBlocked:
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	stream.PublishOrderStatus(c.Request.Context(), order.ID)
+	stream.PublishBalance(c.Request.Context(), user.ID, merchantUser.ID)
+
+	c.JSON(http.StatusOK, util.OK(order))
+}
+
+// ListAuthorizations 获取当前用户已授权的应用列表
+// @Tags oauth2
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/authorizations [get]
+func ListAuthorizations(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var authorizations []model.OAuthAuthorization
+	if err := db.DB(c.Request.Context()).
+		Model(&model.OAuthAuthorization{}).
+		Select("oauth_authorizations.*, merchant_api_keys.app_name AS app_name").
+		Joins("JOIN merchant_api_keys ON merchant_api_keys.id = oauth_authorizations.merchant_api_key_id").
+		Where("oauth_authorizations.user_id = ? AND oauth_authorizations.revoked_at IS NULL", user.ID).
+		Order("oauth_authorizations.updated_at DESC").
+		Find(&authorizations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(authorizations))
+}
+
+// RevokeAuthorization 撤销对应用的授权，已签发的令牌全部失效
+// @Tags oauth2
+// @Produce json
+// @Param id path uint64 true "授权 ID"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/oauth2/authorizations/{id} [delete]
+func RevokeAuthorization(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var authorization model.OAuthAuthorization
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", c.Param("id"), user.ID).
+			First(&authorization).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(AuthorizationNotFound)
+			}
+			return err
+		}
+		if authorization.RevokedAt != nil {
+			return errors.New(AuthorizationRevoked)
+		}
+
+		now := time.Now()
+		if err := tx.Model(&authorization).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.OAuthToken{}).
+			Where("authorization_id = ? AND revoked_at IS NULL", authorization.ID).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionOAuthRevoke,
+			TargetType: audit.TargetOAuthAuthorization,
+			TargetID:   authorization.ID,
+			After:      map[string]interface{}{"revoked_at": now},
+		})
+	}); err != nil {
+		switch err.Error() {
+		case AuthorizationNotFound:
+			c.JSON(http.StatusNotFound, util.Err(err.Error()))
+		case AuthorizationRevoked:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// exchangeAuthorizationCode 校验授权码与 PKCE 后签发令牌，授权码只能使用一次
+func exchangeAuthorizationCode(c *gin.Context, apiKey *model.MerchantAPIKey, req *TokenRequest) (*TokenResponse, error) {
+	if req.Code == "" || req.CodeVerifier == "" {
+		return nil, errors.New(InvalidAuthCode)
+	}
+
+	raw, err := db.Redis.GetDel(c.Request.Context(), db.PrefixedKey(fmt.Sprintf(AuthorizationCodeKeyFormat, req.Code))).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New(InvalidAuthCode)
+		}
+		return nil, err
+	}
+
+	var code authorizationCode
+	if err := json.Unmarshal([]byte(raw), &code); err != nil {
+		return nil, errors.New(InvalidAuthCode)
+	}
+	if code.ClientID != apiKey.ClientID {
+		return nil, errors.New(InvalidAuthCode)
+	}
+	if code.RedirectURI != req.RedirectURI {
+		return nil, errors.New(RedirectURIMismatch)
+	}
+	if !verifyPKCE(req.CodeVerifier, code.CodeChallenge) {
+		return nil, errors.New(PKCEVerifyFailed)
+	}
+
+	var response *TokenResponse
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var authorization model.OAuthAuthorization
+		if err := tx.Where("id = ? AND revoked_at IS NULL", code.AuthorizationID).First(&authorization).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(AuthorizationRevoked)
+			}
+			return err
+		}
+
+		var err error
+		response, err = issueTokens(tx, &authorization)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// refreshAccessToken 使用刷新令牌签发新令牌，旧令牌立即作废
+func refreshAccessToken(c *gin.Context, apiKey *model.MerchantAPIKey, req *TokenRequest) (*TokenResponse, error) {
+	if !strings.HasPrefix(req.RefreshToken, RefreshTokenPrefix) {
+		return nil, errors.New(InvalidRefreshToken)
+	}
+
+	var response *TokenResponse
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var token model.OAuthToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("refresh_token_hash = ?", model.HashAccessToken(req.RefreshToken)).
+			First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(InvalidRefreshToken)
+			}
+			return err
+		}
+		if token.RevokedAt != nil || !token.RefreshExpiresAt.After(time.Now()) {
+			return errors.New(InvalidRefreshToken)
+		}
+
+		var authorization model.OAuthAuthorization
+		if err := tx.Where("id = ? AND merchant_api_key_id = ?", token.AuthorizationID, apiKey.ID).
+			First(&authorization).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(InvalidRefreshToken)
+			}
+			return err
+		}
+		if authorization.RevokedAt != nil {
+			return errors.New(AuthorizationRevoked)
+		}
+
+		if err := tx.Model(&token).Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		var err error
+		response, err = issueTokens(tx, &authorization)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// validateAuthorizeRequest 校验授权请求的应用、回调地址与权限范围
+func validateAuthorizeRequest(c *gin.Context, req *AuthorizeRequest) (*model.MerchantAPIKey, []string, string) {
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByClientID(db.DB(c.Request.Context()), req.ClientID); err != nil {
+		return nil, nil, ClientNotFound
+	}
+	if apiKey.RedirectURI == "" || apiKey.RedirectURI != req.RedirectURI {
+		return nil, nil, RedirectURIMismatch
+	}
+
+	scopes, ok := parseScopes(req.Scope)
+	if !ok {
+		return nil, nil, InvalidScope
+	}
+	return &apiKey, scopes, ""
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth_server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/model"
+	"gorm.io/gorm"
+)
+
+// authorizationCode 授权码关联的授权信息，存储于 Redis
+type authorizationCode struct {
+	AuthorizationID uint64 `json:"authorization_id"`
+	ClientID        string `json:"client_id"`
+	RedirectURI     string `json:"redirect_uri"`
+	CodeChallenge   string `json:"code_challenge"`
+}
+
+// oauthError 按 RFC 6749 格式返回错误
+func oauthError(c *gin.Context, status int, code string, description string) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, gin.H{"error": code, "error_description": description})
+}
+
+// generateToken 生成带前缀的随机令牌
+func generateToken(prefix string) (string, error) {
+	buf := make([]byte, tokenRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
+
+// parseScopes 解析并去重空格分隔的权限范围，存在未知范围时返回 false
+func parseScopes(scope string) ([]string, bool) {
+	fields := strings.Fields(scope)
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	scopes := make([]string, 0, len(fields))
+	for _, s := range fields {
+		if !slices.Contains(model.OAuthScopes, model.OAuthScope(s)) {
+			return nil, false
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, true
+}
+
+// verifyPKCE 校验 code_verifier 与 S256 code_challenge 是否匹配
+func verifyPKCE(verifier string, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// buildRedirectURL 在回调地址上追加查询参数
+func buildRedirectURL(redirectURI string, params map[string]string) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// issueTokens 为授权签发一对新的访问令牌与刷新令牌
+func issueTokens(tx *gorm.DB, authorization *model.OAuthAuthorization) (*TokenResponse, error) {
+	accessToken, err := generateToken(AccessTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := generateToken(RefreshTokenPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := tx.Create(&model.OAuthToken{
+		AuthorizationID:  authorization.ID,
+		AccessTokenHash:  model.HashAccessToken(accessToken),
+		RefreshTokenHash: model.HashAccessToken(refreshToken),
+		AccessExpiresAt:  now.Add(AccessTokenTTL),
+		RefreshExpiresAt: now.Add(RefreshTokenTTL),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        authorization.Scopes,
+	}, nil
+}
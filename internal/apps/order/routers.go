@@ -30,8 +30,8 @@ import (
 type TransactionListRequest struct {
 	Page          int        `json:"page" form:"page" binding:"min=1"`
 	PageSize      int        `json:"page_size" form:"page_size" binding:"min=1,max=100"`
-	Type          string     `json:"type" form:"type" binding:"omitempty,oneof=receive payment transfer community online"`
-	Status        string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired disputing refund refused"`
+	Type          string     `json:"type" form:"type" binding:"omitempty,oneof=receive payment transfer community online adjustment"`
+	Status        string     `json:"status" form:"status" binding:"omitempty,oneof=success pending failed expired disputing refund refused partial_refund"`
 	ClientID      string     `json:"client_id" form:"client_id" binding:"omitempty"`
 	StartTime     *time.Time `json:"startTime" form:"startTime" binding:"omitempty"`
 	EndTime       *time.Time `json:"endTime" form:"endTime" binding:"omitempty,gtfield=StartTime"`
@@ -110,6 +110,9 @@ func ListTransactions(c *gin.Context) {
 			} else {
 				baseQuery = baseQuery.Where("orders.type = ? AND (orders.payer_user_id = ? OR orders.payee_user_id = ?)", orderType, user.ID, user.ID)
 			}
+		case model.OrderTypeAdjustment:
+			// adjustment 类型：查询管理员对当前用户的调账订单
+			baseQuery = baseQuery.Where("orders.type = ? AND (orders.payer_user_id = ? OR orders.payee_user_id = ?)", orderType, user.ID, user.ID)
 		case model.OrderTypePayment, model.OrderTypeTransfer:
 			// payment、transfer 类型：查询当前用户作为付款方的订单
 			baseQuery = baseQuery.Where("orders.type = ? AND orders.payer_user_id = ?", orderType, user.ID)
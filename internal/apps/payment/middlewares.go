@@ -25,6 +25,7 @@ import (
 	"github.com/linux-do/credit/internal/common"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/linux-do/credit/internal/util"
 	"github.com/shopspring/decimal"
 )
@@ -93,18 +94,13 @@ func RequireMerchantAuth() gin.HandlerFunc {
 			return
 		}
 
-		clientID := credentials[0]
-		clientSecret := credentials[1]
-
-		var apiKey model.MerchantAPIKey
-		if err := db.DB(c.Request.Context()).
-			Where("client_secret = ? AND client_id = ?", clientSecret, clientID).
-			First(&apiKey).Error; err != nil {
+		apiKey, err := service.AuthenticateMerchant(db.DB(c.Request.Context()), credentials[0], credentials[1])
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, util.Err("认证失败"))
 			return
 		}
 
-		util.SetToContext(c, APIKeyObjKey, &apiKey)
+		util.SetToContext(c, APIKeyObjKey, apiKey)
 
 		c.Next()
 	}
@@ -17,20 +17,21 @@ limitations under the License.
 package payment
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/linux-do/credit/internal/apps/oauth"
 	"github.com/linux-do/credit/internal/common"
-	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/risk"
 	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
 	"github.com/linux-do/credit/internal/task"
     "github.com/linux-do/credit/internal/task/scheduler"
 
@@ -45,8 +46,9 @@ import (
 
 // PayOrderRequest 用户支付订单请求
 type PayOrderRequest struct {
-	OrderNo string `json:"order_no" binding:"required"`
-	PayKey  string `json:"pay_key" binding:"required,max=6"`
+	OrderNo  string `json:"order_no" binding:"required"`
+	PayKey   string `json:"pay_key" binding:"required,max=6"`
+	TOTPCode string `json:"totp_code" binding:"omitempty,max=16"`
 }
 
 // GetOrderRequest 查询订单请求
@@ -73,6 +75,7 @@ type TransferRequest struct {
 	RecipientUsername string          `json:"recipient_username" binding:"required"`
 	Amount            decimal.Decimal `json:"amount" binding:"required"`
 	PayKey            string          `json:"pay_key" binding:"required,max=6"`
+	TOTPCode          string          `json:"totp_code" binding:"omitempty,max=16"`
 	Remark            string          `json:"remark" binding:"max=100"`
 }
 
@@ -155,7 +158,7 @@ func CreateMerchantOrder(c *gin.Context) {
 				return fmt.Errorf("failed to set order expire key: %w", errSet)
 			}
 
-			payURL = fmt.Sprintf("%s?order_no=%s", config.Config.App.FrontendPayURL, url.QueryEscape(encryptString))
+			payURL = BuildPayURL(encryptString)
 			return nil
 		},
 	); err != nil {
@@ -195,8 +198,7 @@ func QueryMerchantOrder(c *gin.Context) {
 		return
 	}
 
-	var apiKey model.MerchantAPIKey
-	if err := db.DB(c.Request.Context()).Where("client_id = ? AND client_secret = ?", req.ClientID, req.ClientSecret).First(&apiKey).Error; err != nil {
+	if _, err := service.AuthenticateMerchant(db.DB(c.Request.Context()), req.ClientID, req.ClientSecret); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": -1, "msg": MerchantInfoNotFound})
 		return
 	}
@@ -261,14 +263,14 @@ func RefundMerchantOrder(c *gin.Context) {
 		return
 	}
 
-	var apiKey model.MerchantAPIKey
-	if err := db.DB(c.Request.Context()).Where("client_id = ? AND client_secret = ?", req.ClientID, req.ClientSecret).First(&apiKey).Error; err != nil {
+	apiKey, err := service.AuthenticateMerchant(db.DB(c.Request.Context()), req.ClientID, req.ClientSecret)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": -1, "msg": MerchantInfoNotFound})
 		return
 	}
 
+	var order model.Order
 	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
-		var order model.Order
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("id = ? AND client_id = ? AND status = ? AND amount = ?", req.TradeNo, req.ClientID, model.OrderStatusSuccess, req.Amount).
 			First(&order).Error; err != nil {
@@ -278,54 +280,28 @@ func RefundMerchantOrder(c *gin.Context) {
 			return err
 		}
 
-		var payerUser model.User
-		if err := payerUser.GetByID(tx, order.PayerUserID); err != nil {
-			return err
-		}
-
+		// 商户账户需处于启用状态
 		var merchantUser model.User
 		if err := tx.Where("id = ? AND is_active = ?", apiKey.UserID, true).First(&merchantUser).Error; err != nil {
 			return err
 		}
 
-		var merchantPayConfig model.UserPayConfig
-		if err := merchantPayConfig.GetByPayScore(tx, merchantUser.PayScore); err != nil {
-			return err
-		}
-
-		merchantScoreDecrease := order.Amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
-		if err := tx.Model(&model.User{}).
-			Where("id = ?", merchantUser.ID).
-			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance - ?", order.Amount),
-				"total_receive":     gorm.Expr("total_receive - ?", order.Amount),
-				"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
-			}).Error; err != nil {
+		// 与争议退款共用同一退款逻辑，包含风控评估、按比例退还手续费并累计退款金额
+		if err := service.RefundOrder(tx, &order, order.Amount); err != nil {
 			return err
 		}
 
-		if err := tx.Model(&model.User{}).
-			Where("id = ?", payerUser.ID).
-			UpdateColumns(map[string]interface{}{
-				"available_balance": gorm.Expr("available_balance + ?", order.Amount),
-				"total_payment":     gorm.Expr("total_payment - ?", order.Amount),
-				"pay_score":         gorm.Expr("pay_score - ?", order.Amount.Round(0).IntPart()),
-			}).Error; err != nil {
-			return err
-		}
-
-		if err := tx.Model(&model.Order{}).
+		return tx.Model(&model.Order{}).
 			Where("id = ?", order.ID).
-			Update("status", model.OrderStatusRefund).Error; err != nil {
-			return err
-		}
-
-		return nil
+			Update("status", model.OrderStatusRefund).Error
 	}); err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": -1, "msg": err.Error()})
 		return
 	}
 
+	stream.PublishOrderStatus(c.Request.Context(), order.ID)
+	stream.PublishBalance(c.Request.Context(), order.PayerUserID, order.PayeeUserID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"code": 1,
 		"msg":  "退款成功",
@@ -402,8 +378,20 @@ func PayMerchantOrder(c *gin.Context) {
 		return
 	}
 
-	if !orderCtx.CurrentUser.VerifyPayKey(req.PayKey) {
-		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+	if !service.CheckPayKey(c, orderCtx.CurrentUser, req.PayKey) {
+		return
+	}
+
+	var pendingOrder model.Order
+	if err := db.DB(c.Request.Context()).Select("amount").Where("id = ?", orderCtx.OrderID).First(&pendingOrder).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if !service.CheckTOTPForAmount(c, orderCtx.CurrentUser, pendingOrder.Amount, req.TOTPCode) {
 		return
 	}
 
@@ -429,8 +417,19 @@ func PayMerchantOrder(c *gin.Context) {
 				return err
 			}
 
+			// 风控评估
+			if _, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+				Scene:              risk.ScenePayment,
+				User:               orderCtx.CurrentUser,
+				CounterpartyUserID: orderCtx.MerchantUser.ID,
+				Amount:             order.Amount,
+				ReferenceID:        &order.ID,
+			}); err != nil {
+				return err
+			}
+
 			// 计算手续费
-			_, merchantAmount, feePercent := service.CalculateFee(order.Amount, orderCtx.MerchantPayConfig.FeeRate)
+			fee, merchantAmount, feePercent := service.CalculateFee(order.Amount, orderCtx.MerchantPayConfig.FeeRate)
 			feeRemark := fmt.Sprintf("[系统]: 收取商家%d%%手续费", feePercent)
 
 			// 更新订单状态和备注
@@ -440,6 +439,7 @@ func PayMerchantOrder(c *gin.Context) {
 				order.Remark = feeRemark
 			}
 			order.Status = model.OrderStatusSuccess
+			order.Fee = fee
 			order.PayerUserID = orderCtx.CurrentUser.ID
 			order.TradeTime = time.Now()
 			if err := tx.Save(&order).Error; err != nil {
@@ -476,7 +476,14 @@ func PayMerchantOrder(c *gin.Context) {
 				return fmt.Errorf("下发商户回调任务失败: %w", errTask)
 			}
 
-			return nil
+			return service.Notify(
+				tx,
+				orderCtx.MerchantUser.ID,
+				model.NotificationCategoryPayment,
+				"收到付款",
+				fmt.Sprintf("%s 支付了订单「%s」%s", orderCtx.CurrentUser.Username, order.OrderName, order.Amount.StringFixed(2)),
+				&order.ID,
+			)
 		},
 	); err != nil {
 		errMsg := err.Error()
@@ -488,12 +495,17 @@ func PayMerchantOrder(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, util.Err(OrderExpired))
 		} else if errMsg == common.DailyLimitExceeded {
 			c.JSON(http.StatusBadRequest, util.Err(common.DailyLimitExceeded))
+		} else if errMsg == common.RiskBlocked {
+			c.JSON(http.StatusForbidden, util.Err(common.RiskBlocked))
 		} else {
 			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
 		}
 		return
 	}
 
+	stream.PublishOrderStatus(c.Request.Context(), orderCtx.OrderID)
+	stream.PublishBalance(c.Request.Context(), orderCtx.CurrentUser.ID, orderCtx.MerchantUser.ID)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
 
@@ -523,8 +535,11 @@ func Transfer(c *gin.Context) {
 
 	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	if !currentUser.VerifyPayKey(req.PayKey) {
-		c.JSON(http.StatusBadRequest, util.Err(common.PayKeyIncorrect))
+	if !service.CheckPayKey(c, currentUser, req.PayKey) {
+		return
+	}
+
+	if !service.CheckTOTPForAmount(c, currentUser, req.Amount, req.TOTPCode) {
 		return
 	}
 
@@ -533,10 +548,17 @@ func Transfer(c *gin.Context) {
 		return
 	}
 
+	// 获取付款方的支付配置
+	var payerPayConfig model.UserPayConfig
+	if err := payerPayConfig.GetByPayScore(db.DB(c.Request.Context()), currentUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var recipient model.User
 	if err := db.DB(c.Request.Context()).Transaction(
 		func(tx *gorm.DB) error {
 			// 验证收款人是否存在且用户名匹配
-			var recipient model.User
 			if err := tx.Where("id = ? AND username = ?", req.RecipientID, req.RecipientUsername).First(&recipient).Error; err != nil {
 				if errors.Is(err, gorm.ErrRecordNotFound) {
 					return errors.New(RecipientNotFound)
@@ -544,60 +566,163 @@ func Transfer(c *gin.Context) {
 				return err
 			}
 
-			var payer model.User
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
-				Where("id = ?", currentUser.ID).
-				First(&payer).Error; err != nil {
+			// 检查转账限额
+			if err := service.CheckTransferLimit(tx, currentUser, req.Amount, &payerPayConfig); err != nil {
 				return err
 			}
 
-			if payer.AvailableBalance.LessThan(req.Amount) {
-				return errors.New(common.InsufficientBalance)
+			// 风控评估
+			if _, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+				Scene:              risk.SceneTransfer,
+				User:               currentUser,
+				CounterpartyUserID: recipient.ID,
+				Amount:             req.Amount,
+			}); err != nil {
+				return err
 			}
 
-			// 创建转账订单
-			order := model.Order{
-				OrderName:   "转账",
-				PayerUserID: payer.ID,
+			order, err := service.SettleTransfer(tx, &service.TransferParams{
+				PayerUserID: currentUser.ID,
 				PayeeUserID: recipient.ID,
 				Amount:      req.Amount,
-				Status:      model.OrderStatusSuccess,
-				Type:        model.OrderTypeTransfer,
+				OrderName:   "转账",
 				Remark:      req.Remark,
-				TradeTime:   time.Now(),
-				ExpiresAt:   time.Now().Add(24 * time.Hour),
-			}
-
-			if err := tx.Create(&order).Error; err != nil {
-				return err
-			}
-
-			// 扣减付款人余额
-			if err := tx.Model(&model.User{}).
-				Where("id = ?", payer.ID).
-				UpdateColumns(map[string]interface{}{
-					"available_balance": gorm.Expr("available_balance - ?", req.Amount),
-					"total_transfer":    gorm.Expr("total_transfer + ?", req.Amount),
-				}).Error; err != nil {
-				return err
-			}
-
-			// 增加收款人余额
-			if err := tx.Model(&model.User{}).
-				Where("id = ?", recipient.ID).
-				UpdateColumns(map[string]interface{}{
-					"available_balance": gorm.Expr("available_balance + ?", req.Amount),
-					"total_receive":     gorm.Expr("total_receive + ?", req.Amount),
-				}).Error; err != nil {
+			})
+			if err != nil {
 				return err
 			}
 
-			return nil
+			return service.Notify(
+				tx,
+				recipient.ID,
+				model.NotificationCategoryTransfer,
+				"收到转账",
+				fmt.Sprintf("%s 向你转账 %s", currentUser.Username, req.Amount.StringFixed(2)),
+				&order.ID,
+			)
 		},
 	); err != nil {
+		if err.Error() == common.RiskBlocked {
+			c.JSON(http.StatusForbidden, util.Err(err.Error()))
+			return
+		}
 		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
 		return
 	}
 
+	stream.PublishBalance(c.Request.Context(), currentUser.ID, recipient.ID)
+
 	c.JSON(http.StatusOK, util.OKNil())
 }
+
+// RecipientRequest 查询收款人请求（个人收款码中携带的用户信息）
+type RecipientRequest struct {
+	UserID   uint64 `form:"user_id" binding:"required"`
+	Username string `form:"username" binding:"required"`
+}
+
+// RecipientResponse 收款人公开信息
+type RecipientResponse struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	Nickname  string `json:"nickname"`
+	AvatarUrl string `json:"avatar_url"`
+}
+
+// GetRecipient 解析个人收款码，查询收款人公开信息
+// @Tags payment
+// @Produce json
+// @Param user_id query uint64 true "收款人用户 ID"
+// @Param username query string true "收款人用户名"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/recipient [get]
+func GetRecipient(c *gin.Context) {
+	var req RecipientRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var recipient RecipientResponse
+	if err := db.DB(c.Request.Context()).Model(&model.User{}).
+		Where("id = ? AND username = ? AND is_active = ?", req.UserID, req.Username, true).
+		First(&recipient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RecipientNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(recipient))
+}
+
+// OrderStatusEvent 订单状态推送数据
+type OrderStatusEvent struct {
+	OrderID uint64            `json:"order_id"`
+	Status  model.OrderStatus `json:"status"`
+}
+
+// orderStatusSnapshot 读取订单当前状态，isDone 判断推送是否已到终态
+func orderStatusSnapshot(orderID uint64, isDone func(model.OrderStatus) bool) stream.Snapshot {
+	return func(ctx context.Context) (string, interface{}, bool, error) {
+		var order model.Order
+		if err := db.DB(ctx).Select("id", "status").Where("id = ?", orderID).First(&order).Error; err != nil {
+			return "", nil, true, err
+		}
+		return stream.EventOrderStatus, OrderStatusEvent{OrderID: order.ID, Status: order.Status}, isDone(order.Status), nil
+	}
+}
+
+// StreamPaymentOrder 收银台订阅订单状态（Server-Sent Events），订单离开待支付状态后推送最终状态并关闭连接
+// @Tags payment
+// @Produce text/event-stream
+// @Param order_no query string true "订单号"
+// @Success 200 {object} OrderStatusEvent
+// @Router /api/v1/merchant/payment/order/stream [get]
+func StreamPaymentOrder(c *gin.Context) {
+	var req GetOrderRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	orderCtx, errCtx := ParseOrderNo(c, req.OrderNo)
+	if HandleParseOrderNoError(c, errCtx) {
+		return
+	}
+
+	stream.Serve(c, fmt.Sprintf(stream.OrderChannelFormat, orderCtx.OrderID),
+		orderStatusSnapshot(orderCtx.OrderID, func(status model.OrderStatus) bool {
+			return status != model.OrderStatusPending
+		}))
+}
+
+// StreamMerchantOrder 商户订阅自己订单的状态变化（Server-Sent Events），订单过期或全额退款后关闭连接
+// @Tags payment
+// @Produce text/event-stream
+// @Param trade_no path uint64 true "平台订单号"
+// @Success 200 {object} OrderStatusEvent
+// @Router /api/v1/merchant/orders/{trade_no}/stream [get]
+func StreamMerchantOrder(c *gin.Context) {
+	apiKey, _ := util.GetFromContext[*model.MerchantAPIKey](c, APIKeyObjKey)
+
+	var order model.Order
+	if err := db.DB(c.Request.Context()).
+		Select("id").
+		Where("id = ? AND client_id = ?", c.Param("trade_no"), apiKey.ClientID).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(OrderNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	stream.Serve(c, fmt.Sprintf(stream.OrderChannelFormat, order.ID),
+		orderStatusSnapshot(order.ID, func(status model.OrderStatus) bool {
+			return status == model.OrderStatusExpired || status == model.OrderStatusRefund
+		}))
+}
@@ -28,10 +28,13 @@ import (
 	"strings"
 
 	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/apps/email"
 	"github.com/linux-do/credit/internal/common"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/mailer"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/linux-do/credit/internal/util"
 	"gorm.io/gorm"
 )
@@ -77,12 +80,25 @@ func HandleMerchantPaymentNotify(ctx context.Context, t *asynq.Task) error {
 		"sign_type":    "MD5",
 	}
 
-	callbackParams["sign"] = GenerateSignature(callbackParams, apiKey.ClientSecret)
+	// 回调使用最新的有效密钥签名
+	secrets, err := service.MerchantSigningSecrets(db.DB(ctx), &apiKey)
+	if err != nil {
+		logger.ErrorF(ctx, "获取商户[ClientID:%s]签名密钥失败: %v", payload.ClientID, err)
+		return fmt.Errorf("获取商户签名密钥失败: %w", err)
+	}
+	callbackParams["sign"] = GenerateSignature(callbackParams, secrets[0].Secret)
 
 	if err := sendCallbackRequest(ctx, apiKey.NotifyURL, callbackParams); err != nil {
 		retried, _ := asynq.GetRetryCount(ctx)
 		logger.ErrorF(ctx, "商户回调失败: 订单[ID:%d] 重试次数[%d] 错误: %v",
 			payload.OrderID, retried+1, err)
+
+		// 最后一次重试仍失败时邮件告知商户
+		if maxRetry, ok := asynq.GetMaxRetry(ctx); ok && retried >= maxRetry {
+			if errMail := notifyWebhookFailed(ctx, &order, &apiKey, retried+1, err); errMail != nil {
+				logger.ErrorF(ctx, "下发回调失败邮件失败: 订单[ID:%d] 错误: %v", payload.OrderID, errMail)
+			}
+		}
 		return err
 	}
 
@@ -131,3 +147,20 @@ func sendCallbackRequest(ctx context.Context, callbackURL string, params map[str
 	logger.InfoF(ctx, "商户回调请求成功: URL[%s] 响应[%s]", callbackURL, string(respBody))
 	return nil
 }
+
+// notifyWebhookFailed 回调重试耗尽后向商户发送邮件
+func notifyWebhookFailed(ctx context.Context, order *model.Order, apiKey *model.MerchantAPIKey, attempts int, callbackErr error) error {
+	var merchantUser model.User
+	if err := merchantUser.GetByID(db.DB(ctx), apiKey.UserID); err != nil {
+		return err
+	}
+
+	return email.Enqueue(db.DB(ctx), merchantUser.ID, mailer.EventWebhookFailed, map[string]interface{}{
+		"Username":   merchantUser.Username,
+		"TradeNo":    strconv.FormatUint(order.ID, 10),
+		"OutTradeNo": order.MerchantOrderNo,
+		"NotifyURL":  apiKey.NotifyURL,
+		"Attempts":   attempts,
+		"Error":      callbackErr.Error(),
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,8 +31,10 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	"github.com/linux-do/credit/internal/apps/oauth"
 	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/config"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/linux-do/credit/internal/util"
 	"github.com/redis/go-redis/v9"
 	"github.com/shopspring/decimal"
@@ -142,6 +145,11 @@ func ParseOrderNo(c *gin.Context, orderNo string) (*OrderContext, error) {
 	return ctx, nil
 }
 
+// BuildPayURL 根据加密订单号构建收银台支付链接
+func BuildPayURL(orderNo string) string {
+	return fmt.Sprintf("%s?order_no=%s", config.Config.App.FrontendPayURL, url.QueryEscape(orderNo))
+}
+
 // GenerateSignature 生成MD5签名
 func GenerateSignature(params map[string]string, secret string) string {
 	// 按key排序
@@ -210,13 +218,19 @@ func VerifySignature(c *gin.Context, apiKey *model.MerchantAPIKey) (*CreateOrder
 		"device":       req.Device,
 	}
 
-	// 生成期望的签名
-	expectedSign := GenerateSignature(params, apiKey.ClientSecret)
-
-	// 常量时间比较签名（防止时序攻击）
-	if subtle.ConstantTimeCompare([]byte(strings.ToLower(expectedSign)), []byte(strings.ToLower(req.Sign))) != 1 {
+	secrets, err := service.MerchantSigningSecrets(db.DB(c.Request.Context()), apiKey)
+	if err != nil {
 		return nil, errors.New("签名验证失败")
 	}
 
-	return req.ToCreateOrderRequest(), nil
+	// 轮换过渡期内新旧密钥的签名均有效，逐个常量时间比较（防止时序攻击）
+	for _, secret := range secrets {
+		expectedSign := GenerateSignature(params, secret.Secret)
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(expectedSign)), []byte(strings.ToLower(req.Sign))) == 1 {
+			service.TouchClientSecret(db.DB(c.Request.Context()), secret.ID)
+			return req.ToCreateOrderRequest(), nil
+		}
+	}
+
+	return nil, errors.New("签名验证失败")
 }
@@ -0,0 +1,28 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payment_request
+
+const (
+	PaymentRequestNotFound   = "收款请求不存在"
+	PaymentRequestNotPending = "收款请求已处理"
+	PaymentRequestExpired    = "收款请求已过期"
+	PayerNotFound            = "付款人不存在"
+	CannotRequestFromSelf    = "不能向自己发起收款请求"
+	CannotPayOwnRequest      = "不能支付自己发起的收款请求"
+	NotRequestPayer          = "您不是该收款请求的付款人"
+	DeclineNotAllowed        = "公开收款请求无法拒绝，如无需支付忽略即可"
+)
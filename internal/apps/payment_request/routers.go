@@ -0,0 +1,517 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payment_request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentRequestDetail 收款请求详情
+type PaymentRequestDetail struct {
+	model.PaymentRequest
+	ShareURL string `json:"share_url"`
+}
+
+// CreatePaymentRequestRequest 发起收款请求
+type CreatePaymentRequestRequest struct {
+	Amount        decimal.Decimal `json:"amount" binding:"required"`
+	Memo          string          `json:"memo" binding:"max=100"`
+	PayerUsername string          `json:"payer_username" binding:"omitempty,max=64"`
+}
+
+// CreatePaymentRequest 发起收款请求
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body CreatePaymentRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/request [post]
+func CreatePaymentRequest(c *gin.Context) {
+	var req CreatePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	expireHours, err := model.GetIntByKey(c.Request.Context(), model.ConfigKeyPaymentRequestExpireHours)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	paymentRequest := model.PaymentRequest{
+		Token:           util.GenerateUniqueIDSimple(),
+		RequesterUserID: currentUser.ID,
+		Amount:          req.Amount,
+		Memo:            req.Memo,
+		Status:          model.PaymentRequestStatusPending,
+		ExpiresAt:       time.Now().Add(time.Duration(expireHours) * time.Hour),
+	}
+
+	// 指定付款人时校验付款人是否存在
+	if req.PayerUsername != "" {
+		var payer model.User
+		if err := db.DB(c.Request.Context()).Where("username = ?", req.PayerUsername).First(&payer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, util.Err(PayerNotFound))
+			} else {
+				c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+			}
+			return
+		}
+
+		if payer.ID == currentUser.ID {
+			c.JSON(http.StatusBadRequest, util.Err(CannotRequestFromSelf))
+			return
+		}
+
+		paymentRequest.PayerUserID = &payer.ID
+		paymentRequest.PayerUsername = payer.Username
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Create(&paymentRequest).Error; err != nil {
+				return err
+			}
+
+			if paymentRequest.PayerUserID != nil {
+				if err := service.Notify(
+					tx,
+					*paymentRequest.PayerUserID,
+					model.NotificationCategoryPaymentRequest,
+					"收到收款请求",
+					fmt.Sprintf("%s 向您发起了 %s 的收款请求：%s", currentUser.Username, req.Amount.StringFixed(2), req.Memo),
+					&paymentRequest.ID,
+				); err != nil {
+					return err
+				}
+			}
+
+			return enqueueExpireTask(&paymentRequest)
+		},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	paymentRequest.RequesterUsername = currentUser.Username
+	c.JSON(http.StatusOK, util.OK(PaymentRequestDetail{
+		PaymentRequest: paymentRequest,
+		ShareURL:       BuildShareURL(paymentRequest.Token),
+	}))
+}
+
+// ListPaymentRequestsRequest 查询收款请求列表请求
+type ListPaymentRequestsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Role     string `json:"role" form:"role" binding:"required,oneof=sent received"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=pending paid declined canceled expired"`
+}
+
+// ListPaymentRequestsResponse 查询收款请求列表响应
+type ListPaymentRequestsResponse struct {
+	Total           int64                  `json:"total"`
+	Page            int                    `json:"page"`
+	PageSize        int                    `json:"page_size"`
+	PaymentRequests []model.PaymentRequest `json:"payment_requests"`
+}
+
+// ListPaymentRequests 查询当前用户发起（sent）或收到（received）的收款请求
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ListPaymentRequestsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/requests [post]
+func ListPaymentRequests(c *gin.Context) {
+	var req ListPaymentRequestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := detailQuery(db.DB(c.Request.Context()))
+	if req.Role == "sent" {
+		baseQuery = baseQuery.Where("payment_requests.requester_user_id = ?", user.ID)
+	} else {
+		baseQuery = baseQuery.Where("payment_requests.payer_user_id = ?", user.ID)
+	}
+
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("payment_requests.status = ?", model.PaymentRequestStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListPaymentRequestsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("payment_requests.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.PaymentRequests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// GetPaymentRequestByToken 通过 Token 查询收款请求
+// @Tags payment
+// @Produce json
+// @Param token path string true "收款请求 Token"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/requests/{token} [get]
+func GetPaymentRequestByToken(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var paymentRequest model.PaymentRequest
+	if err := detailQuery(db.DB(c.Request.Context())).
+		Where("payment_requests.token = ?", c.Param("token")).
+		First(&paymentRequest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(PaymentRequestNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	if !canView(&paymentRequest, user.ID) {
+		c.JSON(http.StatusNotFound, util.Err(PaymentRequestNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(PaymentRequestDetail{
+		PaymentRequest: paymentRequest,
+		ShareURL:       BuildShareURL(paymentRequest.Token),
+	}))
+}
+
+// ApprovePaymentRequestRequest 支付收款请求
+type ApprovePaymentRequestRequest struct {
+	Token    string `json:"token" binding:"required"`
+	PayKey   string `json:"pay_key" binding:"required,max=6"`
+	TOTPCode string `json:"totp_code" binding:"omitempty,max=16"`
+}
+
+// ApprovePaymentRequest 付款人确认并支付收款请求
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ApprovePaymentRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/request/approve [post]
+func ApprovePaymentRequest(c *gin.Context) {
+	var req ApprovePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if !service.CheckPayKey(c, currentUser, req.PayKey) {
+		return
+	}
+
+	// 收款请求不存在时交由后续事务返回对应错误
+	var pendingRequest model.PaymentRequest
+	if err := db.DB(c.Request.Context()).Select("amount").Where("token = ?", req.Token).First(&pendingRequest).Error; err == nil &&
+		!service.CheckTOTPForAmount(c, currentUser, pendingRequest.Amount, req.TOTPCode) {
+		return
+	}
+
+	// 获取付款方的支付配置
+	var payerPayConfig model.UserPayConfig
+	if err := payerPayConfig.GetByPayScore(db.DB(c.Request.Context()), currentUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	var paymentRequest model.PaymentRequest
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("token = ?", req.Token).
+				First(&paymentRequest).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(PaymentRequestNotFound)
+				}
+				return err
+			}
+
+			if !canView(&paymentRequest, currentUser.ID) {
+				return errors.New(PaymentRequestNotFound)
+			}
+			if paymentRequest.RequesterUserID == currentUser.ID {
+				return errors.New(CannotPayOwnRequest)
+			}
+			if paymentRequest.Status != model.PaymentRequestStatusPending {
+				return errors.New(PaymentRequestNotPending)
+			}
+
+			now := time.Now()
+			if !now.Before(paymentRequest.ExpiresAt) {
+				return errors.New(PaymentRequestExpired)
+			}
+
+			// 检查转账限额
+			if err := service.CheckTransferLimit(tx, currentUser, paymentRequest.Amount, &payerPayConfig); err != nil {
+				return err
+			}
+
+			// 风控评估
+			if _, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+				Scene:              risk.SceneTransfer,
+				User:               currentUser,
+				CounterpartyUserID: paymentRequest.RequesterUserID,
+				Amount:             paymentRequest.Amount,
+			}); err != nil {
+				return err
+			}
+
+			order, err := service.SettleTransfer(tx, &service.TransferParams{
+				PayerUserID: currentUser.ID,
+				PayeeUserID: paymentRequest.RequesterUserID,
+				Amount:      paymentRequest.Amount,
+				OrderName:   "收款请求",
+				Remark:      paymentRequest.Memo,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Model(&paymentRequest).
+				Updates(map[string]interface{}{
+					"status":        model.PaymentRequestStatusPaid,
+					"payer_user_id": currentUser.ID,
+					"order_id":      order.ID,
+					"handled_at":    now,
+				}).Error; err != nil {
+				return err
+			}
+
+			return service.Notify(
+				tx,
+				paymentRequest.RequesterUserID,
+				model.NotificationCategoryPaymentRequest,
+				"收款请求已支付",
+				fmt.Sprintf("%s 已支付您发起的 %s 收款请求", currentUser.Username, paymentRequest.Amount.StringFixed(2)),
+				&paymentRequest.ID,
+			)
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch errMsg {
+		case PaymentRequestNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		case CannotPayOwnRequest, PaymentRequestNotPending, PaymentRequestExpired, common.InsufficientBalance,
+			common.TransferAmountExceedsMax, common.TransferDailyLimitExceeded, common.TransferMonthlyLimitExceeded, common.TransferAccountTooNew:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case common.RiskBlocked:
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	stream.PublishBalance(c.Request.Context(), currentUser.ID, paymentRequest.RequesterUserID)
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// DeclinePaymentRequestRequest 拒绝收款请求
+type DeclinePaymentRequestRequest struct {
+	Token  string `json:"token" binding:"required"`
+	Reason string `json:"reason" binding:"max=100"`
+}
+
+// DeclinePaymentRequest 付款人拒绝收款请求（仅限指定付款人的收款请求）
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body DeclinePaymentRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/request/decline [post]
+func DeclinePaymentRequest(c *gin.Context) {
+	var req DeclinePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var paymentRequest model.PaymentRequest
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("token = ? AND status = ?", req.Token, model.PaymentRequestStatusPending).
+				First(&paymentRequest).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(PaymentRequestNotFound)
+				}
+				return err
+			}
+
+			if paymentRequest.PayerUserID == nil {
+				return errors.New(DeclineNotAllowed)
+			}
+			if *paymentRequest.PayerUserID != currentUser.ID {
+				return errors.New(NotRequestPayer)
+			}
+
+			if err := tx.Model(&paymentRequest).
+				Updates(map[string]interface{}{
+					"status":         model.PaymentRequestStatusDeclined,
+					"decline_reason": req.Reason,
+					"handled_at":     time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+
+			return service.Notify(
+				tx,
+				paymentRequest.RequesterUserID,
+				model.NotificationCategoryPaymentRequest,
+				"收款请求被拒绝",
+				fmt.Sprintf("%s 拒绝了您发起的 %s 收款请求：%s", currentUser.Username, paymentRequest.Amount.StringFixed(2), req.Reason),
+				&paymentRequest.ID,
+			)
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch errMsg {
+		case PaymentRequestNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		case DeclineNotAllowed, NotRequestPayer:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// CancelPaymentRequestRequest 撤销收款请求
+type CancelPaymentRequestRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CancelPaymentRequest 发起人撤销收款请求
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body CancelPaymentRequestRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/request/cancel [post]
+func CancelPaymentRequest(c *gin.Context) {
+	var req CancelPaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var paymentRequest model.PaymentRequest
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("token = ? AND requester_user_id = ? AND status = ?", req.Token, currentUser.ID, model.PaymentRequestStatusPending).
+				First(&paymentRequest).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(PaymentRequestNotFound)
+				}
+				return err
+			}
+
+			if err := tx.Model(&paymentRequest).
+				Updates(map[string]interface{}{
+					"status":     model.PaymentRequestStatusCanceled,
+					"handled_at": time.Now(),
+				}).Error; err != nil {
+				return err
+			}
+
+			if paymentRequest.PayerUserID == nil {
+				return nil
+			}
+
+			return service.Notify(
+				tx,
+				*paymentRequest.PayerUserID,
+				model.NotificationCategoryPaymentRequest,
+				"收款请求已撤销",
+				fmt.Sprintf("%s 撤销了向您发起的 %s 收款请求", currentUser.Username, paymentRequest.Amount.StringFixed(2)),
+				&paymentRequest.ID,
+			)
+		},
+	); err != nil {
+		errMsg := err.Error()
+		if errMsg == PaymentRequestNotFound {
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payment_request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleExpirePaymentRequest 处理收款请求到期任务
+func HandleExpirePaymentRequest(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		PaymentRequestID uint64 `json:"payment_request_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	return db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var paymentRequest model.PaymentRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", payload.PaymentRequestID, model.PaymentRequestStatusPending).
+			First(&paymentRequest).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.InfoF(ctx, "收款请求[ID:%d]已被处理或不存在，跳过", payload.PaymentRequestID)
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now()
+		if now.Before(paymentRequest.ExpiresAt) {
+			return nil
+		}
+
+		if err := tx.Model(&paymentRequest).
+			Updates(map[string]interface{}{
+				"status":     model.PaymentRequestStatusExpired,
+				"handled_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		return service.Notify(
+			tx,
+			paymentRequest.RequesterUserID,
+			model.NotificationCategoryPaymentRequest,
+			"收款请求已过期",
+			fmt.Sprintf("您发起的 %s 收款请求已过期", paymentRequest.Amount.StringFixed(2)),
+			&paymentRequest.ID,
+		)
+	})
+}
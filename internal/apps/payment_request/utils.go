@@ -0,0 +1,67 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package payment_request
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/task"
+	"github.com/linux-do/credit/internal/task/scheduler"
+	"gorm.io/gorm"
+)
+
+// BuildShareURL 构建收款请求分享链接
+func BuildShareURL(token string) string {
+	return fmt.Sprintf("%s?token=%s", config.Config.App.FrontendPaymentRequestURL, url.QueryEscape(token))
+}
+
+// detailQuery 收款请求详情查询（附带发起人与付款人用户名）
+func detailQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&model.PaymentRequest{}).
+		Select("payment_requests.*, requester_user.username as requester_username, payer_user.username as payer_username").
+		Joins("JOIN users as requester_user ON payment_requests.requester_user_id = requester_user.id").
+		Joins("LEFT JOIN users as payer_user ON payment_requests.payer_user_id = payer_user.id")
+}
+
+// enqueueExpireTask 下发收款请求到期处理任务
+func enqueueExpireTask(paymentRequest *model.PaymentRequest) error {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"payment_request_id": paymentRequest.ID,
+	})
+	if _, err := scheduler.AsynqClient.Enqueue(
+		asynq.NewTask(task.ExpirePaymentRequestTask, payload),
+		asynq.ProcessAt(paymentRequest.ExpiresAt),
+		asynq.MaxRetry(5),
+	); err != nil {
+		return fmt.Errorf("下发收款请求到期任务失败: %w", err)
+	}
+	return nil
+}
+
+// canView 判断用户是否可以查看收款请求
+// 指定付款人的收款请求仅发起人与付款人可见，公开收款请求持有链接即可查看
+func canView(paymentRequest *model.PaymentRequest, userID uint64) bool {
+	if paymentRequest.RequesterUserID == userID || paymentRequest.PayerUserID == nil {
+		return true
+	}
+	return *paymentRequest.PayerUserID == userID
+}
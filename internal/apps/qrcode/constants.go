@@ -0,0 +1,37 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qrcode
+
+import "time"
+
+const (
+	// QRCodeCacheKeyFormat Redis key 格式，用于缓存渲染后的二维码图片
+	QRCodeCacheKeyFormat = "qrcode:%s"
+	// QRCodeCacheExpiration 支付链接、收款码二维码缓存时间
+	QRCodeCacheExpiration = 24 * time.Hour
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 1024
+)
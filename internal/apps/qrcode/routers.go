@@ -0,0 +1,169 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qrcode
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/merchant/link"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/apps/payment"
+	"github.com/linux-do/credit/internal/apps/payment_request"
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+)
+
+// QRCodeOptions 二维码渲染参数
+type QRCodeOptions struct {
+	Format string `form:"format" binding:"omitempty,oneof=png svg"`
+	Size   int    `form:"size" binding:"omitempty,min=64,max=1024"`
+	Level  string `form:"level" binding:"omitempty,oneof=L M Q H"`
+}
+
+// OrderQRCodeRequest 订单支付二维码请求
+type OrderQRCodeRequest struct {
+	QRCodeOptions
+	OrderNo string `form:"order_no" binding:"required"`
+}
+
+// GetOrderQRCode 获取商户订单支付二维码
+// @Tags qrcode
+// @Produce png
+// @Produce image/svg+xml
+// @Param order_no query string true "加密订单号（CreateMerchantOrder 返回的支付链接中的 order_no）"
+// @Param format query string false "图片格式：png、svg"
+// @Param size query int false "图片边长（像素），64-1024"
+// @Param level query string false "纠错等级：L、M、Q、H"
+// @Success 200 {file} binary
+// @Router /api/v1/qrcode/order [get]
+func GetOrderQRCode(c *gin.Context) {
+	var req OrderQRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	// 订单号仅在未过期期间存在，二维码缓存时间不超过订单剩余有效期
+	ttl, err := db.Redis.TTL(c.Request.Context(), db.PrefixedKey(fmt.Sprintf(payment.OrderMerchantIDCacheKeyFormat, req.OrderNo))).Result()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if ttl <= 0 {
+		c.JSON(http.StatusNotFound, util.Err(payment.OrderNotFound))
+		return
+	}
+
+	writeQRCode(c, payment.BuildPayURL(req.OrderNo), &req.QRCodeOptions, ttl)
+}
+
+// GetPaymentLinkQRCode 获取支付链接二维码
+// @Tags qrcode
+// @Produce png
+// @Produce image/svg+xml
+// @Param token path string true "支付链接 Token"
+// @Param format query string false "图片格式：png、svg"
+// @Param size query int false "图片边长（像素），64-1024"
+// @Param level query string false "纠错等级：L、M、Q、H"
+// @Success 200 {file} binary
+// @Router /api/v1/qrcode/payment-links/{token} [get]
+func GetPaymentLinkQRCode(c *gin.Context) {
+	var req QRCodeOptions
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var paymentLink model.MerchantPaymentLink
+	if err := paymentLink.GetByToken(db.DB(c.Request.Context()), c.Param("token")); err != nil {
+		c.JSON(http.StatusNotFound, util.Err(link.PaymentLinkNotFound))
+		return
+	}
+
+	content := fmt.Sprintf("%s/online?token=%s", config.Config.App.FrontendPayURL, url.QueryEscape(paymentLink.Token))
+	writeQRCode(c, content, &req, QRCodeCacheExpiration)
+}
+
+// GetReceiveQRCode 获取当前用户的个人收款码
+// @Tags qrcode
+// @Produce png
+// @Produce image/svg+xml
+// @Param format query string false "图片格式：png、svg"
+// @Param size query int false "图片边长（像素），64-1024"
+// @Param level query string false "纠错等级：L、M、Q、H"
+// @Success 200 {file} binary
+// @Router /api/v1/qrcode/receive [get]
+func GetReceiveQRCode(c *gin.Context) {
+	var req QRCodeOptions
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	query := url.Values{}
+	query.Set("user_id", strconv.FormatUint(user.ID, 10))
+	query.Set("username", user.Username)
+	content := fmt.Sprintf("%s?%s", config.Config.App.FrontendReceiveURL, query.Encode())
+
+	writeQRCode(c, content, &req, QRCodeCacheExpiration)
+}
+
+// GetPaymentRequestQRCode 获取收款请求二维码
+// @Tags qrcode
+// @Produce png
+// @Produce image/svg+xml
+// @Param token path string true "收款请求 Token"
+// @Param format query string false "图片格式：png、svg"
+// @Param size query int false "图片边长（像素），64-1024"
+// @Param level query string false "纠错等级：L、M、Q、H"
+// @Success 200 {file} binary
+// @Router /api/v1/qrcode/payment-requests/{token} [get]
+func GetPaymentRequestQRCode(c *gin.Context) {
+	var req QRCodeOptions
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	var paymentRequest model.PaymentRequest
+	if err := paymentRequest.GetByToken(db.DB(c.Request.Context()), c.Param("token")); err != nil ||
+		paymentRequest.Status != model.PaymentRequestStatusPending {
+		c.JSON(http.StatusNotFound, util.Err(payment_request.PaymentRequestNotFound))
+		return
+	}
+
+	// 二维码缓存时间不超过收款请求剩余有效期
+	ttl := time.Until(paymentRequest.ExpiresAt)
+	if ttl <= 0 {
+		c.JSON(http.StatusNotFound, util.Err(payment_request.PaymentRequestExpired))
+		return
+	}
+	if ttl > QRCodeCacheExpiration {
+		ttl = QRCodeCacheExpiration
+	}
+
+	writeQRCode(c, payment_request.BuildShareURL(paymentRequest.Token), &req, ttl)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qrcode
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/redis/go-redis/v9"
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+// recoveryLevels 纠错等级映射
+var recoveryLevels = map[string]goqrcode.RecoveryLevel{
+	"L": goqrcode.Low,
+	"M": goqrcode.Medium,
+	"Q": goqrcode.High,
+	"H": goqrcode.Highest,
+}
+
+// normalize 填充二维码参数默认值
+func (o *QRCodeOptions) normalize() {
+	if o.Format == "" {
+		o.Format = FormatPNG
+	}
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+	if o.Level == "" {
+		o.Level = "M"
+	}
+}
+
+// contentType 返回图片格式对应的 Content-Type
+func (o *QRCodeOptions) contentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// cacheKey 生成二维码缓存 key，同一内容不同参数分别缓存
+func (o *QRCodeOptions) cacheKey(content string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%s", o.Format, o.Size, o.Level, content)))
+	return db.PrefixedKey(fmt.Sprintf(QRCodeCacheKeyFormat, hex.EncodeToString(hash[:])))
+}
+
+// render 渲染二维码图片
+func render(content string, opts *QRCodeOptions) ([]byte, error) {
+	q, err := goqrcode.New(content, recoveryLevels[opts.Level])
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Format == FormatSVG {
+		return renderSVG(q, opts.Size), nil
+	}
+	return q.PNG(opts.Size)
+}
+
+// renderSVG 将二维码点阵渲染为 SVG，每个深色模块绘制为 1x1 的路径
+func renderSVG(q *goqrcode.QRCode, size int) []byte {
+	bitmap := q.Bitmap()
+	modules := len(bitmap)
+
+	var builder strings.Builder
+	builder.Grow(modules * modules * 4)
+	fmt.Fprintf(&builder,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, modules, modules)
+	fmt.Fprintf(&builder, `<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&builder, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	builder.WriteString(`"/></svg>`)
+
+	return []byte(builder.String())
+}
+
+// getOrRender 优先从 Redis 读取二维码，未命中时渲染并写入缓存
+func getOrRender(ctx context.Context, content string, opts *QRCodeOptions, ttl time.Duration) ([]byte, error) {
+	key := opts.cacheKey(content)
+
+	cached, err := db.Redis.Get(ctx, key).Bytes()
+	if err == nil {
+		return cached, nil
+	} else if !errors.Is(err, redis.Nil) {
+		logger.ErrorF(ctx, "读取二维码缓存失败: %v", err)
+	}
+
+	image, err := render(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if errSet := db.Redis.Set(ctx, key, image, ttl).Err(); errSet != nil {
+		logger.ErrorF(ctx, "写入二维码缓存失败: %v", errSet)
+	}
+
+	return image, nil
+}
+
+// writeQRCode 渲染二维码并写入响应
+func writeQRCode(c *gin.Context, content string, opts *QRCodeOptions, ttl time.Duration) {
+	opts.normalize()
+
+	image, err := getOrRender(c.Request.Context(), content, opts, ttl)
+	if err != nil {
+		logger.ErrorF(c.Request.Context(), "渲染二维码失败: %v", err)
+		c.JSON(http.StatusInternalServerError, util.Err(RenderQRCodeFailed))
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(ttl.Seconds())))
+	c.Data(http.StatusOK, opts.contentType(), image)
+}
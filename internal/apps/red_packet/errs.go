@@ -0,0 +1,27 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package red_packet
+
+const (
+	RedPacketNotFound      = "红包不存在"
+	RedPacketExpired       = "红包已过期"
+	RedPacketFinished      = "红包已被领完"
+	AlreadyClaimed         = "您已领取过该红包"
+	CannotClaimOwnPacket   = "不能领取自己发送的红包"
+	AmountTooSmallToSplit  = "红包金额不足以按份数分配，每份至少0.01"
+	RedPacketClaimConflict = "领取人数较多，请稍后重试"
+)
@@ -0,0 +1,425 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package red_packet
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RedPacketDetail 红包详情
+type RedPacketDetail struct {
+	model.RedPacket
+	ShareURL string                `json:"share_url"`
+	MyClaim  *model.RedPacketClaim `json:"my_claim"`
+}
+
+// CreateRedPacketRequest 发红包请求
+type CreateRedPacketRequest struct {
+	TotalAmount decimal.Decimal `json:"total_amount" binding:"required"`
+	Shares      int             `json:"shares" binding:"required,min=1,max=1000"`
+	SplitType   string          `json:"split_type" binding:"required,oneof=equal random"`
+	Greeting    string          `json:"greeting" binding:"max=100"`
+	PayKey      string          `json:"pay_key" binding:"required,max=6"`
+	TOTPCode    string          `json:"totp_code" binding:"omitempty,max=16"`
+}
+
+// CreateRedPacket 发红包
+// @Tags red_packet
+// @Accept json
+// @Produce json
+// @Param request body CreateRedPacketRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packet [post]
+func CreateRedPacket(c *gin.Context) {
+	var req CreateRedPacketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.TotalAmount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+
+	if req.TotalAmount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	if req.TotalAmount.Shift(2).IntPart() < int64(req.Shares)*minShareCents {
+		c.JSON(http.StatusBadRequest, util.Err(AmountTooSmallToSplit))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if !service.CheckPayKey(c, currentUser, req.PayKey) {
+		return
+	}
+
+	if !service.CheckTOTPForAmount(c, currentUser, req.TotalAmount, req.TOTPCode) {
+		return
+	}
+
+	// 获取发送者的支付配置
+	var senderPayConfig model.UserPayConfig
+	if err := senderPayConfig.GetByPayScore(db.DB(c.Request.Context()), currentUser.PayScore); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	expireHours, err := model.GetIntByKey(c.Request.Context(), model.ConfigKeyRedPacketExpireHours)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	redPacket := model.RedPacket{
+		Token:           util.GenerateUniqueIDSimple(),
+		SenderUserID:    currentUser.ID,
+		TotalAmount:     req.TotalAmount,
+		TotalShares:     req.Shares,
+		SplitType:       model.RedPacketSplitType(req.SplitType),
+		RemainingAmount: req.TotalAmount,
+		RemainingShares: req.Shares,
+		RefundedAmount:  decimal.Zero,
+		Greeting:        req.Greeting,
+		Status:          model.RedPacketStatusActive,
+		ExpiresAt:       time.Now().Add(time.Duration(expireHours) * time.Hour),
+		SenderUsername:  currentUser.Username,
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 红包总金额计入转账限额
+			if err := service.CheckTransferLimit(tx, currentUser, req.TotalAmount, &senderPayConfig); err != nil {
+				return err
+			}
+
+			// 从发送者余额中冻结红包总金额
+			if err := service.DebitTransferPayer(tx, currentUser.ID, req.TotalAmount); err != nil {
+				return err
+			}
+
+			if err := tx.Create(&redPacket).Error; err != nil {
+				return err
+			}
+
+			// 风控评估，红包没有确定的收款方
+			_, err := risk.Evaluate(c.Request.Context(), tx, &risk.Event{
+				Scene:       risk.SceneTransfer,
+				User:        currentUser,
+				Amount:      req.TotalAmount,
+				ReferenceID: &redPacket.ID,
+			})
+			return err
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch errMsg {
+		case common.InsufficientBalance, common.TransferAmountExceedsMax, common.TransferDailyLimitExceeded,
+			common.TransferMonthlyLimitExceeded, common.TransferAccountTooNew:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case common.RiskBlocked:
+			c.JSON(http.StatusForbidden, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	stream.PublishBalance(c.Request.Context(), currentUser.ID)
+
+	c.JSON(http.StatusOK, util.OK(RedPacketDetail{
+		RedPacket: redPacket,
+		ShareURL:  BuildShareURL(redPacket.Token),
+	}))
+}
+
+// GetRedPacket 通过 Token 查询红包详情
+// @Tags red_packet
+// @Produce json
+// @Param token path string true "红包 Token"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packet/{token} [get]
+func GetRedPacket(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var detail RedPacketDetail
+	if err := db.DB(c.Request.Context()).Model(&model.RedPacket{}).
+		Select("red_packets.*, sender_user.username as sender_username").
+		Joins("JOIN users as sender_user ON red_packets.sender_user_id = sender_user.id").
+		Where("red_packets.token = ?", c.Param("token")).
+		First(&detail.RedPacket).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RedPacketNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+	detail.ShareURL = BuildShareURL(detail.Token)
+
+	var claim model.RedPacketClaim
+	if err := db.DB(c.Request.Context()).
+		Where("red_packet_id = ? AND user_id = ?", detail.ID, user.ID).
+		First(&claim).Error; err == nil {
+		detail.MyClaim = &claim
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(detail))
+}
+
+// ClaimRedPacketRequest 领红包请求
+type ClaimRedPacketRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ClaimRedPacket 领红包
+// @Tags red_packet
+// @Accept json
+// @Produce json
+// @Param request body ClaimRedPacketRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packet/claim [post]
+func ClaimRedPacket(c *gin.Context) {
+	var req ClaimRedPacketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var claim model.RedPacketClaim
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			// 行锁保证同一红包的领取串行执行，避免超领
+			var redPacket model.RedPacket
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("token = ?", req.Token).
+				First(&redPacket).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(RedPacketNotFound)
+				}
+				return err
+			}
+
+			if redPacket.SenderUserID == currentUser.ID {
+				return errors.New(CannotClaimOwnPacket)
+			}
+
+			var claimedCount int64
+			if err := tx.Model(&model.RedPacketClaim{}).
+				Where("red_packet_id = ? AND user_id = ?", redPacket.ID, currentUser.ID).
+				Count(&claimedCount).Error; err != nil {
+				return err
+			}
+			if claimedCount > 0 {
+				return errors.New(AlreadyClaimed)
+			}
+
+			if redPacket.Status == model.RedPacketStatusExpired || !time.Now().Before(redPacket.ExpiresAt) {
+				return errors.New(RedPacketExpired)
+			}
+			if redPacket.Status != model.RedPacketStatusActive || redPacket.RemainingShares <= 0 {
+				return errors.New(RedPacketFinished)
+			}
+
+			amount := nextShareAmount(&redPacket)
+
+			now := time.Now()
+			order := model.Order{
+				OrderName:   "红包",
+				PayerUserID: redPacket.SenderUserID,
+				PayeeUserID: currentUser.ID,
+				Amount:      amount,
+				Status:      model.OrderStatusSuccess,
+				Type:        model.OrderTypeTransfer,
+				Remark:      redPacket.Greeting,
+				TradeTime:   now,
+				ExpiresAt:   now.Add(24 * time.Hour),
+			}
+			if err := service.CreditTransferPayee(tx, &order); err != nil {
+				return err
+			}
+
+			claim = model.RedPacketClaim{
+				RedPacketID: redPacket.ID,
+				UserID:      currentUser.ID,
+				Amount:      amount,
+				OrderID:     order.ID,
+				Username:    currentUser.Username,
+				AvatarUrl:   currentUser.AvatarUrl,
+			}
+			if err := tx.Create(&claim).Error; err != nil {
+				return err
+			}
+
+			updates := map[string]interface{}{
+				"remaining_amount": gorm.Expr("remaining_amount - ?", amount),
+				"remaining_shares": gorm.Expr("remaining_shares - 1"),
+			}
+			finished := redPacket.RemainingShares == 1
+			if finished {
+				updates["status"] = model.RedPacketStatusFinished
+			}
+			if err := tx.Model(&redPacket).UpdateColumns(updates).Error; err != nil {
+				return err
+			}
+
+			if !finished {
+				return nil
+			}
+			return service.Notify(
+				tx,
+				redPacket.SenderUserID,
+				model.NotificationCategoryRedPacket,
+				"红包已被领完",
+				fmt.Sprintf("您发送的 %s 红包（%d份）已被领完", redPacket.TotalAmount.StringFixed(2), redPacket.TotalShares),
+				&redPacket.ID,
+			)
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch {
+		case errMsg == RedPacketNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		case errMsg == CannotClaimOwnPacket, errMsg == AlreadyClaimed, errMsg == RedPacketExpired, errMsg == RedPacketFinished:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		case strings.Contains(errMsg, "SQLSTATE 23505"):
+			c.JSON(http.StatusBadRequest, util.Err(AlreadyClaimed))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	// 红包金额在发送时已从发送者余额扣除，领取只影响领取者余额
+	stream.PublishBalance(c.Request.Context(), currentUser.ID)
+
+	c.JSON(http.StatusOK, util.OK(claim))
+}
+
+// ListRedPacketClaims 查询红包领取记录
+// @Tags red_packet
+// @Produce json
+// @Param token path string true "红包 Token"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packet/{token}/claims [get]
+func ListRedPacketClaims(c *gin.Context) {
+	var redPacket model.RedPacket
+	if err := redPacket.GetByToken(db.DB(c.Request.Context()), c.Param("token")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(RedPacketNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	var claims []model.RedPacketClaim
+	if err := db.DB(c.Request.Context()).Model(&model.RedPacketClaim{}).
+		Select("red_packet_claims.*, users.username, users.avatar_url").
+		Joins("JOIN users ON red_packet_claims.user_id = users.id").
+		Where("red_packet_claims.red_packet_id = ?", redPacket.ID).
+		Order("red_packet_claims.created_at ASC").
+		Find(&claims).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(claims))
+}
+
+// ListRedPacketsRequest 查询已发红包列表请求
+type ListRedPacketsRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=active finished expired"`
+}
+
+// ListRedPacketsResponse 查询已发红包列表响应
+type ListRedPacketsResponse struct {
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	RedPackets []model.RedPacket `json:"red_packets"`
+}
+
+// ListRedPackets 查询当前用户发送的红包
+// @Tags red_packet
+// @Accept json
+// @Produce json
+// @Param request body ListRedPacketsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/red-packet/list [post]
+func ListRedPackets(c *gin.Context) {
+	var req ListRedPacketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.RedPacket{}).Where("sender_user_id = ?", user.ID)
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("status = ?", model.RedPacketStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListRedPacketsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.RedPackets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
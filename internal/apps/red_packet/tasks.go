@@ -0,0 +1,118 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package red_packet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleRefundExpiredRedPackets 将过期红包的剩余金额退回发送者
+func HandleRefundExpiredRedPackets(ctx context.Context, t *asynq.Task) error {
+	pageSize := 500
+	lastID := uint64(0)
+	now := time.Now()
+
+	for {
+		var redPackets []model.RedPacket
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND expires_at <= ?", lastID, model.RedPacketStatusActive, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&redPackets).Error; err != nil {
+			logger.ErrorF(ctx, "查询过期红包失败: %v", err)
+			return err
+		}
+
+		if len(redPackets) == 0 {
+			break
+		}
+
+		for _, redPacket := range redPackets {
+			if err := refundRedPacket(ctx, redPacket.ID); err != nil {
+				logger.ErrorF(ctx, "红包[ID:%d]退款失败: %v", redPacket.ID, err)
+				return err
+			}
+		}
+
+		lastID = redPackets[len(redPackets)-1].ID
+	}
+	return nil
+}
+
+// refundRedPacket 退回单个过期红包的剩余金额
+func refundRedPacket(ctx context.Context, redPacketID uint64) error {
+	var refundedUserID uint64
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var redPacket model.RedPacket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", redPacketID, model.RedPacketStatusActive).
+			First(&redPacket).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		remaining := redPacket.RemainingAmount
+		if remaining.GreaterThan(decimal.Zero) {
+			if err := service.RefundTransferPayer(tx, redPacket.SenderUserID, remaining); err != nil {
+				return err
+			}
+			refundedUserID = redPacket.SenderUserID
+		}
+
+		if err := tx.Model(&redPacket).Updates(map[string]interface{}{
+			"status":           model.RedPacketStatusExpired,
+			"refunded_amount":  remaining,
+			"remaining_amount": decimal.Zero,
+		}).Error; err != nil {
+			return err
+		}
+
+		logger.InfoF(ctx, "红包[ID:%d]已过期，退回剩余金额 %s", redPacket.ID, remaining.StringFixed(2))
+
+		return service.Notify(
+			tx,
+			redPacket.SenderUserID,
+			model.NotificationCategoryRedPacket,
+			"红包已过期",
+			fmt.Sprintf("您发送的红包已过期，%d份中%d份未被领取，剩余 %s 已退回余额",
+				redPacket.TotalShares, redPacket.RemainingShares, remaining.StringFixed(2)),
+			&redPacket.ID,
+		)
+	}); err != nil {
+		return err
+	}
+
+	if refundedUserID != 0 {
+		stream.PublishBalance(ctx, refundedUserID)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package red_packet
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// minShareCents 每份红包最小金额（分）
+const minShareCents = 1
+
+// BuildShareURL 构建红包领取链接
+func BuildShareURL(token string) string {
+	return fmt.Sprintf("%s?token=%s", config.Config.App.FrontendRedPacketURL, url.QueryEscape(token))
+}
+
+// nextShareAmount 计算下一份红包金额
+// 最后一份领取全部剩余金额；等额红包按总额均分，余数归最后一份；
+// 随机红包采用二倍均值法，保证剩余每份至少 0.01
+func nextShareAmount(redPacket *model.RedPacket) decimal.Decimal {
+	remainingCents := redPacket.RemainingAmount.Shift(2).IntPart()
+	if redPacket.RemainingShares <= 1 {
+		return decimal.New(remainingCents, -2)
+	}
+
+	var cents int64
+	switch redPacket.SplitType {
+	case model.RedPacketSplitTypeEqual:
+		cents = redPacket.TotalAmount.Shift(2).IntPart() / int64(redPacket.TotalShares)
+	default:
+		maxCents := remainingCents * 2 / int64(redPacket.RemainingShares)
+		cents = minShareCents
+		if maxCents > minShareCents {
+			cents = minShareCents + rand.Int64N(maxCents-minShareCents)
+		}
+	}
+
+	// 为剩余份数保留最小金额
+	upperCents := remainingCents - int64(redPacket.RemainingShares-1)*minShareCents
+	if cents > upperCents {
+		cents = upperCents
+	}
+	if cents < minShareCents {
+		cents = minShareCents
+	}
+
+	return decimal.New(cents, -2)
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduled_transfer
+
+import "time"
+
+const (
+	// MinRecurringInterval 周期转账最小执行间隔
+	MinRecurringInterval = time.Hour
+	// MaxConsecutiveFailures 连续失败次数达到该值后自动暂停
+	MaxConsecutiveFailures = 3
+	// ExecuteTaskIDFormat 单次执行任务 ID，用于避免同一计划时间重复下发
+	ExecuteTaskIDFormat = "scheduled_transfer:%d:%d"
+)
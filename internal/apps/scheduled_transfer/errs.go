@@ -0,0 +1,26 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduled_transfer
+
+const (
+	ScheduledTransferNotFound = "定时转账不存在"
+	ScheduleRequired          = "必须且只能指定执行时间或 cron 表达式其中之一"
+	RunAtMustBeFuture         = "执行时间必须晚于当前时间"
+	CronExprInvalid           = "cron 表达式格式错误"
+	CronIntervalTooShort      = "周期转账的执行间隔不能少于1小时"
+	StatusNotAllowed          = "当前状态不允许该操作"
+)
@@ -0,0 +1,371 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduled_transfer
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/apps/payment"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateScheduledTransferRequest 创建定时转账请求
+type CreateScheduledTransferRequest struct {
+	RecipientID       uint64          `json:"recipient_id" binding:"required"`
+	RecipientUsername string          `json:"recipient_username" binding:"required"`
+	Amount            decimal.Decimal `json:"amount" binding:"required"`
+	PayKey            string          `json:"pay_key" binding:"required,max=6"`
+	TOTPCode          string          `json:"totp_code" binding:"omitempty,max=16"`
+	Remark            string          `json:"remark" binding:"max=100"`
+	RunAt             *time.Time      `json:"run_at"`
+	CronExpr          string          `json:"cron_expr" binding:"omitempty,max=64"`
+}
+
+// CreateScheduledTransfer 创建定时转账（一次性 run_at 或周期 cron_expr）
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body CreateScheduledTransferRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/scheduled-transfer [post]
+func CreateScheduledTransfer(c *gin.Context) {
+	var req CreateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.Amount.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountMustBeGreaterThanZero))
+		return
+	}
+
+	if req.Amount.Exponent() < -2 {
+		c.JSON(http.StatusBadRequest, util.Err(common.AmountDecimalPlacesExceeded))
+		return
+	}
+
+	if (req.RunAt == nil) == (req.CronExpr == "") {
+		c.JSON(http.StatusBadRequest, util.Err(ScheduleRequired))
+		return
+	}
+
+	// 计算首次执行时间
+	var firstRunAt time.Time
+	if req.RunAt != nil {
+		if !req.RunAt.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, util.Err(RunAtMustBeFuture))
+			return
+		}
+		firstRunAt = *req.RunAt
+	} else {
+		schedule, err := parseCronExpr(req.CronExpr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+		firstRunAt = schedule.Next(time.Now().In(scheduleLocation))
+	}
+
+	currentUser, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if !service.CheckPayKey(c, currentUser, req.PayKey) {
+		return
+	}
+
+	if !service.CheckTOTPForAmount(c, currentUser, req.Amount, req.TOTPCode) {
+		return
+	}
+
+	if currentUser.ID == req.RecipientID && currentUser.Username == req.RecipientUsername {
+		c.JSON(http.StatusBadRequest, util.Err(payment.CannotTransferToSelf))
+		return
+	}
+
+	// 验证收款人是否存在且用户名匹配
+	var recipient model.User
+	if err := db.DB(c.Request.Context()).Where("id = ? AND username = ?", req.RecipientID, req.RecipientUsername).First(&recipient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(payment.RecipientNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	scheduledTransfer := model.ScheduledTransfer{
+		PayerUserID:       currentUser.ID,
+		RecipientUserID:   recipient.ID,
+		Amount:            req.Amount,
+		Remark:            req.Remark,
+		CronExpr:          req.CronExpr,
+		Status:            model.ScheduledTransferStatusActive,
+		NextRunAt:         &firstRunAt,
+		RecipientUsername: recipient.Username,
+	}
+
+	if err := db.DB(c.Request.Context()).Create(&scheduledTransfer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(scheduledTransfer))
+}
+
+// ListScheduledTransfersRequest 查询定时转账列表请求
+type ListScheduledTransfersRequest struct {
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+	Status   string `json:"status" form:"status" binding:"omitempty,oneof=active paused canceled completed"`
+}
+
+// ListScheduledTransfersResponse 查询定时转账列表响应
+type ListScheduledTransfersResponse struct {
+	Total              int64                     `json:"total"`
+	Page               int                       `json:"page"`
+	PageSize           int                       `json:"page_size"`
+	ScheduledTransfers []model.ScheduledTransfer `json:"scheduled_transfers"`
+}
+
+// ListScheduledTransfers 查询当前用户的定时转账
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ListScheduledTransfersRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/scheduled-transfers [post]
+func ListScheduledTransfers(c *gin.Context) {
+	var req ListScheduledTransfersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.ScheduledTransfer{}).
+		Select("scheduled_transfers.*, recipient_user.username as recipient_username").
+		Joins("JOIN users as recipient_user ON scheduled_transfers.recipient_user_id = recipient_user.id").
+		Where("scheduled_transfers.payer_user_id = ?", user.ID)
+
+	if req.Status != "" {
+		baseQuery = baseQuery.Where("scheduled_transfers.status = ?", model.ScheduledTransferStatus(req.Status))
+	}
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListScheduledTransfersResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("scheduled_transfers.created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.ScheduledTransfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
+
+// ScheduledTransferIDRequest 定时转账操作请求
+type ScheduledTransferIDRequest struct {
+	ID uint64 `json:"id" binding:"required"`
+}
+
+// PauseScheduledTransfer 暂停定时转账
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ScheduledTransferIDRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/scheduled-transfer/pause [post]
+func PauseScheduledTransfer(c *gin.Context) {
+	changeStatus(c, []model.ScheduledTransferStatus{model.ScheduledTransferStatusActive}, model.ScheduledTransferStatusPaused)
+}
+
+// ResumeScheduledTransfer 恢复已暂停的定时转账
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ScheduledTransferIDRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/scheduled-transfer/resume [post]
+func ResumeScheduledTransfer(c *gin.Context) {
+	changeStatus(c, []model.ScheduledTransferStatus{model.ScheduledTransferStatusPaused}, model.ScheduledTransferStatusActive)
+}
+
+// CancelScheduledTransfer 取消定时转账
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ScheduledTransferIDRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/scheduled-transfer/cancel [post]
+func CancelScheduledTransfer(c *gin.Context) {
+	changeStatus(c, []model.ScheduledTransferStatus{model.ScheduledTransferStatusActive, model.ScheduledTransferStatusPaused}, model.ScheduledTransferStatusCanceled)
+}
+
+// changeStatus 变更定时转账状态
+func changeStatus(c *gin.Context, fromStatuses []model.ScheduledTransferStatus, toStatus model.ScheduledTransferStatus) {
+	var req ScheduledTransferIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if err := db.DB(c.Request.Context()).Transaction(
+		func(tx *gorm.DB) error {
+			var scheduledTransfer model.ScheduledTransfer
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+				Where("id = ? AND payer_user_id = ?", req.ID, user.ID).
+				First(&scheduledTransfer).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New(ScheduledTransferNotFound)
+				}
+				return err
+			}
+
+			allowed := false
+			for _, status := range fromStatuses {
+				if scheduledTransfer.Status == status {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return errors.New(StatusNotAllowed)
+			}
+
+			updates := map[string]interface{}{"status": toStatus}
+			switch toStatus {
+			case model.ScheduledTransferStatusActive:
+				// 周期转账恢复后从当前时间重新计算下一次执行时间，一次性转账保持原执行时间
+				updates["consecutive_failures"] = 0
+				if scheduledTransfer.IsRecurring() {
+					next, err := nextRunAt(scheduledTransfer.CronExpr, time.Now())
+					if err != nil {
+						return err
+					}
+					updates["next_run_at"] = next
+				}
+			case model.ScheduledTransferStatusCanceled:
+				updates["next_run_at"] = nil
+			}
+
+			return tx.Model(&scheduledTransfer).Updates(updates).Error
+		},
+	); err != nil {
+		errMsg := err.Error()
+		switch errMsg {
+		case ScheduledTransferNotFound:
+			c.JSON(http.StatusNotFound, util.Err(errMsg))
+		case StatusNotAllowed:
+			c.JSON(http.StatusBadRequest, util.Err(errMsg))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(errMsg))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// ListScheduledTransferRunsRequest 查询定时转账执行记录请求
+type ListScheduledTransferRunsRequest struct {
+	ID       uint64 `json:"id" binding:"required"`
+	Page     int    `json:"page" form:"page" binding:"min=1"`
+	PageSize int    `json:"page_size" form:"page_size" binding:"min=1,max=100"`
+}
+
+// ListScheduledTransferRunsResponse 查询定时转账执行记录响应
+type ListScheduledTransferRunsResponse struct {
+	Total    int64                        `json:"total"`
+	Page     int                          `json:"page"`
+	PageSize int                          `json:"page_size"`
+	Runs     []model.ScheduledTransferRun `json:"runs"`
+}
+
+// ListScheduledTransferRuns 查询定时转账执行记录
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param request body ListScheduledTransferRunsRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/payment/scheduled-transfer/runs [post]
+func ListScheduledTransferRuns(c *gin.Context) {
+	var req ListScheduledTransferRunsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var scheduledTransfer model.ScheduledTransfer
+	if err := db.DB(c.Request.Context()).
+		Where("id = ? AND payer_user_id = ?", req.ID, user.ID).
+		First(&scheduledTransfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, util.Err(ScheduledTransferNotFound))
+		} else {
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	baseQuery := db.DB(c.Request.Context()).Model(&model.ScheduledTransferRun{}).
+		Where("scheduled_transfer_id = ?", scheduledTransfer.ID)
+
+	var total int64
+	if err := baseQuery.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	response := &ListScheduledTransferRunsResponse{
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := baseQuery.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&response.Runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(response))
+}
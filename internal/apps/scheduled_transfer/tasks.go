@@ -0,0 +1,263 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduled_transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/apps/payment"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
+	"github.com/linux-do/credit/internal/task"
+	"github.com/linux-do/credit/internal/task/scheduler"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleDispatchScheduledTransfers 扫描到期的定时转账并下发执行任务
+func HandleDispatchScheduledTransfers(ctx context.Context, t *asynq.Task) error {
+	pageSize := 500
+	lastID := uint64(0)
+	now := time.Now()
+
+	for {
+		var scheduledTransfers []model.ScheduledTransfer
+		if err := db.DB(ctx).
+			Where("id > ? AND status = ? AND next_run_at <= ?", lastID, model.ScheduledTransferStatusActive, now).
+			Order("id ASC").
+			Limit(pageSize).
+			Find(&scheduledTransfers).Error; err != nil {
+			logger.ErrorF(ctx, "查询到期定时转账失败: %v", err)
+			return err
+		}
+
+		if len(scheduledTransfers) == 0 {
+			break
+		}
+
+		for _, scheduledTransfer := range scheduledTransfers {
+			payload, _ := json.Marshal(map[string]interface{}{
+				"scheduled_transfer_id": scheduledTransfer.ID,
+			})
+
+			if _, errTask := scheduler.AsynqClient.Enqueue(
+				asynq.NewTask(task.ExecuteScheduledTransferTask, payload),
+				asynq.TaskID(fmt.Sprintf(ExecuteTaskIDFormat, scheduledTransfer.ID, scheduledTransfer.NextRunAt.Unix())),
+				asynq.MaxRetry(5),
+			); errTask != nil {
+				if errors.Is(errTask, asynq.ErrTaskIDConflict) {
+					continue
+				}
+				logger.ErrorF(ctx, "下发定时转账[ID:%d]执行任务失败: %v", scheduledTransfer.ID, errTask)
+				return errTask
+			}
+		}
+
+		lastID = scheduledTransfers[len(scheduledTransfers)-1].ID
+	}
+	return nil
+}
+
+// HandleExecuteScheduledTransfer 执行单个定时转账
+// 余额不足、超出转账限额等业务失败会记录失败的执行记录并推进到下一次执行，其余错误交由任务重试
+func HandleExecuteScheduledTransfer(ctx context.Context, t *asynq.Task) error {
+	var payload struct {
+		ScheduledTransferID uint64 `json:"scheduled_transfer_id"`
+	}
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("解析任务参数失败: %w", err)
+	}
+
+	var settled *model.ScheduledTransfer
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var scheduledTransfer model.ScheduledTransfer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", payload.ScheduledTransferID, model.ScheduledTransferStatusActive).
+			First(&scheduledTransfer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.InfoF(ctx, "定时转账[ID:%d]已暂停、取消或不存在，跳过", payload.ScheduledTransferID)
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now()
+		if scheduledTransfer.NextRunAt == nil || scheduledTransfer.NextRunAt.After(now) {
+			return nil
+		}
+
+		run := model.ScheduledTransferRun{
+			ScheduledTransferID: scheduledTransfer.ID,
+			Amount:              scheduledTransfer.Amount,
+			Status:              model.ScheduledTransferRunStatusSuccess,
+			ScheduledAt:         *scheduledTransfer.NextRunAt,
+		}
+
+		failureReason, err := executeTransfer(ctx, tx, &scheduledTransfer, &run)
+		if err != nil {
+			return err
+		}
+		if failureReason != "" {
+			run.Status = model.ScheduledTransferRunStatusFailed
+			run.FailureReason = failureReason
+		}
+
+		if err := tx.Create(&run).Error; err != nil {
+			return err
+		}
+		if run.OrderID != nil {
+			settled = &scheduledTransfer
+		}
+
+		return advanceSchedule(tx, &scheduledTransfer, &run, now)
+	}); err != nil {
+		return err
+	}
+
+	if settled != nil {
+		stream.PublishBalance(ctx, settled.PayerUserID, settled.RecipientUserID)
+	}
+	return nil
+}
+
+// executeTransfer 执行转账，返回业务失败原因
+func executeTransfer(ctx context.Context, tx *gorm.DB, scheduledTransfer *model.ScheduledTransfer, run *model.ScheduledTransferRun) (string, error) {
+	var payer model.User
+	if err := payer.GetByID(tx, scheduledTransfer.PayerUserID); err != nil {
+		return "", err
+	}
+	if err := payer.CheckActive(); err != nil {
+		return err.Error(), nil
+	}
+
+	var recipient model.User
+	if err := recipient.GetByID(tx, scheduledTransfer.RecipientUserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return payment.RecipientNotFound, nil
+		}
+		return "", err
+	}
+
+	var payerPayConfig model.UserPayConfig
+	if err := payerPayConfig.GetByPayScore(tx, payer.PayScore); err != nil {
+		return "", err
+	}
+
+	// 使用保存点执行转账，业务失败时仅回滚转账部分
+	if err := tx.Transaction(func(transferTx *gorm.DB) error {
+		if err := service.CheckTransferLimit(transferTx, &payer, scheduledTransfer.Amount, &payerPayConfig); err != nil {
+			return err
+		}
+
+		if _, err := risk.Evaluate(ctx, transferTx, &risk.Event{
+			Scene:              risk.SceneTransfer,
+			User:               &payer,
+			CounterpartyUserID: recipient.ID,
+			Amount:             scheduledTransfer.Amount,
+			ReferenceID:        &scheduledTransfer.ID,
+		}); err != nil {
+			return err
+		}
+
+		order, err := service.SettleTransfer(transferTx, &service.TransferParams{
+			PayerUserID: payer.ID,
+			PayeeUserID: recipient.ID,
+			Amount:      scheduledTransfer.Amount,
+			OrderName:   "定时转账",
+			Remark:      scheduledTransfer.Remark,
+		})
+		if err != nil {
+			return err
+		}
+		run.OrderID = &order.ID
+		return nil
+	}); err != nil {
+		switch errMsg := err.Error(); errMsg {
+		case common.InsufficientBalance, common.TransferAmountExceedsMax, common.TransferDailyLimitExceeded,
+			common.TransferMonthlyLimitExceeded, common.TransferAccountTooNew, common.RiskBlocked:
+			return errMsg, nil
+		default:
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// advanceSchedule 推进定时转账到下一次执行，并在失败时通知付款人
+func advanceSchedule(tx *gorm.DB, scheduledTransfer *model.ScheduledTransfer, run *model.ScheduledTransferRun, now time.Time) error {
+	updates := map[string]interface{}{
+		"last_run_at": now,
+		"run_count":   gorm.Expr("run_count + 1"),
+	}
+
+	failed := run.Status == model.ScheduledTransferRunStatusFailed
+	consecutiveFailures := 0
+	if failed {
+		consecutiveFailures = scheduledTransfer.ConsecutiveFailures + 1
+	}
+	updates["consecutive_failures"] = consecutiveFailures
+
+	paused := false
+	if !scheduledTransfer.IsRecurring() {
+		updates["status"] = model.ScheduledTransferStatusCompleted
+		updates["next_run_at"] = nil
+	} else {
+		next, err := nextRunAt(scheduledTransfer.CronExpr, now)
+		if err != nil {
+			return err
+		}
+		updates["next_run_at"] = next
+		if next == nil {
+			updates["status"] = model.ScheduledTransferStatusCompleted
+		} else if consecutiveFailures >= MaxConsecutiveFailures {
+			updates["status"] = model.ScheduledTransferStatusPaused
+			paused = true
+		}
+	}
+
+	if err := tx.Model(scheduledTransfer).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if !failed {
+		return nil
+	}
+
+	content := fmt.Sprintf("定时转账 %s 执行失败：%s", scheduledTransfer.Amount.StringFixed(2), run.FailureReason)
+	if paused {
+		content += fmt.Sprintf("，已连续失败%d次，计划已自动暂停", consecutiveFailures)
+	}
+	return service.Notify(
+		tx,
+		scheduledTransfer.PayerUserID,
+		model.NotificationCategoryScheduledTransfer,
+		"定时转账执行失败",
+		content,
+		&scheduledTransfer.ID,
+	)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduled_transfer
+
+import (
+	"errors"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser 标准 5 段 cron 表达式解析器（分 时 日 月 周）
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// scheduleLocation cron 表达式使用的时区，与定时任务调度器保持一致
+var scheduleLocation = loadScheduleLocation()
+
+func loadScheduleLocation() *time.Location {
+	location, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		return time.FixedZone("CST", 8*60*60)
+	}
+	return location
+}
+
+// parseCronExpr 解析 cron 表达式并校验执行间隔
+func parseCronExpr(expr string) (cron.Schedule, error) {
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, errors.New(CronExprInvalid)
+	}
+
+	first := schedule.Next(time.Now().In(scheduleLocation))
+	if first.IsZero() {
+		return nil, errors.New(CronExprInvalid)
+	}
+	if schedule.Next(first).Sub(first) < MinRecurringInterval {
+		return nil, errors.New(CronIntervalTooShort)
+	}
+
+	return schedule, nil
+}
+
+// nextRunAt 计算 from 之后的下一次执行时间
+func nextRunAt(expr string, from time.Time) (*time.Time, error) {
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	next := schedule.Next(from.In(scheduleLocation))
+	if next.IsZero() {
+		return nil, nil
+	}
+	return &next, nil
+}
@@ -0,0 +1,26 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package totp
+
+const (
+	// Issuer 认证器应用中显示的签发方名称
+	Issuer = "LINUX DO Credit"
+	// RecoveryCodeCount 每次生成的恢复码数量
+	RecoveryCodeCount = 10
+	// recoveryCodeBytes 单个恢复码的随机字节数（base32 编码后取前 10 个字符）
+	recoveryCodeBytes = 7
+)
@@ -0,0 +1,25 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package totp
+
+const (
+	TOTPAlreadyEnabled  = "已启用 TOTP，请先停用后再重新绑定"
+	TOTPNotEnrolled     = "请先生成 TOTP 密钥"
+	TOTPNotEnabled      = "尚未启用 TOTP"
+	InvalidThreshold    = "支付阈值不能为负数且最多保留两位小数"
+	EncryptSecretFailed = "加密 TOTP 密钥失败"
+)
@@ -0,0 +1,373 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package totp
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StatusResponse TOTP 状态响应
+type StatusResponse struct {
+	Enabled                bool             `json:"enabled"`
+	EnabledAt              *time.Time       `json:"enabled_at"`
+	PaymentThreshold       *decimal.Decimal `json:"payment_threshold"`
+	RecoveryCodesRemaining int64            `json:"recovery_codes_remaining"`
+}
+
+// EnrollResponse 生成 TOTP 密钥响应
+type EnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauth_url"`
+}
+
+// RecoveryCodesResponse 恢复码响应，明文仅返回一次
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ActivateRequest 启用 TOTP 请求
+type ActivateRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// CodeRequest 需要 TOTP 验证码或恢复码的请求
+type CodeRequest struct {
+	TOTPCode string `json:"totp_code" binding:"required,max=16"`
+}
+
+// UpdateThresholdRequest 更新支付阈值请求，payment_threshold 为空表示支付时不要求 TOTP
+type UpdateThresholdRequest struct {
+	PaymentThreshold *decimal.Decimal `json:"payment_threshold"`
+	TOTPCode         string           `json:"totp_code" binding:"required,max=16"`
+}
+
+// GetStatus 查询当前用户的 TOTP 状态
+// @Tags totp
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/totp [get]
+func GetStatus(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	totp, err := service.GetEnabledTOTP(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	if totp == nil {
+		c.JSON(http.StatusOK, util.OK(StatusResponse{}))
+		return
+	}
+
+	var remaining int64
+	if err := db.DB(c.Request.Context()).
+		Model(&model.UserTOTPRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", user.ID).
+		Count(&remaining).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(StatusResponse{
+		Enabled:                true,
+		EnabledAt:              totp.EnabledAt,
+		PaymentThreshold:       totp.PaymentThreshold,
+		RecoveryCodesRemaining: remaining,
+	}))
+}
+
+// Enroll 生成待绑定的 TOTP 密钥，需调用 Activate 验证后才会生效
+// @Tags totp
+// @Produce json
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/totp/enroll [post]
+func Enroll(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+	encryptedSecret, err := service.SealTOTPSecret(secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(EncryptSecretFailed))
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var totp model.UserTOTP
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", user.ID).
+			First(&totp).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			return tx.Create(&model.UserTOTP{UserID: user.ID, Secret: encryptedSecret}).Error
+		}
+		if totp.Enabled() {
+			return errors.New(TOTPAlreadyEnabled)
+		}
+
+		return tx.Model(&totp).Update("secret", encryptedSecret).Error
+	}); err != nil {
+		if err.Error() == TOTPAlreadyEnabled {
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(EnrollResponse{
+		Secret:     secret,
+		OtpauthURL: util.TOTPProvisioningURI(Issuer, user.Username, secret),
+	}))
+}
+
+// Activate 使用认证器应用生成的验证码完成绑定，返回一组恢复码
+// @Tags totp
+// @Accept json
+// @Produce json
+// @Param request body ActivateRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/totp/activate [post]
+func Activate(c *gin.Context) {
+	var req ActivateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	var codes []string
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var totp model.UserTOTP
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("user_id = ?", user.ID).
+			First(&totp).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New(TOTPNotEnrolled)
+			}
+			return err
+		}
+		if totp.Enabled() {
+			return errors.New(TOTPAlreadyEnabled)
+		}
+
+		secret, err := service.OpenTOTPSecret(totp.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := util.ValidateTOTP(secret, req.Code, time.Now())
+		if !ok {
+			return errors.New(common.TOTPCodeIncorrect)
+		}
+
+		now := time.Now()
+		if err := tx.Model(&totp).Updates(map[string]interface{}{
+			"enabled_at":     now,
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+
+		if codes, err = regenerateRecoveryCodes(tx, user.ID); err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionUserTOTPEnable,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			After:      map[string]interface{}{"enabled_at": now},
+		})
+	}); err != nil {
+		switch err.Error() {
+		case TOTPNotEnrolled, TOTPAlreadyEnabled, common.TOTPCodeIncorrect:
+			c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(RecoveryCodesResponse{RecoveryCodes: codes}))
+}
+
+// Disable 停用 TOTP 并作废全部恢复码
+// @Tags totp
+// @Accept json
+// @Produce json
+// @Param request body CodeRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/totp/disable [post]
+func Disable(c *gin.Context) {
+	var req CodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if !requireEnabled(c, user) || !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.UserTOTPRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.UserTOTP{}).Error; err != nil {
+			return err
+		}
+
+		if err := service.Notify(
+			tx,
+			user.ID,
+			model.NotificationCategorySecurity,
+			"TOTP 二次验证已停用",
+			"你的账户已停用 TOTP 二次验证。如非本人操作，请尽快修改支付密码并重新启用",
+			nil,
+		); err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionUserTOTPDisable,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// UpdateThreshold 设置支付时需要 TOTP 验证的金额阈值
+// @Tags totp
+// @Accept json
+// @Produce json
+// @Param request body UpdateThresholdRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/totp/threshold [put]
+func UpdateThreshold(c *gin.Context) {
+	var req UpdateThresholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	if req.PaymentThreshold != nil && (req.PaymentThreshold.IsNegative() || req.PaymentThreshold.Exponent() < -2) {
+		c.JSON(http.StatusBadRequest, util.Err(InvalidThreshold))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if !requireEnabled(c, user) || !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var totp model.UserTOTP
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("user_id = ?", user.ID).
+			First(&totp).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&totp).Update("payment_threshold", req.PaymentThreshold).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionUserTOTPUpdate,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     map[string]interface{}{"payment_threshold": totp.PaymentThreshold},
+			After:      map[string]interface{}{"payment_threshold": req.PaymentThreshold},
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OKNil())
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码全部作废
+// @Tags totp
+// @Accept json
+// @Produce json
+// @Param request body CodeRequest true "request body"
+// @Success 200 {object} util.ResponseAny
+// @Router /api/v1/user/totp/recovery-codes [post]
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req CodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.Err(err.Error()))
+		return
+	}
+
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	if !requireEnabled(c, user) || !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
+	var codes []string
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if codes, err = regenerateRecoveryCodes(tx, user.ID); err != nil {
+			return err
+		}
+
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionUserTOTPRecoveryCodes,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, util.OK(RecoveryCodesResponse{RecoveryCodes: codes}))
+}
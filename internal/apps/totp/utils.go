@@ -0,0 +1,72 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package totp
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+// regenerateRecoveryCodes 作废用户已有恢复码并生成一组新的恢复码，明文仅在此时返回一次
+func regenerateRecoveryCodes(tx *gorm.DB, userID uint64) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.UserTOTPRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, RecoveryCodeCount)
+	records := make([]model.UserTOTPRecoveryCode, 0, RecoveryCodeCount)
+	for range RecoveryCodeCount {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+		code := raw[:5] + "-" + raw[5:10]
+		codes = append(codes, code)
+		records = append(records, model.UserTOTPRecoveryCode{
+			UserID:   userID,
+			CodeHash: model.HashRecoveryCode(code),
+		})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// requireEnabled 检查用户已启用 TOTP，未启用时直接写入错误响应
+func requireEnabled(c *gin.Context, user *model.User) bool {
+	totp, err := service.GetEnabledTOTP(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+	if totp == nil {
+		c.JSON(http.StatusBadRequest, util.Err(TOTPNotEnabled))
+		return false
+	}
+	return true
+}
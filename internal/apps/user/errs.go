@@ -17,5 +17,5 @@ limitations under the License.
 package user
 
 const (
-	EncryptPayKeyFailed = "加密支付密码失败"
+	HashPayKeyFailed = "处理支付密码失败"
 )
@@ -17,18 +17,26 @@ limitations under the License.
 package user
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/audit"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
 	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 // UpdatePayKeyRequest 更新支付密钥请求
 type UpdatePayKeyRequest struct {
-	PayKey string `json:"pay_key" binding:"required,max=6"`
+	PayKey   string `json:"pay_key" binding:"required,max=6"`
+	TOTPCode string `json:"totp_code" binding:"omitempty,max=16"`
 }
 
 // UpdatePayKey 更新用户支付密钥
@@ -47,19 +55,72 @@ func UpdatePayKey(c *gin.Context) {
 
 	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
 
-	encryptedPayKey, err := util.Encrypt(user.SignKey, req.PayKey)
+	// 已启用 TOTP 时修改支付密码必须通过二次验证
+	if !service.CheckTOTP(c, user, req.TOTPCode) {
+		return
+	}
+
+	hashedPayKey, err := model.HashPayKey(req.PayKey)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, util.Err(EncryptPayKeyFailed))
+		c.JSON(http.StatusInternalServerError, util.Err(HashPayKeyFailed))
 		return
 	}
 
-	if err := db.DB(c.Request.Context()).
-		Model(&model.User{}).
-		Where("id = ?", user.ID).
-		Update("pay_key", encryptedPayKey).Error; err != nil {
+	if err := db.DB(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Model(&model.User{}).
+			Where("id = ?", user.ID).
+			Update("pay_key", hashedPayKey).Error; err != nil {
+			return err
+		}
+
+		// 仅记录是否设置过支付密码，不记录密码内容
+		return audit.Record(c, tx, &audit.Entry{
+			Actor:      user,
+			Action:     audit.ActionUserUpdatePayKey,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     map[string]interface{}{"is_pay_key": user.PayKey != ""},
+			After:      map[string]interface{}{"is_pay_key": true},
+		})
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, util.OKNil())
 }
+
+// BalanceEvent 余额推送数据
+type BalanceEvent struct {
+	AvailableBalance decimal.Decimal `json:"available_balance"`
+	CommunityBalance decimal.Decimal `json:"community_balance"`
+	TotalReceive     decimal.Decimal `json:"total_receive"`
+	TotalPayment     decimal.Decimal `json:"total_payment"`
+	TotalTransfer    decimal.Decimal `json:"total_transfer"`
+	PayScore         int64           `json:"pay_score"`
+}
+
+// StreamBalance 订阅当前用户的余额变化（Server-Sent Events）
+// @Tags user
+// @Produce text/event-stream
+// @Success 200 {object} BalanceEvent
+// @Router /api/v1/user/balance/stream [get]
+func StreamBalance(c *gin.Context) {
+	user, _ := util.GetFromContext[*model.User](c, oauth.UserObjKey)
+
+	stream.Serve(c, fmt.Sprintf(stream.UserChannelFormat, user.ID), func(ctx context.Context) (string, interface{}, bool, error) {
+		var current model.User
+		if err := current.GetByID(db.DB(ctx), user.ID); err != nil {
+			return "", nil, true, err
+		}
+		return stream.EventBalance, BalanceEvent{
+			AvailableBalance: current.AvailableBalance,
+			CommunityBalance: current.CommunityBalance,
+			TotalReceive:     current.TotalReceive,
+			TotalPayment:     current.TotalPayment,
+			TotalTransfer:    current.TotalTransfer,
+			PayScore:         current.PayScore,
+		}, false, nil
+	})
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/service"
+	"github.com/linux-do/credit/internal/stream"
 	"github.com/linux-do/credit/internal/task"
 	"github.com/linux-do/credit/internal/task/scheduler"
 	"github.com/shopspring/decimal"
@@ -152,7 +154,8 @@ func batchUpdateUserScores(ctx context.Context, userScores []model.LeaderboardUs
 
 	now := time.Now()
 
-	return db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+	var changedUserIDs []uint64
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
 		for _, user := range users {
 			newScore, exists := scoreMap[user.ID]
 			if !exists {
@@ -228,15 +231,35 @@ func batchUpdateUserScores(ctx context.Context, userScores []model.LeaderboardUs
 			}).Error; err != nil {
 				return fmt.Errorf("更新用户[%s]积分失败: %w", user.Username, err)
 			}
+			changedUserIDs = append(changedUserIDs, user.ID)
 
 			remark := fmt.Sprintf("社区积分从 %s 更新到 %s，变化 %s",
 				oldCommunityBalance.String(), newCommunityBalance.String(), diff.String())
 			if err = createOrder(diff, remark); err != nil {
 				return err
 			}
+
+			// 积分下降会直接扣减可用余额，需告知用户
+			if diff.IsNegative() {
+				if err = service.Notify(
+					tx,
+					user.ID,
+					model.NotificationCategoryCommunityScore,
+					"社区积分同步扣减余额",
+					fmt.Sprintf("社区积分从 %s 降至 %s，可用余额相应扣减 %s", oldCommunityBalance.String(), newCommunityBalance.String(), diff.Abs().String()),
+					nil,
+				); err != nil {
+					return fmt.Errorf("通知用户[%s]积分扣减失败: %w", user.Username, err)
+				}
+			}
 		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	stream.PublishBalance(ctx, changedUserIDs...)
+	return nil
 }
 
 // HandleUpdateSingleUserGamificationScore 处理用户积分更新任务
@@ -0,0 +1,258 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/model"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// 审计动作
+const (
+	ActionSystemConfigCreate    = "system_config.create"
+	ActionSystemConfigUpdate    = "system_config.update"
+	ActionSystemConfigDelete    = "system_config.delete"
+	ActionUserPayConfigCreate   = "user_pay_config.create"
+	ActionUserPayConfigUpdate   = "user_pay_config.update"
+	ActionUserPayConfigDelete   = "user_pay_config.delete"
+	ActionUserUpdatePayKey      = "user.update_pay_key"
+	ActionUserBan               = "user.ban"
+	ActionUserUnban             = "user.unban"
+	ActionUserAdjustBalance     = "user.adjust_balance"
+	ActionUserRevokeSessions    = "user.revoke_sessions"
+	ActionUserUnlockPayKey      = "user.unlock_pay_key"
+	ActionUserTOTPEnable        = "user.totp_enable"
+	ActionUserTOTPDisable       = "user.totp_disable"
+	ActionUserTOTPUpdate        = "user.totp_update"
+	ActionUserTOTPRecoveryCodes = "user.totp_recovery_codes"
+	ActionAccessTokenCreate     = "access_token.create"
+	ActionAccessTokenRevoke     = "access_token.revoke"
+	ActionOAuthAuthorize        = "oauth.authorize"
+	ActionOAuthRevoke           = "oauth.revoke"
+	ActionAPIKeyCreate          = "api_key.create"
+	ActionAPIKeyUpdate          = "api_key.update"
+	ActionAPIKeySecretRotate    = "api_key.secret_rotate"
+	ActionAPIKeySecretRevoke    = "api_key.secret_revoke"
+	ActionAPIKeyDelete          = "api_key.delete"
+	ActionRiskRuleCreate        = "risk_rule.create"
+	ActionRiskRuleUpdate        = "risk_rule.update"
+	ActionRiskRuleDelete        = "risk_rule.delete"
+	ActionRoleGrant             = "role.grant"
+	ActionRoleRevoke            = "role.revoke"
+	ActionChangeRequestSubmit   = "change_request.submit"
+	ActionChangeRequestApprove  = "change_request.approve"
+	ActionChangeRequestReject   = "change_request.reject"
+	ActionDisputeRule           = "dispute.rule"
+	ActionDisputeOffer          = "dispute.offer"
+	ActionWashTradeReview       = "wash_trade.review"
+)
+
+// 审计对象类型
+const (
+	TargetSystemConfig       = "system_config"
+	TargetUserPayConfig      = "user_pay_config"
+	TargetUser               = "user"
+	TargetAPIKey             = "api_key"
+	TargetAccessToken        = "access_token"
+	TargetOAuthAuthorization = "oauth_authorization"
+	TargetRiskRule           = "risk_rule"
+	TargetChangeRequest      = "change_request"
+	TargetDispute            = "dispute"
+	TargetWashTradeFlag      = "wash_trade_flag"
+)
+
+// genesisHash 哈希链起点
+const genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// maxUserAgentLength 与 AuditLog.UserAgent 字段长度一致
+const maxUserAgentLength = 255
+
+// redactedKeys 写入审计日志前需要脱敏的字段
+var redactedKeys = map[string]struct{}{
+	"pay_key":       {},
+	"sign_key":      {},
+	"client_secret": {},
+}
+
+// Entry 一次审计记录，Before/After 为变更前后的对象（创建时 Before 为 nil，删除时 After 为 nil）
+// Actor 为操作者，系统任务触发时为 nil
+type Entry struct {
+	Actor      *model.User
+	Action     string
+	TargetType string
+	TargetID   interface{}
+	Before     interface{}
+	After      interface{}
+}
+
+// Record 在业务事务内追加审计日志，IP、User-Agent 与 Trace ID 取自请求上下文
+// 哈希链按审计对象（TargetType + TargetID）划分，只锁定同一对象的写入，不同对象的审计互不阻塞
+func Record(c *gin.Context, tx *gorm.DB, entry *Entry) error {
+	before, err := toMap(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := toMap(entry.After)
+	if err != nil {
+		return err
+	}
+
+	log := model.AuditLog{
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   formatID(entry.TargetID),
+		Before:     marshal(before),
+		After:      marshal(after),
+		Diff:       marshal(diff(before, after)),
+		IP:         c.ClientIP(),
+		UserAgent:  truncate(c.Request.UserAgent(), maxUserAgentLength),
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if entry.Actor != nil {
+		log.ActorUserID = entry.Actor.ID
+		log.ActorUsername = entry.Actor.Username
+	}
+	if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+		log.TraceID = spanContext.TraceID().String()
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?), hashtext(?))", log.TargetType, log.TargetID).Error; err != nil {
+		return err
+	}
+
+	var last model.AuditLog
+	if err := tx.Select("seq", "hash").
+		Where("target_type = ? AND target_id = ?", log.TargetType, log.TargetID).
+		Order("seq DESC").
+		First(&last).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		last.Hash = genesisHash
+	}
+
+	log.Seq = last.Seq + 1
+	log.PrevHash = last.Hash
+	log.Hash = ComputeHash(&log)
+
+	return tx.Create(&log).Error
+}
+
+// ComputeHash 计算审计日志的哈希值
+func ComputeHash(log *model.AuditLog) string {
+	fields := []string{
+		log.PrevHash,
+		strconv.FormatInt(log.Seq, 10),
+		strconv.FormatUint(log.ActorUserID, 10),
+		log.ActorUsername,
+		log.Action,
+		log.TargetType,
+		log.TargetID,
+		log.Before,
+		log.After,
+		log.Diff,
+		log.IP,
+		log.UserAgent,
+		log.TraceID,
+		log.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// toMap 将对象转换为 JSON 对象并脱敏
+func toMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	for key := range result {
+		if _, ok := redactedKeys[key]; ok {
+			result[key] = "***"
+		}
+	}
+	return result, nil
+}
+
+// diff 计算变更前后发生变化的字段
+func diff(before, after map[string]interface{}) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for key, afterValue := range after {
+		beforeValue, ok := before[key]
+		if !ok || !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[key] = map[string]interface{}{"before": beforeValue, "after": afterValue}
+		}
+	}
+	for key, beforeValue := range before {
+		if _, ok := after[key]; !ok {
+			changes[key] = map[string]interface{}{"before": beforeValue, "after": nil}
+		}
+	}
+	return changes
+}
+
+func marshal(v map[string]interface{}) string {
+	if v == nil {
+		return ""
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+func formatID(id interface{}) string {
+	switch v := id.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	default:
+		raw, _ := json.Marshal(v)
+		return strings.Trim(string(raw), `"`)
+	}
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
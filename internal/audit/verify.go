@@ -0,0 +1,79 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+)
+
+// verifyBatchSize 校验哈希链时每批读取的记录数
+const verifyBatchSize = 1000
+
+// VerifyResult 哈希链校验结果，BrokenTargetType/BrokenTargetID/BrokenSeq 定位第一条校验失败的记录
+type VerifyResult struct {
+	Total            int64   `json:"total"`
+	Valid            bool    `json:"valid"`
+	BrokenTargetType *string `json:"broken_target_type"`
+	BrokenTargetID   *string `json:"broken_target_id"`
+	BrokenSeq        *int64  `json:"broken_seq"`
+}
+
+// Verify 逐个审计对象从头校验哈希链的完整性
+func Verify(ctx context.Context) (*VerifyResult, error) {
+	result := &VerifyResult{Valid: true}
+	var last model.AuditLog
+	prevHash := genesisHash
+
+	for {
+		query := db.DB(ctx).Order("target_type ASC, target_id ASC, seq ASC").Limit(verifyBatchSize)
+		if last.ID != 0 {
+			query = query.Where("(target_type, target_id, seq) > (?, ?, ?)", last.TargetType, last.TargetID, last.Seq)
+		}
+
+		var logs []model.AuditLog
+		if err := query.Find(&logs).Error; err != nil {
+			return nil, err
+		}
+		if len(logs) == 0 {
+			return result, nil
+		}
+
+		for i := range logs {
+			log := &logs[i]
+			result.Total++
+
+			expectedSeq := last.Seq + 1
+			if last.ID == 0 || log.TargetType != last.TargetType || log.TargetID != last.TargetID {
+				prevHash = genesisHash
+				expectedSeq = 1
+			}
+			if log.Seq != expectedSeq || log.PrevHash != prevHash || ComputeHash(log) != log.Hash {
+				targetType, targetID, seq := log.TargetType, log.TargetID, log.Seq
+				result.Valid = false
+				result.BrokenTargetType = &targetType
+				result.BrokenTargetID = &targetID
+				result.BrokenSeq = &seq
+				return result, nil
+			}
+			prevHash = log.Hash
+			last = *log
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+
+	"github.com/spf13/cobra"
+)
+
+var payKeyStatusCmd = &cobra.Command{
+	Use:   "pay-key-status",
+	Short: "report pay key hashing migration status",
+	Run: func(cmd *cobra.Command, args []string) {
+		legacy, hashed, err := model.CountLegacyPayKeyUsers(db.DB(context.Background()))
+		if err != nil {
+			log.Fatalf("[CMD] 统计支付密码存储状态失败: %v", err)
+		}
+		log.Printf("[CMD] 支付密码存储状态: 旧版 AES 密文 %d 人, argon2id 哈希 %d 人", legacy, hashed)
+		if legacy > 0 {
+			log.Println("[CMD] 旧版密文将在用户下次支付密码验证成功后自动迁移")
+		}
+	},
+}
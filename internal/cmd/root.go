@@ -40,6 +40,8 @@ var rootCmd = &cobra.Command{
 			schedulerCmd.Run(schedulerCmd, args)
 		case "worker":
 			workerCmd.Run(workerCmd, args)
+		case "pay-key-status":
+			payKeyStatusCmd.Run(payKeyStatusCmd, args)
 		default:
 			log.Fatal("[CMD] unknown app mode\n")
 		}
@@ -21,4 +21,6 @@ const (
 	PayTypeLDPay = "ldpay"
 	// PayTypeEPay Epay 支付类型
 	PayTypeEPay = "epay"
+	// PayTypeOAuth OAuth2 授权免密扣款
+	PayTypeOAuth = "oauth"
 )
@@ -17,13 +17,24 @@ limitations under the License.
 package common
 
 const (
-	BannedAccount               = "账号已被封禁"
-	AmountMustBeGreaterThanZero = "金额必须大于0"
-	AmountDecimalPlacesExceeded = "金额小数位数不能超过2位"
-	InsufficientBalance         = "余额不足"
-	DailyLimitExceeded          = "已超过每日限额"
-	PayKeyIncorrect             = "支付密钥错误"
-	CannotPaySelf               = "不能给自己付款"
+	BannedAccount                = "账号已被封禁"
+	AmountMustBeGreaterThanZero  = "金额必须大于0"
+	AmountDecimalPlacesExceeded  = "金额小数位数不能超过2位"
+	InsufficientBalance          = "余额不足"
+	DailyLimitExceeded           = "已超过每日限额"
+	PayKeyIncorrect              = "支付密钥错误"
+	PayKeyRetryTooSoon           = "支付密码输入过于频繁，请稍后再试"
+	PayKeyLocked                 = "支付密码错误次数过多，已暂时锁定"
+	InvalidPayKeyLimitConfig     = "支付密码尝试限制配置无效"
+	TOTPRequired                 = "请输入 TOTP 验证码"
+	TOTPCodeIncorrect            = "TOTP 验证码错误"
+	TOTPTooManyAttempts          = "TOTP 验证失败次数过多，请稍后再试"
+	CannotPaySelf                = "不能给自己付款"
+	TransferAmountExceedsMax     = "超过单笔转账上限"
+	TransferDailyLimitExceeded   = "已超过每日转账限额"
+	TransferMonthlyLimitExceeded = "已超过每月转账限额"
+	TransferAccountTooNew        = "账户注册时间不足，暂不能转账"
+	RiskBlocked                  = "交易存在风险，已被系统拦截，如有疑问请联系管理员"
 )
 
 const (
@@ -27,23 +27,30 @@ type configModel struct {
 	ClickHouse clickHouseConfig `mapstructure:"clickhouse"`
 	LinuxDo    linuxDoConfig    `mapstructure:"linuxdo"`
 	Otel       otelConfig       `mapstructure:"otel"`
+	Storage    storageConfig    `mapstructure:"storage"`
+	Email      emailConfig      `mapstructure:"email"`
 }
 
 // appConfig 应用基本配置
 type appConfig struct {
-	AppName                 string `mapstructure:"app_name"`
-	Env                     string `mapstructure:"env"`
-	Addr                    string `mapstructure:"addr"`
-	NodeID                  int64  `mapstructure:"node_id"`
-	APIPrefix               string `mapstructure:"api_prefix"`
-	GracefulShutdownTimeout int    `mapstructure:"graceful_shutdown_timeout"`
-	FrontendPayURL          string `mapstructure:"frontend_pay_url"`
-	SessionCookieName       string `mapstructure:"session_cookie_name"`
-	SessionSecret           string `mapstructure:"session_secret"`
-	SessionDomain           string `mapstructure:"session_domain"`
-	SessionAge              int    `mapstructure:"session_age"`
-	SessionHttpOnly         bool   `mapstructure:"session_http_only"`
-	SessionSecure           bool   `mapstructure:"session_secure"`
+	AppName                   string `mapstructure:"app_name"`
+	Env                       string `mapstructure:"env"`
+	Addr                      string `mapstructure:"addr"`
+	NodeID                    int64  `mapstructure:"node_id"`
+	APIPrefix                 string `mapstructure:"api_prefix"`
+	GracefulShutdownTimeout   int    `mapstructure:"graceful_shutdown_timeout"`
+	FrontendPayURL            string `mapstructure:"frontend_pay_url"`
+	FrontendReceiveURL        string `mapstructure:"frontend_receive_url"`
+	FrontendPaymentRequestURL string `mapstructure:"frontend_payment_request_url"`
+	FrontendRedPacketURL      string `mapstructure:"frontend_red_packet_url"`
+	SessionCookieName         string `mapstructure:"session_cookie_name"`
+	SessionSecret             string `mapstructure:"session_secret"`
+	SessionDomain             string `mapstructure:"session_domain"`
+	SessionAge                int    `mapstructure:"session_age"`
+	SessionHttpOnly           bool   `mapstructure:"session_http_only"`
+	SessionSecure             bool   `mapstructure:"session_secure"`
+	PayKeyPepper              string `mapstructure:"pay_key_pepper"`
+	SecretEncryptionKey       string `mapstructure:"secret_encryption_key"`
 }
 
 // OAuth2Config OAuth2/OIDC认证配置
@@ -139,7 +146,12 @@ type schedulerConfig struct {
 	UpdateUserGamificationScoresTaskCron     string `mapstructure:"update_user_gamification_scores_task_cron"`
 	DisputeAutoRefundDispatchIntervalSeconds int    `mapstructure:"dispute_auto_refund_dispatch_interval_seconds"`
 	AutoRefundExpiredDisputesTaskCron        string `mapstructure:"auto_refund_expired_disputes_task_cron"`
+	RemindPendingDisputesTaskCron            string `mapstructure:"remind_pending_disputes_task_cron"`
 	SyncOrdersToClickHouseTaskCron           string `mapstructure:"sync_orders_to_clickhouse_task_cron"`
+	DispatchScheduledTransfersTaskCron       string `mapstructure:"dispatch_scheduled_transfers_task_cron"`
+	RefundExpiredRedPacketsTaskCron          string `mapstructure:"refund_expired_red_packets_task_cron"`
+	DetectWashTradingTaskCron                string `mapstructure:"detect_wash_trading_task_cron"`
+	ExpireChangeRequestsTaskCron             string `mapstructure:"expire_change_requests_task_cron"`
 }
 
 // workerConfig 工作配置
@@ -171,3 +183,26 @@ type linuxDoConfig struct {
 type otelConfig struct {
 	SamplingRate float64 `mapstructure:"sampling_rate"`
 }
+
+// storageConfig 文件存储配置
+type storageConfig struct {
+	Driver      string `mapstructure:"driver"`
+	LocalPath   string `mapstructure:"local_path"`
+	MaxFileSize int64  `mapstructure:"max_file_size"`
+}
+
+// emailConfig 邮件发送配置
+type emailConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	Host              string `mapstructure:"host"`
+	Port              int    `mapstructure:"port"`
+	Username          string `mapstructure:"username"`
+	Password          string `mapstructure:"password"`
+	From              string `mapstructure:"from"`
+	FromName          string `mapstructure:"from_name"`
+	TLSMode           string `mapstructure:"tls_mode"`
+	DialTimeout       int    `mapstructure:"dial_timeout"`
+	VerifyCodeTTL     int    `mapstructure:"verify_code_ttl"`
+	VerifyMaxAttempts int    `mapstructure:"verify_max_attempts"`
+	VerifyCooldown    int    `mapstructure:"verify_cooldown"`
+}
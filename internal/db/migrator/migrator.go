@@ -18,13 +18,17 @@ package migrator
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/linux-do/credit/internal/model"
 
 	"github.com/linux-do/credit/internal/config"
 	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/service"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func Migrate() {
@@ -40,32 +44,109 @@ func Migrate() {
 		&model.Order{},
 		&model.SystemConfig{},
 		&model.Dispute{},
+		&model.PaymentRequest{},
+		&model.Notification{},
+		&model.ScheduledTransfer{},
+		&model.ScheduledTransferRun{},
+		&model.MerchantPayout{},
+		&model.MerchantPayoutItem{},
+		&model.RedPacket{},
+		&model.RedPacketClaim{},
+		&model.RiskRule{},
+		&model.RiskDecision{},
+		&model.WashTradeFlag{},
+		&model.WashTradeClawbackOrder{},
+		&model.AdminUserOperation{},
+		&model.UserRole{},
+		&model.ChangeRequest{},
+		&model.DisputeMessage{},
+		&model.DisputeAttachment{},
+		&model.DisputeOffer{},
+		&model.NotificationPreference{},
+		&model.UserEmail{},
+		&model.UserTOTP{},
+		&model.UserTOTPRecoveryCode{},
+		&model.PersonalAccessToken{},
+		&model.OAuthAuthorization{},
+		&model.OAuthToken{},
+		&model.MerchantClientSecret{},
+		&model.AuditLog{},
 	); err != nil {
 		log.Fatalf("[PostgreSQL] auto migrate failed: %v\n", err)
 	}
+
+	// 审计日志只允许追加
+	for _, sql := range auditLogImmutableSQL {
+		if err := db.DB(context.Background()).Exec(sql).Error; err != nil {
+			log.Fatalf("[PostgreSQL] create audit log trigger failed: %v\n", err)
+		}
+	}
 	log.Printf("[PostgreSQL] auto migrate success\n")
 
+	// 迁移历史明文商户密钥
+	migrateMerchantClientSecrets()
+
 	// 初始化系统配置数据
 	initSystemConfigs()
 
 	// 初始化用户支付配置数据
 	initUserPayConfigs()
+
+	// 初始化风控规则数据
+	initRiskRules()
 }
 
-// initSystemConfigs 初始化系统配置数据
-func initSystemConfigs() {
-	tx := db.DB(context.Background())
+// auditLogImmutableSQL 禁止修改或删除审计日志
+var auditLogImmutableSQL = []string{
+	`CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS audit_logs_immutable ON audit_logs`,
+	`CREATE TRIGGER audit_logs_immutable BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_immutable()`,
+}
 
-	var count int64
-	if err := tx.Model(&model.SystemConfig{}).Count(&count).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to check system_config table: %v\n", err)
+// migrateMerchantClientSecrets 将 merchant_api_keys.client_secret 中的历史明文密钥
+// 转存为哈希与密文，随后删除该列；迁移与删列在同一事务中完成
+func migrateMerchantClientSecrets() {
+	tx := db.DB(context.Background())
+	if !tx.Migrator().HasColumn(&model.MerchantAPIKey{}, "client_secret") {
 		return
 	}
 
-	if count > 0 {
-		return
+	var legacyKeys []struct {
+		ID           uint64
+		UserID       uint64
+		ClientSecret string
+	}
+	if err := tx.Table("merchant_api_keys").
+		Select("id, user_id, client_secret").
+		Where("client_secret <> ''").
+		Find(&legacyKeys).Error; err != nil {
+		log.Fatalf("[PostgreSQL] failed to load legacy merchant client secrets: %v\n", err)
 	}
 
+	if err := tx.Transaction(func(tx *gorm.DB) error {
+		for _, legacy := range legacyKeys {
+			apiKey := model.MerchantAPIKey{ID: legacy.ID, UserID: legacy.UserID}
+			if _, err := service.SaveClientSecret(tx, &apiKey, legacy.ClientSecret); err != nil {
+				return fmt.Errorf("api key %d: %w", legacy.ID, err)
+			}
+		}
+		return tx.Migrator().DropColumn(&model.MerchantAPIKey{}, "client_secret")
+	}); err != nil {
+		log.Fatalf("[PostgreSQL] migrate merchant client secrets failed: %v\n", err)
+	}
+	log.Printf("[PostgreSQL] migrated %d legacy merchant client secrets\n", len(legacyKeys))
+}
+
+// initSystemConfigs 初始化系统配置数据
+func initSystemConfigs() {
+	tx := db.DB(context.Background())
+
+	// 仅补充缺失的配置项，已存在的配置保持不变
 	defaultConfigs := []model.SystemConfig{
 		{
 			Key:         model.ConfigKeyMerchantOrderExpireMinutes,
@@ -80,7 +161,22 @@ func initSystemConfigs() {
 		{
 			Key:         model.ConfigKeyDisputeTimeWindowHours,
 			Value:       "168",
-			Description: "商家争议时间窗口（小时）",
+			Description: "付款方发起争议的时间窗口（小时），自交易时间起计算",
+		},
+		{
+			Key:         model.ConfigKeyDisputeAppealWindowHours,
+			Value:       "72",
+			Description: "商家拒绝退款后付款方申请平台仲裁的时间窗口（小时）",
+		},
+		{
+			Key:         model.ConfigKeyDisputeResponseHours,
+			Value:       "168",
+			Description: "商家处理争议的时限（小时），自争议发起起计算，超时自动退款",
+		},
+		{
+			Key:         model.ConfigKeyDisputeReminderHours,
+			Value:       "24,2",
+			Description: "自动退款前提醒商家处理争议的时间点（距截止的小时数，逗号分隔）",
 		},
 		{
 			Key:         model.ConfigKeyNewUserInitialCredit,
@@ -92,12 +188,68 @@ func initSystemConfigs() {
 			Value:       "30",
 			Description: "新用户保护期天数，期内积分下降不扣分",
 		},
+		{
+			Key:         model.ConfigKeyPaymentRequestExpireHours,
+			Value:       "72",
+			Description: "收款请求过期时间（小时）",
+		},
+		{
+			Key:         model.ConfigKeyRedPacketExpireHours,
+			Value:       "24",
+			Description: "红包过期时间（小时），过期后剩余金额退回发送者",
+		},
+		{
+			Key:         model.ConfigKeyWashTradeWindowDays,
+			Value:       "7",
+			Description: "刷单检测时间窗口（天）",
+		},
+		{
+			Key:         model.ConfigKeyWashTradeMinEdgeAmount,
+			Value:       "100",
+			Description: "刷单检测中两账户间累计金额低于该值的资金流向不参与检测",
+		},
+		{
+			Key:         model.ConfigKeyWashTradeMaxCycleLength,
+			Value:       "4",
+			Description: "刷单检测的最长环路长度，小于 3 时不检测环路",
+		},
+		{
+			Key:         model.ConfigKeyWashTradeStarMinDegree,
+			Value:       "10",
+			Description: "星型模式最少专属对手方数量，为 0 时不检测星型模式",
+		},
+		{
+			Key:         model.ConfigKeyWashTradeStarConcentration,
+			Value:       "0.8",
+			Description: "星型模式中对手方资金流向集中于中心账户的最低比例",
+		},
+		{
+			Key:         model.ConfigKeyChangeRequestExpireHours,
+			Value:       "48",
+			Description: "敏感配置变更申请的审批有效期（小时），超时未审批自动失效",
+		},
+		{
+			Key:         model.ConfigKeyPayKeyMaxAttempts,
+			Value:       "5",
+			Description: "支付密码连续输错次数上限，达到后暂时锁定支付密码验证",
+		},
+		{
+			Key:         model.ConfigKeyPayKeyLockoutMinutes,
+			Value:       "30",
+			Description: "支付密码锁定时长（分钟），同时作为连续失败次数的统计窗口",
+		},
+		{
+			Key:         model.ConfigKeyPayKeyRetryDelays,
+			Value:       "0,2,5,15",
+			Description: "第 N 次输错支付密码后需等待的秒数（逗号分隔，超出部分沿用最后一项）",
+		},
 	}
 
-	if err := tx.Create(&defaultConfigs).Error; err != nil {
-		log.Printf("[PostgreSQL] failed to create default system configs: %v\n", err)
-	} else {
-		log.Printf("[PostgreSQL] initialized %d default system configs\n", len(defaultConfigs))
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaultConfigs)
+	if result.Error != nil {
+		log.Printf("[PostgreSQL] failed to create default system configs: %v\n", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Printf("[PostgreSQL] initialized %d default system configs\n", result.RowsAffected)
 	}
 }
 
@@ -128,6 +280,10 @@ func initUserPayConfigs() {
 			DailyLimit: int64Ptr(1000),
 			FeeRate:    decimal.Zero,
 			ScoreRate:  decimal.Zero,
+
+			DailyTransferLimit:     int64Ptr(1000),
+			MonthlyTransferLimit:   int64Ptr(10000),
+			MinTransferAccountDays: 7,
 		},
 		{
 			Level:      model.PayLevelBasic,
@@ -136,6 +292,9 @@ func initUserPayConfigs() {
 			DailyLimit: int64Ptr(6000),
 			FeeRate:    decimal.Zero,
 			ScoreRate:  decimal.Zero,
+
+			DailyTransferLimit:   int64Ptr(6000),
+			MonthlyTransferLimit: int64Ptr(60000),
 		},
 		{
 			Level:      model.PayLevelStandard,
@@ -144,6 +303,9 @@ func initUserPayConfigs() {
 			DailyLimit: int64Ptr(25000),
 			FeeRate:    decimal.Zero,
 			ScoreRate:  decimal.Zero,
+
+			DailyTransferLimit:   int64Ptr(25000),
+			MonthlyTransferLimit: int64Ptr(250000),
 		},
 		{
 			Level:      model.PayLevelPremium,
@@ -161,3 +323,69 @@ func initUserPayConfigs() {
 		log.Printf("[PostgreSQL] initialized %d default user pay configs\n", len(defaultConfigs))
 	}
 }
+
+// initRiskRules 初始化默认风控规则，默认规则均为人工复核，不直接拦截
+func initRiskRules() {
+	tx := db.DB(context.Background())
+
+	var count int64
+	if err := tx.Model(&model.RiskRule{}).Count(&count).Error; err != nil {
+		log.Printf("[PostgreSQL] failed to check risk_rules table: %v\n", err)
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	defaultRules := []model.RiskRule{
+		{
+			Name:     "高频交易",
+			Type:     model.RiskRuleTypeVelocity,
+			Params:   `{"window_minutes":10,"max_count":20,"max_amount":0}`,
+			Action:   model.RiskOutcomeReview,
+			Enabled:  true,
+			Priority: 10,
+		},
+		{
+			Name:     "首次交易对手大额交易",
+			Type:     model.RiskRuleTypeNewCounterparty,
+			Scenes:   "payment,transfer",
+			Params:   `{"min_amount":1000}`,
+			Action:   model.RiskOutcomeReview,
+			Enabled:  true,
+			Priority: 20,
+		},
+		{
+			Name:     "新账户大额交易",
+			Type:     model.RiskRuleTypeNewAccount,
+			Params:   `{"max_trust_level":1,"max_account_days":30,"min_amount":500}`,
+			Action:   model.RiskOutcomeReview,
+			Enabled:  true,
+			Priority: 30,
+		},
+		{
+			Name:     "金额显著高于历史",
+			Type:     model.RiskRuleTypeLargeAmount,
+			Params:   `{"lookback_days":30,"multiplier":10,"min_history":5,"min_amount":100}`,
+			Action:   model.RiskOutcomeReview,
+			Enabled:  true,
+			Priority: 40,
+		},
+		{
+			Name:     "多账户资金汇集",
+			Type:     model.RiskRuleTypeFunnel,
+			Scenes:   "transfer",
+			Params:   `{"window_hours":24,"min_distinct_payers":20}`,
+			Action:   model.RiskOutcomeReview,
+			Enabled:  true,
+			Priority: 50,
+		},
+	}
+
+	if err := tx.Create(&defaultRules).Error; err != nil {
+		log.Printf("[PostgreSQL] failed to create default risk rules: %v\n", err)
+	} else {
+		log.Printf("[PostgreSQL] initialized %d default risk rules\n", len(defaultRules))
+	}
+}
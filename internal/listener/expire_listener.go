@@ -35,6 +35,7 @@ import (
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
 	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/stream"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -153,5 +154,6 @@ func handleExpiredKey(ctx context.Context, expiredKey string) {
 		logger.ErrorF(ctx, "更新订单状态为过期失败: order_id=%d, error=%v", orderID, result.Error)
 	} else if result.RowsAffected > 0 {
 		logger.InfoF(ctx, "订单已过期: order_id=%d", orderID)
+		stream.PublishOrderStatus(ctx, orderID)
 	}
 }
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/linux-do/credit/internal/config"
+)
+
+const (
+	TLSModeNone     = "none"
+	TLSModeStartTLS = "starttls"
+	TLSModeTLS      = "tls"
+)
+
+// Message 一封待发送的纯文本邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Enabled 是否启用了邮件发送
+func Enabled() bool {
+	return config.Config.Email.Enabled
+}
+
+// IsPermanentFailure 判断发送错误是否为 SMTP 5xx 永久性失败（地址不存在、被拒收等），此类错误重试无意义
+func IsPermanentFailure(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
+
+// Send 通过配置的 SMTP 服务发送邮件
+func Send(ctx context.Context, msg *Message) error {
+	cfg := config.Config.Email
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+
+	dialer := &net.Dialer{Timeout: time.Duration(cfg.DialTimeout) * time.Second}
+	var conn net.Conn
+	var err error
+	if cfg.TLSMode == TLSModeTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务失败: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("初始化 SMTP 会话失败: %w", err)
+	}
+	defer client.Close()
+
+	if cfg.TLSMode == TLSModeStartTLS {
+		if err = client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("SMTP STARTTLS 失败: %w", err)
+		}
+	}
+	if cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP 认证失败: %w", err)
+		}
+	}
+
+	if err = client.Mail(cfg.From); err != nil {
+		return err
+	}
+	if err = client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(buildMessage(msg)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildMessage 组装邮件头与 base64 编码的 UTF-8 正文
+func buildMessage(msg *Message) []byte {
+	cfg := config.Config.Email
+	from := (&mail.Address{Name: cfg.FromName, Address: cfg.From}).String()
+
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + msg.To + "\r\n")
+	buf.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+
+	return buf.Bytes()
+}
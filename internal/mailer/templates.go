@@ -0,0 +1,81 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/template"
+)
+
+// 邮件事件类型，每个事件在 templates 目录下有 <event>.<locale>.tmpl 模板，模板需定义 subject 与 body
+const (
+	EventVerifyEmail     = "verify_email"
+	EventDisputeCreated  = "dispute_created"
+	EventDisputeReminder = "dispute_reminder"
+	EventWebhookFailed   = "webhook_failed"
+)
+
+const (
+	LocaleZH = "zh"
+	LocaleEN = "en"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// templates 每个模板文件独立解析，避免各文件中同名的 subject/body 定义互相覆盖
+var templates = loadTemplates()
+
+func loadTemplates() map[string]*template.Template {
+	entries, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]*template.Template, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		result[name] = template.Must(template.ParseFS(templateFS, "templates/"+entry.Name()))
+	}
+	return result
+}
+
+// Render 按事件与语言渲染邮件标题和正文，未知语言回退为中文
+func Render(event, locale string, data map[string]interface{}) (subject, body string, err error) {
+	if locale != LocaleEN {
+		locale = LocaleZH
+	}
+
+	tmpl, ok := templates[event+"."+locale]
+	if !ok {
+		return "", "", fmt.Errorf("邮件模板不存在: %s.%s", event, locale)
+	}
+
+	var subjectBuf, bodyBuf bytes.Buffer
+	if err = tmpl.ExecuteTemplate(&subjectBuf, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err = tmpl.ExecuteTemplate(&bodyBuf, "body", data); err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(subjectBuf.String()), strings.TrimSpace(bodyBuf.String()) + "\n", nil
+}
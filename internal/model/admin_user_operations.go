@@ -0,0 +1,56 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type AdminUserAction string
+
+const (
+	AdminUserActionBan            AdminUserAction = "ban"
+	AdminUserActionUnban          AdminUserAction = "unban"
+	AdminUserActionCredit         AdminUserAction = "credit"
+	AdminUserActionDebit          AdminUserAction = "debit"
+	AdminUserActionRevokeSessions AdminUserAction = "revoke_sessions"
+	AdminUserActionUnlockPayKey   AdminUserAction = "unlock_pay_key"
+)
+
+// AdminUserOperation 管理员对用户的操作记录
+type AdminUserOperation struct {
+	ID            uint64          `json:"id" gorm:"primaryKey"`
+	AdminUserID   uint64          `json:"admin_user_id" gorm:"not null;index"`
+	UserID        uint64          `json:"user_id" gorm:"not null;index:idx_admin_user_operation_user_created,priority:1"`
+	Action        AdminUserAction `json:"action" gorm:"type:varchar(20);not null"`
+	Amount        decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Reason        string          `json:"reason" gorm:"size:255;not null"`
+	OrderID       *uint64         `json:"order_id"`
+	AdminUsername string          `json:"admin_username" gorm:"->"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_admin_user_operation_user_created,priority:2"`
+}
+
+func (o *AdminUserOperation) BeforeCreate(*gorm.DB) error {
+	if o.ID == 0 {
+		o.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+// AuditLog 审计日志，只允许追加
+// 同一审计对象（TargetType + TargetID）的记录构成一条哈希链，Seq 为对象内的序号，
+// 每条记录的 Hash 由该对象上一条记录的 Hash 与本条内容计算得到，以发现篡改
+type AuditLog struct {
+	ID            uint64    `json:"id" gorm:"primaryKey"`
+	Seq           int64     `json:"seq" gorm:"not null;uniqueIndex:idx_audit_log_target,priority:3"`
+	ActorUserID   uint64    `json:"actor_user_id" gorm:"not null;index"`
+	ActorUsername string    `json:"actor_username" gorm:"size:64"`
+	Action        string    `json:"action" gorm:"size:64;not null;index"`
+	TargetType    string    `json:"target_type" gorm:"size:32;not null;uniqueIndex:idx_audit_log_target,priority:1"`
+	TargetID      string    `json:"target_id" gorm:"size:64;uniqueIndex:idx_audit_log_target,priority:2"`
+	Before        string    `json:"before" gorm:"type:text"`
+	After         string    `json:"after" gorm:"type:text"`
+	Diff          string    `json:"diff" gorm:"type:text"`
+	IP            string    `json:"ip" gorm:"size:64"`
+	UserAgent     string    `json:"user_agent" gorm:"size:255"`
+	TraceID       string    `json:"trace_id" gorm:"size:32;index"`
+	PrevHash      string    `json:"prev_hash" gorm:"size:64;not null"`
+	Hash          string    `json:"hash" gorm:"size:64;not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"not null;index"`
+}
+
+func (a *AuditLog) BeforeCreate(*gorm.DB) error {
+	if a.ID == 0 {
+		a.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
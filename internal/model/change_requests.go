@@ -0,0 +1,78 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+type ChangeRequestTarget string
+
+const (
+	ChangeRequestTargetUserPayConfig ChangeRequestTarget = "user_pay_config"
+	ChangeRequestTargetSystemConfig  ChangeRequestTarget = "system_config"
+)
+
+// ChangeRequestAction 变更申请的操作类型，审批通过时按类型应用
+type ChangeRequestAction string
+
+const (
+	ChangeRequestActionUpdate ChangeRequestAction = "update"
+	ChangeRequestActionCreate ChangeRequestAction = "create"
+	ChangeRequestActionDelete ChangeRequestAction = "delete"
+)
+
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestStatusPending  ChangeRequestStatus = "pending"
+	ChangeRequestStatusApproved ChangeRequestStatus = "approved"
+	ChangeRequestStatusRejected ChangeRequestStatus = "rejected"
+	ChangeRequestStatusExpired  ChangeRequestStatus = "expired"
+)
+
+// ChangeRequest 敏感配置变更申请，需由另一名管理员审批后才会生效
+// 部分唯一索引保证同一配置同时只有一条待审批申请，并发提交时由数据库兜底
+type ChangeRequest struct {
+	ID              uint64              `json:"id" gorm:"primaryKey"`
+	TargetType      ChangeRequestTarget `json:"target_type" gorm:"type:varchar(32);not null;index:idx_change_request_target,priority:1;uniqueIndex:idx_change_request_pending,priority:1,where:status = 'pending'"`
+	TargetID        string              `json:"target_id" gorm:"size:64;not null;index:idx_change_request_target,priority:2;uniqueIndex:idx_change_request_pending,priority:2,where:status = 'pending'"`
+	Action          ChangeRequestAction `json:"action" gorm:"type:varchar(20);not null;default:update"`
+	Before          string              `json:"before" gorm:"type:text"`
+	After           string              `json:"after" gorm:"type:text;not null"`
+	Reason          string              `json:"reason" gorm:"size:255"`
+	Status          ChangeRequestStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	MakerUserID     uint64              `json:"maker_user_id" gorm:"not null;index"`
+	CheckerUserID   *uint64             `json:"checker_user_id"`
+	ReviewNote      string              `json:"review_note" gorm:"size:255"`
+	ExpiresAt       time.Time           `json:"expires_at" gorm:"not null;index"`
+	ReviewedAt      *time.Time          `json:"reviewed_at"`
+	MakerUsername   string              `json:"maker_username" gorm:"->"`
+	CheckerUsername string              `json:"checker_username" gorm:"->"`
+	CreatedAt       time.Time           `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (r *ChangeRequest) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
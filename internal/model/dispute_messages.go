@@ -0,0 +1,70 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+type DisputeParty string
+
+const (
+	DisputePartyPayer    DisputeParty = "payer"
+	DisputePartyMerchant DisputeParty = "merchant"
+	DisputePartyAdmin    DisputeParty = "admin"
+)
+
+// DisputeMessage 争议沟通记录
+type DisputeMessage struct {
+	ID             uint64              `json:"id" gorm:"primaryKey"`
+	DisputeID      uint64              `json:"dispute_id" gorm:"not null;index:idx_dispute_message_created,priority:1"`
+	SenderUserID   uint64              `json:"sender_user_id" gorm:"not null"`
+	SenderRole     DisputeParty        `json:"sender_role" gorm:"type:varchar(20);not null"`
+	Content        string              `json:"content" gorm:"size:1000"`
+	SenderUsername string              `json:"sender_username" gorm:"->"`
+	Attachments    []DisputeAttachment `json:"attachments" gorm:"foreignKey:MessageID"`
+	CreatedAt      time.Time           `json:"created_at" gorm:"autoCreateTime;index:idx_dispute_message_created,priority:2"`
+}
+
+func (m *DisputeMessage) BeforeCreate(*gorm.DB) error {
+	if m.ID == 0 {
+		m.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// DisputeAttachment 争议沟通附件，文件内容保存在 BlobStore 中
+type DisputeAttachment struct {
+	ID          uint64    `json:"id" gorm:"primaryKey"`
+	DisputeID   uint64    `json:"dispute_id" gorm:"not null;index"`
+	MessageID   uint64    `json:"message_id" gorm:"not null;index"`
+	FileName    string    `json:"file_name" gorm:"size:255;not null"`
+	ContentType string    `json:"content_type" gorm:"size:100;not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (a *DisputeAttachment) BeforeCreate(*gorm.DB) error {
+	if a.ID == 0 {
+		a.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type DisputeOfferStatus string
+
+const (
+	DisputeOfferStatusPending   DisputeOfferStatus = "pending"
+	DisputeOfferStatusAccepted  DisputeOfferStatus = "accepted"
+	DisputeOfferStatusRejected  DisputeOfferStatus = "rejected"
+	DisputeOfferStatusCountered DisputeOfferStatus = "countered"
+	DisputeOfferStatusCancelled DisputeOfferStatus = "cancelled"
+)
+
+// DisputeOffer 争议部分退款协商方案，同一争议同时只有一条待回应方案
+type DisputeOffer struct {
+	ID               uint64             `json:"id" gorm:"primaryKey"`
+	DisputeID        uint64             `json:"dispute_id" gorm:"not null;index:idx_dispute_offer_status,priority:1"`
+	ProposerUserID   uint64             `json:"proposer_user_id" gorm:"not null"`
+	ProposerRole     DisputeParty       `json:"proposer_role" gorm:"type:varchar(20);not null"`
+	Amount           decimal.Decimal    `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Note             string             `json:"note" gorm:"size:255"`
+	Status           DisputeOfferStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_dispute_offer_status,priority:2"`
+	ResponderUserID  *uint64            `json:"responder_user_id"`
+	RespondedAt      *time.Time         `json:"responded_at"`
+	ProposerUsername string             `json:"proposer_username" gorm:"->"`
+	CreatedAt        time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (o *DisputeOffer) BeforeCreate(*gorm.DB) error {
+	if o.ID == 0 {
+		o.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
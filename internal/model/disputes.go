@@ -20,28 +20,48 @@ import (
 	"time"
 
 	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
 type DisputeStatus string
 
 const (
-	DisputeStatusDisputing DisputeStatus = "disputing"
-	DisputeStatusRefund    DisputeStatus = "refund"
-	DisputeStatusClosed    DisputeStatus = "closed"
+	DisputeStatusDisputing     DisputeStatus = "disputing"
+	DisputeStatusRefund        DisputeStatus = "refund"
+	DisputeStatusClosed        DisputeStatus = "closed"
+	DisputeStatusArbitrating   DisputeStatus = "arbitrating"
+	DisputeStatusPartialRefund DisputeStatus = "partial_refund"
+)
+
+type DisputeRuling string
+
+const (
+	DisputeRulingRefund        DisputeRuling = "refund"
+	DisputeRulingPartialRefund DisputeRuling = "partial_refund"
+	DisputeRulingDeny          DisputeRuling = "deny"
 )
 
 type Dispute struct {
-	ID                uint64        `json:"id" gorm:"primaryKey"`
-	OrderID           uint64        `json:"order_id" gorm:"uniqueIndex:idx_dispute_order;index:idx_dispute_order_status,priority:1;not null"`
-	InitiatorUserID   uint64        `json:"initiator_user_id" gorm:"not null;index:idx_initiator_status_created,priority:1"`
-	Reason            string        `json:"reason" gorm:"size:500;not null"`
-	Status            DisputeStatus `json:"status" gorm:"type:varchar(20);index;index:idx_dispute_order_status,priority:2;index:idx_initiator_status_created,priority:2;not null;default:'disputing'"`
-	HandlerUserID     *uint64       `json:"handler_user_id" gorm:"index"`
-	InitiatorUsername string        `json:"initiator_username" gorm:"->"`
-	HandlerUsername   string        `json:"handler_username" gorm:"->"`
-	CreatedAt         time.Time     `json:"created_at" gorm:"autoCreateTime;index:idx_initiator_status_created,priority:3"`
-	UpdatedAt         time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                uint64          `json:"id" gorm:"primaryKey"`
+	OrderID           uint64          `json:"order_id" gorm:"uniqueIndex:idx_dispute_order;index:idx_dispute_order_status,priority:1;not null"`
+	InitiatorUserID   uint64          `json:"initiator_user_id" gorm:"not null;index:idx_initiator_status_created,priority:1"`
+	Reason            string          `json:"reason" gorm:"size:500;not null"`
+	Status            DisputeStatus   `json:"status" gorm:"type:varchar(20);index;index:idx_dispute_order_status,priority:2;index:idx_initiator_status_created,priority:2;not null;default:'disputing'"`
+	HandlerUserID     *uint64         `json:"handler_user_id" gorm:"index"`
+	RefusedAt         *time.Time      `json:"refused_at"`
+	AppealReason      string          `json:"appeal_reason" gorm:"size:500"`
+	AppealedAt        *time.Time      `json:"appealed_at" gorm:"index"`
+	ArbitratorUserID  *uint64         `json:"arbitrator_user_id" gorm:"index"`
+	Ruling            DisputeRuling   `json:"ruling" gorm:"type:varchar(20)"`
+	RulingNote        string          `json:"ruling_note" gorm:"size:500"`
+	RuledAt           *time.Time      `json:"ruled_at"`
+	RefundAmount      decimal.Decimal `json:"refund_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	RemindedHours     int             `json:"-" gorm:"not null;default:0"`
+	InitiatorUsername string          `json:"initiator_username" gorm:"->"`
+	HandlerUsername   string          `json:"handler_username" gorm:"->"`
+	CreatedAt         time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_initiator_status_created,priority:3"`
+	UpdatedAt         time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 func (d *Dispute) BeforeCreate(*gorm.DB) error {
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+// MerchantClientSecret 商户应用密钥，一个应用在轮换过渡期内可同时存在多个有效密钥。
+// 明文仅在创建时返回一次；SecretHash 用于认证查找，SecretCipher 为使用服务端主密钥
+// 按条加盐派生密钥加密的密文，仅用于 MD5 签名校验与回调签名
+type MerchantClientSecret struct {
+	ID           uint64     `json:"id" gorm:"primaryKey"`
+	APIKeyID     uint64     `json:"api_key_id" gorm:"not null;index:idx_merchant_client_secrets_key_created,priority:1"`
+	SecretPrefix string     `json:"secret_prefix" gorm:"size:16;not null"`
+	SecretHash   string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	SecretCipher string     `json:"-" gorm:"size:255;not null"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime;index:idx_merchant_client_secrets_key_created,priority:2"`
+}
+
+func (s *MerchantClientSecret) BeforeCreate(*gorm.DB) error {
+	if s.ID == 0 {
+		s.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// Active 密钥未过期（ExpiresAt 为空表示长期有效）
+func (s *MerchantClientSecret) Active() bool {
+	return s.ExpiresAt == nil || s.ExpiresAt.After(time.Now())
+}
+
+// HashClientSecret 计算商户密钥哈希，密钥为高熵随机串，使用 SHA-256 即可
+func HashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type MerchantPayoutStatus string
+
+const (
+	MerchantPayoutStatusProcessing MerchantPayoutStatus = "processing"
+	MerchantPayoutStatusCompleted  MerchantPayoutStatus = "completed"
+)
+
+type MerchantPayoutItemStatus string
+
+const (
+	MerchantPayoutItemStatusPending MerchantPayoutItemStatus = "pending"
+	MerchantPayoutItemStatusSuccess MerchantPayoutItemStatus = "success"
+	MerchantPayoutItemStatusFailed  MerchantPayoutItemStatus = "failed"
+)
+
+// MerchantPayout 商户批量付款批次
+// 创建批次时一次性扣减商户余额，逐条到账失败的金额退回商户
+type MerchantPayout struct {
+	ID               uint64               `json:"id" gorm:"primaryKey"`
+	MerchantAPIKeyID uint64               `json:"merchant_api_key_id" gorm:"not null;uniqueIndex:idx_merchant_payout_idempotency,priority:1"`
+	IdempotencyKey   string               `json:"idempotency_key" gorm:"size:64;not null;uniqueIndex:idx_merchant_payout_idempotency,priority:2"`
+	MerchantUserID   uint64               `json:"merchant_user_id" gorm:"not null;index"`
+	TotalAmount      decimal.Decimal      `json:"total_amount" gorm:"type:numeric(20,2);not null"`
+	ItemCount        int                  `json:"item_count" gorm:"not null"`
+	SuccessCount     int                  `json:"success_count" gorm:"not null;default:0"`
+	FailedCount      int                  `json:"failed_count" gorm:"not null;default:0"`
+	RefundedAmount   decimal.Decimal      `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Status           MerchantPayoutStatus `json:"status" gorm:"type:varchar(20);not null;default:'processing';index"`
+	CreatedAt        time.Time            `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt        time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (m *MerchantPayout) BeforeCreate(*gorm.DB) error {
+	if m.ID == 0 {
+		m.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// MerchantPayoutItem 商户批量付款明细
+type MerchantPayoutItem struct {
+	ID                uint64                   `json:"id" gorm:"primaryKey"`
+	PayoutID          uint64                   `json:"payout_id" gorm:"not null;index:idx_merchant_payout_item_seq,priority:1"`
+	Seq               int                      `json:"seq" gorm:"not null;index:idx_merchant_payout_item_seq,priority:2"`
+	RecipientUserID   uint64                   `json:"recipient_user_id" gorm:"not null;index"`
+	RecipientUsername string                   `json:"recipient_username" gorm:"size:64;not null"`
+	Amount            decimal.Decimal          `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Memo              string                   `json:"memo" gorm:"size:100"`
+	Status            MerchantPayoutItemStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	FailureReason     string                   `json:"failure_reason" gorm:"size:255"`
+	OrderID           *uint64                  `json:"order_id" gorm:"index"`
+	CreatedAt         time.Time                `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time                `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (m *MerchantPayoutItem) BeforeCreate(*gorm.DB) error {
+	if m.ID == 0 {
+		m.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
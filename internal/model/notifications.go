@@ -0,0 +1,80 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+type NotificationCategory string
+
+const (
+	NotificationCategoryPaymentRequest    NotificationCategory = "payment_request"
+	NotificationCategoryScheduledTransfer NotificationCategory = "scheduled_transfer"
+	NotificationCategoryRedPacket         NotificationCategory = "red_packet"
+	NotificationCategoryDispute           NotificationCategory = "dispute"
+	NotificationCategoryPayment           NotificationCategory = "payment"
+	NotificationCategoryTransfer          NotificationCategory = "transfer"
+	NotificationCategoryRefund            NotificationCategory = "refund"
+	NotificationCategoryPayLevel          NotificationCategory = "pay_level"
+	NotificationCategoryCommunityScore    NotificationCategory = "community_score"
+	NotificationCategorySecurity          NotificationCategory = "security"
+)
+
+// NotificationCategories 全部通知分类，用于偏好设置展示与校验
+var NotificationCategories = []NotificationCategory{
+	NotificationCategoryPayment,
+	NotificationCategoryTransfer,
+	NotificationCategoryRefund,
+	NotificationCategoryDispute,
+	NotificationCategoryPaymentRequest,
+	NotificationCategoryScheduledTransfer,
+	NotificationCategoryRedPacket,
+	NotificationCategoryPayLevel,
+	NotificationCategoryCommunityScore,
+	NotificationCategorySecurity,
+}
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint64               `json:"id" gorm:"primaryKey"`
+	UserID    uint64               `json:"user_id" gorm:"not null;index:idx_notification_user_created,priority:1;index:idx_notification_user_read,priority:1"`
+	Category  NotificationCategory `json:"category" gorm:"type:varchar(32);not null"`
+	Title     string               `json:"title" gorm:"size:64;not null"`
+	Content   string               `json:"content" gorm:"size:500"`
+	RelatedID *uint64              `json:"related_id"`
+	ReadAt    *time.Time           `json:"read_at" gorm:"index:idx_notification_user_read,priority:2"`
+	CreatedAt time.Time            `json:"created_at" gorm:"autoCreateTime;index:idx_notification_user_created,priority:2"`
+}
+
+func (n *Notification) BeforeCreate(*gorm.DB) error {
+	if n.ID == 0 {
+		n.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// NotificationPreference 用户对某类通知的接收偏好，未设置时默认接收
+type NotificationPreference struct {
+	UserID    uint64               `json:"user_id" gorm:"primaryKey"`
+	Category  NotificationCategory `json:"category" gorm:"primaryKey;type:varchar(32)"`
+	Enabled   bool                 `json:"enabled" gorm:"not null;default:true"`
+	UpdatedAt time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// OAuthScope 商户通过 OAuth2 授权获得的权限范围
+type OAuthScope string
+
+const (
+	OAuthScopeProfile       OAuthScope = "profile"        // 读取用户基本信息
+	OAuthScopeBalance       OAuthScope = "balance"        // 读取用户可用余额
+	OAuthScopeChargeLimited OAuthScope = "charge:limited" // 在授权额度内免密扣款
+)
+
+// OAuthScopes 全部 OAuth2 权限范围，用于授权请求校验
+var OAuthScopes = []OAuthScope{
+	OAuthScopeProfile,
+	OAuthScopeBalance,
+	OAuthScopeChargeLimited,
+}
+
+// OAuthAuthorization 用户对商户应用的授权记录，同一用户与应用仅保留一条，重新授权时覆盖
+type OAuthAuthorization struct {
+	ID               uint64          `json:"id" gorm:"primaryKey"`
+	UserID           uint64          `json:"user_id" gorm:"not null;uniqueIndex:idx_oauth_authorization_user_client,priority:1"`
+	MerchantAPIKeyID uint64          `json:"merchant_api_key_id" gorm:"not null;uniqueIndex:idx_oauth_authorization_user_client,priority:2;index"`
+	Scopes           string          `json:"scopes" gorm:"size:255;not null"` // 空格分隔
+	SpendCap         decimal.Decimal `json:"spend_cap" gorm:"type:numeric(20,2);not null;default:0"`
+	SpentAmount      decimal.Decimal `json:"spent_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	RevokedAt        *time.Time      `json:"revoked_at"`
+	AppName          string          `json:"app_name" gorm:"->"`
+	CreatedAt        time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (a *OAuthAuthorization) BeforeCreate(*gorm.DB) error {
+	if a.ID == 0 {
+		a.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// HasScope 授权是否包含指定权限范围
+func (a *OAuthAuthorization) HasScope(scope OAuthScope) bool {
+	return slices.Contains(strings.Fields(a.Scopes), string(scope))
+}
+
+// RemainingSpend 授权剩余可扣款额度
+func (a *OAuthAuthorization) RemainingSpend() decimal.Decimal {
+	return decimal.Max(a.SpendCap.Sub(a.SpentAmount), decimal.Zero)
+}
+
+// OAuthToken 商户应用的访问令牌与刷新令牌，仅存储哈希；刷新时旧令牌立即作废
+type OAuthToken struct {
+	ID               uint64     `json:"id" gorm:"primaryKey"`
+	AuthorizationID  uint64     `json:"authorization_id" gorm:"not null;index"`
+	AccessTokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	RefreshTokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	AccessExpiresAt  time.Time  `json:"access_expires_at" gorm:"not null"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at" gorm:"not null"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (t *OAuthToken) BeforeCreate(*gorm.DB) error {
+	if t.ID == 0 {
+		t.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
@@ -32,23 +32,25 @@ import (
 type OrderType string
 
 const (
-	OrderTypeReceive   OrderType = "receive"
-	OrderTypePayment   OrderType = "payment"
-	OrderTypeTransfer  OrderType = "transfer"
-	OrderTypeCommunity OrderType = "community"
-	OrderTypeOnline    OrderType = "online"
+	OrderTypeReceive    OrderType = "receive"
+	OrderTypePayment    OrderType = "payment"
+	OrderTypeTransfer   OrderType = "transfer"
+	OrderTypeCommunity  OrderType = "community"
+	OrderTypeOnline     OrderType = "online"
+	OrderTypeAdjustment OrderType = "adjustment"
 )
 
 type OrderStatus string
 
 const (
-	OrderStatusSuccess   OrderStatus = "success"
-	OrderStatusFailed    OrderStatus = "failed"
-	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusExpired   OrderStatus = "expired"
-	OrderStatusDisputing OrderStatus = "disputing"
-	OrderStatusRefund    OrderStatus = "refund"
-	OrderStatusRefused   OrderStatus = "refused"
+	OrderStatusSuccess       OrderStatus = "success"
+	OrderStatusFailed        OrderStatus = "failed"
+	OrderStatusPending       OrderStatus = "pending"
+	OrderStatusExpired       OrderStatus = "expired"
+	OrderStatusDisputing     OrderStatus = "disputing"
+	OrderStatusRefund        OrderStatus = "refund"
+	OrderStatusRefused       OrderStatus = "refused"
+	OrderStatusPartialRefund OrderStatus = "partial_refund"
 )
 
 type Order struct {
@@ -62,6 +64,8 @@ type Order struct {
 	PayerUsername   string          `json:"payer_username" gorm:"->"`
 	PayeeUsername   string          `json:"payee_username" gorm:"->"`
 	Amount          decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null;index"`
+	Fee             decimal.Decimal `json:"fee" gorm:"type:numeric(20,2);not null;default:0"`
+	RefundedAmount  decimal.Decimal `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
 	Status          OrderStatus     `json:"status" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:2;index:idx_orders_payer_status_type_created,priority:2;index:idx_orders_client_status_created,priority:2;index:idx_orders_payer_status_type_trade,priority:2"`
 	Type            OrderType       `json:"type" gorm:"type:varchar(20);not null;index:idx_orders_payee_status_type_created,priority:3;index:idx_orders_payer_status_type_created,priority:3;index:idx_orders_payer_status_type_trade,priority:3"`
 	Remark          string          `json:"remark" gorm:"size:255"`
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestStatusPending  PaymentRequestStatus = "pending"
+	PaymentRequestStatusPaid     PaymentRequestStatus = "paid"
+	PaymentRequestStatusDeclined PaymentRequestStatus = "declined"
+	PaymentRequestStatusCanceled PaymentRequestStatus = "canceled"
+	PaymentRequestStatusExpired  PaymentRequestStatus = "expired"
+)
+
+// PaymentRequest 用户之间的收款请求
+// PayerUserID 为空时表示任何持有链接的用户均可付款
+type PaymentRequest struct {
+	ID                uint64               `json:"id" gorm:"primaryKey"`
+	Token             string               `json:"token" gorm:"size:64;uniqueIndex;not null"`
+	RequesterUserID   uint64               `json:"requester_user_id" gorm:"not null;index:idx_payment_request_requester_created,priority:1"`
+	PayerUserID       *uint64              `json:"payer_user_id" gorm:"index:idx_payment_request_payer_created,priority:1"`
+	Amount            decimal.Decimal      `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Memo              string               `json:"memo" gorm:"size:100"`
+	Status            PaymentRequestStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	OrderID           *uint64              `json:"order_id" gorm:"index"`
+	DeclineReason     string               `json:"decline_reason" gorm:"size:100"`
+	ExpiresAt         time.Time            `json:"expires_at" gorm:"not null;index"`
+	HandledAt         *time.Time           `json:"handled_at"`
+	RequesterUsername string               `json:"requester_username" gorm:"->"`
+	PayerUsername     string               `json:"payer_username" gorm:"->"`
+	CreatedAt         time.Time            `json:"created_at" gorm:"autoCreateTime;index:idx_payment_request_requester_created,priority:2;index:idx_payment_request_payer_created,priority:2"`
+	UpdatedAt         time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GetByToken 通过 Token 查询收款请求
+func (p *PaymentRequest) GetByToken(tx *gorm.DB, token string) error {
+	return tx.Where("token = ?", token).First(p).Error
+}
+
+func (p *PaymentRequest) BeforeCreate(*gorm.DB) error {
+	if p.ID == 0 {
+		p.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+// TokenScope 访问令牌权限范围
+type TokenScope string
+
+const (
+	TokenScopeReadProfile       TokenScope = "read:profile"
+	TokenScopeReadOrders        TokenScope = "read:orders"
+	TokenScopeReadDashboard     TokenScope = "read:dashboard"
+	TokenScopeReadNotifications TokenScope = "read:notifications"
+	TokenScopeWriteTransfer     TokenScope = "write:transfer"
+	TokenScopeWritePayment      TokenScope = "write:payment"
+)
+
+// TokenScopes 全部访问令牌权限范围，用于创建时校验
+var TokenScopes = []TokenScope{
+	TokenScopeReadProfile,
+	TokenScopeReadOrders,
+	TokenScopeReadDashboard,
+	TokenScopeReadNotifications,
+	TokenScopeWriteTransfer,
+	TokenScopeWritePayment,
+}
+
+// PersonalAccessToken 个人访问令牌，用于用户脚本化调用自身账户接口，仅存储令牌哈希
+type PersonalAccessToken struct {
+	ID          uint64     `json:"id" gorm:"primaryKey"`
+	UserID      uint64     `json:"user_id" gorm:"not null;index"`
+	Name        string     `json:"name" gorm:"size:64;not null"`
+	TokenPrefix string     `json:"token_prefix" gorm:"size:16;not null"`
+	TokenHash   string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Scopes      string     `json:"scopes" gorm:"size:255;not null"` // 空格分隔
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip" gorm:"size:64"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (t *PersonalAccessToken) BeforeCreate(*gorm.DB) error {
+	if t.ID == 0 {
+		t.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// Active 令牌未撤销且未过期
+func (t *PersonalAccessToken) Active() bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || t.ExpiresAt.After(time.Now()))
+}
+
+// HasScope 令牌是否拥有指定权限范围
+func (t *PersonalAccessToken) HasScope(scope TokenScope) bool {
+	return slices.Contains(strings.Fields(t.Scopes), string(scope))
+}
+
+// HashAccessToken 计算访问令牌哈希，令牌为高熵随机串，使用 SHA-256 即可
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type RedPacketSplitType string
+
+const (
+	RedPacketSplitTypeEqual  RedPacketSplitType = "equal"
+	RedPacketSplitTypeRandom RedPacketSplitType = "random"
+)
+
+type RedPacketStatus string
+
+const (
+	RedPacketStatusActive   RedPacketStatus = "active"
+	RedPacketStatusFinished RedPacketStatus = "finished"
+	RedPacketStatusExpired  RedPacketStatus = "expired"
+)
+
+// RedPacket 红包
+// 创建时从发送者余额中扣除总金额，领取时逐份入账，过期后剩余金额退回发送者
+type RedPacket struct {
+	ID              uint64             `json:"id" gorm:"primaryKey"`
+	Token           string             `json:"token" gorm:"size:64;uniqueIndex;not null"`
+	SenderUserID    uint64             `json:"sender_user_id" gorm:"not null;index:idx_red_packet_sender_created,priority:1"`
+	TotalAmount     decimal.Decimal    `json:"total_amount" gorm:"type:numeric(20,2);not null"`
+	TotalShares     int                `json:"total_shares" gorm:"not null"`
+	SplitType       RedPacketSplitType `json:"split_type" gorm:"type:varchar(20);not null"`
+	RemainingAmount decimal.Decimal    `json:"remaining_amount" gorm:"type:numeric(20,2);not null"`
+	RemainingShares int                `json:"remaining_shares" gorm:"not null"`
+	RefundedAmount  decimal.Decimal    `json:"refunded_amount" gorm:"type:numeric(20,2);not null;default:0"`
+	Greeting        string             `json:"greeting" gorm:"size:100"`
+	Status          RedPacketStatus    `json:"status" gorm:"type:varchar(20);not null;default:'active';index:idx_red_packet_status_expires,priority:1"`
+	ExpiresAt       time.Time          `json:"expires_at" gorm:"not null;index:idx_red_packet_status_expires,priority:2"`
+	SenderUsername  string             `json:"sender_username" gorm:"->"`
+	CreatedAt       time.Time          `json:"created_at" gorm:"autoCreateTime;index:idx_red_packet_sender_created,priority:2"`
+	UpdatedAt       time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// GetByToken 通过 Token 查询红包
+func (r *RedPacket) GetByToken(tx *gorm.DB, token string) error {
+	return tx.Where("token = ?", token).First(r).Error
+}
+
+func (r *RedPacket) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// RedPacketClaim 红包领取记录，每个用户对同一红包只能领取一次
+type RedPacketClaim struct {
+	ID          uint64          `json:"id" gorm:"primaryKey"`
+	RedPacketID uint64          `json:"red_packet_id" gorm:"not null;uniqueIndex:idx_red_packet_claim_user,priority:1;index:idx_red_packet_claim_created,priority:1"`
+	UserID      uint64          `json:"user_id" gorm:"not null;uniqueIndex:idx_red_packet_claim_user,priority:2;index"`
+	Amount      decimal.Decimal `json:"amount" gorm:"type:numeric(20,2);not null"`
+	OrderID     uint64          `json:"order_id" gorm:"not null;index"`
+	Username    string          `json:"username" gorm:"->"`
+	AvatarUrl   string          `json:"avatar_url" gorm:"->"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime;index:idx_red_packet_claim_created,priority:2"`
+}
+
+func (r *RedPacketClaim) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type RiskRuleType string
+
+const (
+	RiskRuleTypeVelocity        RiskRuleType = "velocity"
+	RiskRuleTypeNewCounterparty RiskRuleType = "new_counterparty"
+	RiskRuleTypeNewAccount      RiskRuleType = "new_account"
+	RiskRuleTypeLargeAmount     RiskRuleType = "large_amount"
+	RiskRuleTypeFunnel          RiskRuleType = "funnel"
+)
+
+type RiskOutcome string
+
+const (
+	RiskOutcomeAllow  RiskOutcome = "allow"
+	RiskOutcomeReview RiskOutcome = "review"
+	RiskOutcomeBlock  RiskOutcome = "block"
+)
+
+type RiskReviewStatus string
+
+const (
+	RiskReviewStatusNone     RiskReviewStatus = ""
+	RiskReviewStatusPending  RiskReviewStatus = "pending"
+	RiskReviewStatusReviewed RiskReviewStatus = "reviewed"
+)
+
+// RiskRule 风控规则
+// Scenes 为逗号分隔的适用场景，为空表示适用全部场景；Params 为规则参数 JSON
+type RiskRule struct {
+	ID        uint64       `json:"id" gorm:"primaryKey"`
+	Name      string       `json:"name" gorm:"size:64;not null"`
+	Type      RiskRuleType `json:"type" gorm:"type:varchar(32);not null"`
+	Scenes    string       `json:"scenes" gorm:"size:128;not null;default:''"`
+	Params    string       `json:"params" gorm:"type:text;not null"`
+	Action    RiskOutcome  `json:"action" gorm:"type:varchar(20);not null"`
+	Enabled   bool         `json:"enabled" gorm:"not null;index"`
+	Priority  int          `json:"priority" gorm:"not null;default:0"`
+	CreatedAt time.Time    `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time    `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (r *RiskRule) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// RiskDecision 风控决策记录，每次资金变动前的评估结果均会记录
+type RiskDecision struct {
+	ID                 uint64           `json:"id" gorm:"primaryKey"`
+	Scene              string           `json:"scene" gorm:"type:varchar(20);not null;index"`
+	UserID             uint64           `json:"user_id" gorm:"not null;index:idx_risk_decision_user_created,priority:1"`
+	CounterpartyUserID uint64           `json:"counterparty_user_id" gorm:"index"`
+	Amount             decimal.Decimal  `json:"amount" gorm:"type:numeric(20,2);not null"`
+	ReferenceID        *uint64          `json:"reference_id" gorm:"index"`
+	Outcome            RiskOutcome      `json:"outcome" gorm:"type:varchar(20);not null;index:idx_risk_decision_outcome_created,priority:1"`
+	HitRules           string           `json:"hit_rules" gorm:"type:text"`
+	ReviewStatus       RiskReviewStatus `json:"review_status" gorm:"type:varchar(20);not null;default:'';index"`
+	ReviewerUserID     *uint64          `json:"reviewer_user_id"`
+	ReviewNote         string           `json:"review_note" gorm:"size:255"`
+	ReviewedAt         *time.Time       `json:"reviewed_at"`
+	Username           string           `json:"username" gorm:"->"`
+	CreatedAt          time.Time        `json:"created_at" gorm:"autoCreateTime;index:idx_risk_decision_user_created,priority:2;index:idx_risk_decision_outcome_created,priority:2"`
+}
+
+func (r *RiskDecision) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+type WashTradePattern string
+
+const (
+	WashTradePatternCycle      WashTradePattern = "cycle"
+	WashTradePatternReciprocal WashTradePattern = "reciprocal"
+	WashTradePatternStar       WashTradePattern = "star"
+)
+
+type WashTradeFlagStatus string
+
+const (
+	WashTradeFlagStatusPending   WashTradeFlagStatus = "pending"
+	WashTradeFlagStatusConfirmed WashTradeFlagStatus = "confirmed"
+	WashTradeFlagStatusDismissed WashTradeFlagStatus = "dismissed"
+)
+
+// WashTradeFlag 刷单/循环转账检测标记，等待管理员复核
+// Members 为涉及账户 ID（升序、逗号分隔，含被标记用户本身）
+type WashTradeFlag struct {
+	ID             uint64              `json:"id" gorm:"primaryKey"`
+	UserID         uint64              `json:"user_id" gorm:"not null;index:idx_wash_trade_flag_user_members,priority:1"`
+	Pattern        WashTradePattern    `json:"pattern" gorm:"type:varchar(20);not null;index"`
+	Members        string              `json:"members" gorm:"type:text;not null;index:idx_wash_trade_flag_user_members,priority:2"`
+	OrderCount     int64               `json:"order_count" gorm:"not null"`
+	Amount         decimal.Decimal     `json:"amount" gorm:"type:numeric(20,2);not null"`
+	WindowStart    time.Time           `json:"window_start" gorm:"not null"`
+	WindowEnd      time.Time           `json:"window_end" gorm:"not null"`
+	Status         WashTradeFlagStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	ClawbackScore  int64               `json:"clawback_score" gorm:"not null;default:0"`
+	ReviewerUserID *uint64             `json:"reviewer_user_id"`
+	ReviewNote     string              `json:"review_note" gorm:"size:255"`
+	ReviewedAt     *time.Time          `json:"reviewed_at"`
+	Username       string              `json:"username" gorm:"->"`
+	CreatedAt      time.Time           `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt      time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (f *WashTradeFlag) BeforeCreate(*gorm.DB) error {
+	if f.ID == 0 {
+		f.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// WashTradeClawbackOrder 已扣回积分的订单记录，同一用户的同一订单只扣回一次，
+// 避免重叠的刷单标记（互转、环路、星型或成员变化后的重复标记）重复扣分
+type WashTradeClawbackOrder struct {
+	UserID    uint64    `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	OrderID   uint64    `json:"order_id" gorm:"primaryKey;autoIncrement:false"`
+	FlagID    uint64    `json:"flag_id" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
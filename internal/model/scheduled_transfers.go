@@ -0,0 +1,91 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusPaused    ScheduledTransferStatus = "paused"
+	ScheduledTransferStatusCanceled  ScheduledTransferStatus = "canceled"
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed"
+)
+
+type ScheduledTransferRunStatus string
+
+const (
+	ScheduledTransferRunStatusSuccess ScheduledTransferRunStatus = "success"
+	ScheduledTransferRunStatusFailed  ScheduledTransferRunStatus = "failed"
+)
+
+// ScheduledTransfer 定时转账计划
+// CronExpr 为空表示一次性转账，否则按 cron 表达式周期执行
+type ScheduledTransfer struct {
+	ID                  uint64                  `json:"id" gorm:"primaryKey"`
+	PayerUserID         uint64                  `json:"payer_user_id" gorm:"not null;index:idx_scheduled_transfer_payer_created,priority:1"`
+	RecipientUserID     uint64                  `json:"recipient_user_id" gorm:"not null;index"`
+	Amount              decimal.Decimal         `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Remark              string                  `json:"remark" gorm:"size:100"`
+	CronExpr            string                  `json:"cron_expr" gorm:"size:64"`
+	Status              ScheduledTransferStatus `json:"status" gorm:"type:varchar(20);not null;default:'active';index:idx_scheduled_transfer_status_next_run,priority:1"`
+	NextRunAt           *time.Time              `json:"next_run_at" gorm:"index:idx_scheduled_transfer_status_next_run,priority:2"`
+	LastRunAt           *time.Time              `json:"last_run_at"`
+	RunCount            int64                   `json:"run_count" gorm:"not null;default:0"`
+	ConsecutiveFailures int                     `json:"consecutive_failures" gorm:"not null;default:0"`
+	RecipientUsername   string                  `json:"recipient_username" gorm:"->"`
+	CreatedAt           time.Time               `json:"created_at" gorm:"autoCreateTime;index:idx_scheduled_transfer_payer_created,priority:2"`
+	UpdatedAt           time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// IsRecurring 是否为周期转账
+func (s *ScheduledTransfer) IsRecurring() bool {
+	return s.CronExpr != ""
+}
+
+func (s *ScheduledTransfer) BeforeCreate(*gorm.DB) error {
+	if s.ID == 0 {
+		s.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// ScheduledTransferRun 定时转账执行记录
+type ScheduledTransferRun struct {
+	ID                  uint64                     `json:"id" gorm:"primaryKey"`
+	ScheduledTransferID uint64                     `json:"scheduled_transfer_id" gorm:"not null;index:idx_scheduled_transfer_run_created,priority:1"`
+	OrderID             *uint64                    `json:"order_id" gorm:"index"`
+	Amount              decimal.Decimal            `json:"amount" gorm:"type:numeric(20,2);not null"`
+	Status              ScheduledTransferRunStatus `json:"status" gorm:"type:varchar(20);not null"`
+	FailureReason       string                     `json:"failure_reason" gorm:"size:255"`
+	ScheduledAt         time.Time                  `json:"scheduled_at" gorm:"not null"`
+	CreatedAt           time.Time                  `json:"created_at" gorm:"autoCreateTime;index:idx_scheduled_transfer_run_created,priority:2"`
+}
+
+func (r *ScheduledTransferRun) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
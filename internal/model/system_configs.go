@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -33,9 +34,23 @@ import (
 const (
 	ConfigKeyMerchantOrderExpireMinutes = "merchant_order_expire_minutes" // 商家订单过期时间（分钟）
 	ConfigKeyWebsiteOrderExpireMinutes  = "website_order_expire_minutes"  // 网站订单过期时间（分钟）
-	ConfigKeyDisputeTimeWindowHours     = "dispute_time_window_hours"     // 商家争议时间窗口（小时）
+	ConfigKeyDisputeTimeWindowHours     = "dispute_time_window_hours"     // 付款方发起争议的时间窗口（小时），自交易时间起计算
+	ConfigKeyDisputeAppealWindowHours   = "dispute_appeal_window_hours"   // 商家拒绝后付款方申请平台仲裁的时间窗口（小时）
+	ConfigKeyDisputeResponseHours       = "dispute_response_hours"        // 商家处理争议的时限（小时），超时自动退款
+	ConfigKeyDisputeReminderHours       = "dispute_reminder_hours"        // 自动退款前提醒商家的时间点（小时，逗号分隔）
 	ConfigKeyNewUserInitialCredit       = "new_user_initial_credit"       // 新用户注册初始积分
 	ConfigKeyNewUserProtectionDays      = "new_user_protection_days"      // 新用户保护期天数（期内不扣分）
+	ConfigKeyPaymentRequestExpireHours  = "payment_request_expire_hours"  // 收款请求过期时间（小时）
+	ConfigKeyRedPacketExpireHours       = "red_packet_expire_hours"       // 红包过期时间（小时）
+	ConfigKeyWashTradeWindowDays        = "wash_trade_window_days"        // 刷单检测时间窗口（天）
+	ConfigKeyWashTradeMinEdgeAmount     = "wash_trade_min_edge_amount"    // 刷单检测中两账户间最低累计金额
+	ConfigKeyWashTradeMaxCycleLength    = "wash_trade_max_cycle_length"   // 刷单检测的最长环路长度
+	ConfigKeyWashTradeStarMinDegree     = "wash_trade_star_min_degree"    // 星型模式最少专属对手方数量
+	ConfigKeyWashTradeStarConcentration = "wash_trade_star_concentration" // 星型模式对手方资金集中度
+	ConfigKeyChangeRequestExpireHours   = "change_request_expire_hours"   // 敏感配置变更申请有效期（小时）
+	ConfigKeyPayKeyMaxAttempts          = "pay_key_max_attempts"          // 支付密码连续错误次数上限，达到后锁定
+	ConfigKeyPayKeyLockoutMinutes       = "pay_key_lockout_minutes"       // 支付密码锁定时长（分钟），同时作为失败次数的统计窗口
+	ConfigKeyPayKeyRetryDelays          = "pay_key_retry_delays"          // 第 N 次失败后的冷却秒数（逗号分隔，超出部分沿用最后一项）
 )
 
 const (
@@ -86,6 +101,29 @@ func GetIntByKey(ctx context.Context, key string) (int, error) {
 	return value, nil
 }
 
+// GetIntListByKey 通过 key 查询配置并按逗号拆分为 int 列表
+func GetIntListByKey(ctx context.Context, key string) ([]int, error) {
+	var sc SystemConfig
+	if err := sc.GetByKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	var values []int
+	for _, part := range strings.Split(sc.Value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("配置 %s 的值 '%s' 无法转换为整数列表: %w", key, sc.Value, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
 // GetDecimalByKey 通过 key 查询配置并转换为 decimal.Decimal 类型
 // precision 指定保留的小数位数，多余的小数会被裁剪
 func GetDecimalByKey(ctx context.Context, key string, precision int32) (decimal.Decimal, error) {
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+// UserEmail 用户绑定的通知邮箱，验证通过且未退订、未退信时才会投递邮件
+type UserEmail struct {
+	ID           uint64     `json:"id" gorm:"primaryKey"`
+	UserID       uint64     `json:"user_id" gorm:"not null;uniqueIndex"`
+	Email        string     `json:"email" gorm:"size:255;not null;index"`
+	Locale       string     `json:"locale" gorm:"type:varchar(8);not null;default:'zh'"`
+	VerifiedAt   *time.Time `json:"verified_at"`
+	OptOut       bool       `json:"opt_out" gorm:"not null;default:false"`
+	BouncedAt    *time.Time `json:"bounced_at"`
+	BounceReason string     `json:"bounce_reason" gorm:"size:255"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (e *UserEmail) BeforeCreate(*gorm.DB) error {
+	if e.ID == 0 {
+		e.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// Deliverable 邮箱是否可以接收通知邮件
+func (e *UserEmail) Deliverable() bool {
+	return e.VerifiedAt != nil && !e.OptOut && e.BouncedAt == nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"gorm.io/gorm"
+)
+
+type AdminRole string
+
+const (
+	AdminRoleSuperAdmin AdminRole = "superadmin"
+	AdminRoleFinance    AdminRole = "finance"
+	AdminRoleSupport    AdminRole = "support"
+	AdminRoleAuditor    AdminRole = "auditor"
+)
+
+// UserRole 用户被授予的管理角色
+type UserRole struct {
+	ID              uint64    `json:"id" gorm:"primaryKey"`
+	UserID          uint64    `json:"user_id" gorm:"not null;uniqueIndex:idx_user_role,priority:1"`
+	Role            AdminRole `json:"role" gorm:"type:varchar(20);not null;uniqueIndex:idx_user_role,priority:2"`
+	GrantedByUserID uint64    `json:"granted_by_user_id" gorm:"not null"`
+	Username        string    `json:"username" gorm:"->"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (r *UserRole) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// GetRoles 获取用户的管理角色，IsAdmin 用户视为超级管理员
+func (u *User) GetRoles(tx *gorm.DB) ([]AdminRole, error) {
+	var roles []AdminRole
+	if err := tx.Model(&UserRole{}).
+		Where("user_id = ?", u.ID).
+		Order("role ASC").
+		Pluck("role", &roles).Error; err != nil {
+		return nil, err
+	}
+
+	if u.IsAdmin {
+		for _, role := range roles {
+			if role == AdminRoleSuperAdmin {
+				return roles, nil
+			}
+		}
+		roles = append([]AdminRole{AdminRoleSuperAdmin}, roles...)
+	}
+	return roles, nil
+}
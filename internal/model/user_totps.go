@@ -0,0 +1,73 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/linux-do/credit/internal/db/idgen"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// UserTOTP 用户的 TOTP 二次验证配置，EnabledAt 为空表示已生成密钥但尚未完成绑定
+type UserTOTP struct {
+	UserID           uint64           `json:"user_id" gorm:"primaryKey"`
+	Secret           string           `json:"-" gorm:"size:255;not null"` // 使用服务端主密钥派生的密钥加密存储
+	EnabledAt        *time.Time       `json:"enabled_at"`
+	PaymentThreshold *decimal.Decimal `json:"payment_threshold" gorm:"type:numeric(20,2)"`
+	LastUsedStep     int64            `json:"-" gorm:"not null;default:0"`
+	CreatedAt        time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Enabled 是否已完成绑定
+func (t *UserTOTP) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// RequiredForAmount 支付金额超过用户设置的阈值时需要 TOTP 验证，未设置阈值时不要求
+func (t *UserTOTP) RequiredForAmount(amount decimal.Decimal) bool {
+	return t.Enabled() && t.PaymentThreshold != nil && amount.GreaterThan(*t.PaymentThreshold)
+}
+
+// UserTOTPRecoveryCode TOTP 恢复码，仅存储哈希，每个恢复码只能使用一次
+type UserTOTPRecoveryCode struct {
+	ID        uint64     `json:"id" gorm:"primaryKey"`
+	UserID    uint64     `json:"user_id" gorm:"not null;index:idx_totp_recovery_user_code,priority:1"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null;index:idx_totp_recovery_user_code,priority:2"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (r *UserTOTPRecoveryCode) BeforeCreate(*gorm.DB) error {
+	if r.ID == 0 {
+		r.ID = idgen.NextUint64ID()
+	}
+	return nil
+}
+
+// HashRecoveryCode 计算恢复码哈希，忽略大小写、空格与分隔符
+// 恢复码为高熵随机串，使用 SHA-256 即可抵御离线爆破
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
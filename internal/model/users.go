@@ -29,6 +29,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/config"
 	"github.com/linux-do/credit/internal/db"
 	"github.com/linux-do/credit/internal/logger"
 	"github.com/linux-do/credit/internal/task"
@@ -130,18 +131,81 @@ func GetByIDs(tx *gorm.DB, ids []uint64) ([]User, error) {
 	return users, nil
 }
 
+// HashPayKey 计算支付密码的存储值（argon2id + 随机盐 + 可选 pepper）
+func HashPayKey(payKey string) (string, error) {
+	return util.HashSecret(payKey, config.Config.App.PayKeyPepper)
+}
+
+// IsLegacyPayKey 判断支付密码是否仍为旧版 AES 可逆密文
+func IsLegacyPayKey(payKey string) bool {
+	return payKey != "" && !util.IsSecretHash(payKey)
+}
+
+// CountLegacyPayKeyUsers 统计仍使用旧版 AES 密文存储支付密码的用户数
+func CountLegacyPayKeyUsers(tx *gorm.DB) (legacy int64, hashed int64, err error) {
+	if err = tx.Model(&User{}).
+		Where("pay_key <> '' AND pay_key NOT LIKE ?", "$argon2id$%").
+		Count(&legacy).Error; err != nil {
+		return 0, 0, err
+	}
+	if err = tx.Model(&User{}).
+		Where("pay_key LIKE ?", "$argon2id$%").
+		Count(&hashed).Error; err != nil {
+		return 0, 0, err
+	}
+	return legacy, hashed, nil
+}
+
 // VerifyPayKey 验证用户支付密码
-// 使用用户的 SignKey 解密存储的加密密码，然后与输入的明文密码比较
-func (u *User) VerifyPayKey(inputPayKey string) bool {
+// 支付密码以 argon2id 哈希存储；旧版 AES 密文在验证成功后自动迁移为哈希
+func (u *User) VerifyPayKey(ctx context.Context, inputPayKey string) bool {
 	if u.PayKey == "" {
 		return false
 	}
+
+	if !IsLegacyPayKey(u.PayKey) {
+		ok, needsRehash, err := util.VerifySecret(u.PayKey, inputPayKey, config.Config.App.PayKeyPepper)
+		if err != nil || !ok {
+			return false
+		}
+		if needsRehash {
+			u.upgradePayKey(ctx, inputPayKey)
+		}
+		return true
+	}
+
 	decrypted, err := util.Decrypt(u.SignKey, u.PayKey)
 	if err != nil {
 		return false
 	}
+	if subtle.ConstantTimeCompare([]byte(decrypted), []byte(inputPayKey)) != 1 {
+		return false
+	}
+
+	u.upgradePayKey(ctx, inputPayKey)
+	return true
+}
 
-	return subtle.ConstantTimeCompare([]byte(decrypted), []byte(inputPayKey)) == 1
+// upgradePayKey 将已验证的支付密码重新哈希存储，失败不影响本次验证结果
+// 仅在存储值未被并发修改时覆盖，避免覆盖用户刚设置的新密码
+func (u *User) upgradePayKey(ctx context.Context, payKey string) {
+	hashed, err := HashPayKey(payKey)
+	if err != nil {
+		logger.ErrorF(ctx, "用户[%d]支付密码重新哈希失败: %v", u.ID, err)
+		return
+	}
+
+	result := db.DB(ctx).
+		Model(&User{}).
+		Where("id = ? AND pay_key = ?", u.ID, u.PayKey).
+		Update("pay_key", hashed)
+	if result.Error != nil {
+		logger.ErrorF(ctx, "用户[%d]支付密码迁移失败: %v", u.ID, result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		u.PayKey = hashed
+	}
 }
 
 func (u *User) GetUserGamificationScore(ctx context.Context) (*UserGamificationScoreResponse, error) {
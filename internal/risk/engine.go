@@ -0,0 +1,164 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Scene 资金变动场景
+type Scene string
+
+const (
+	ScenePayment  Scene = "payment"
+	SceneTransfer Scene = "transfer"
+	SceneRefund   Scene = "refund"
+	ScenePayout   Scene = "payout"
+)
+
+// Scenes 全部可配置的场景
+var Scenes = []Scene{ScenePayment, SceneTransfer, SceneRefund, ScenePayout}
+
+// Event 待评估的资金变动
+// User 为资金流出方，CounterpartyUserID 为资金流入方（为 0 表示无确定的对手方，如红包、批量付款）
+type Event struct {
+	Scene              Scene
+	User               *model.User
+	CounterpartyUserID uint64
+	Amount             decimal.Decimal
+	ReferenceID        *uint64
+}
+
+// Hit 命中的规则
+type Hit struct {
+	RuleID   uint64             `json:"rule_id"`
+	RuleName string             `json:"rule_name"`
+	RuleType model.RiskRuleType `json:"rule_type"`
+	Action   model.RiskOutcome  `json:"action"`
+	Reason   string             `json:"reason"`
+}
+
+// Rule 风控规则实现
+type Rule interface {
+	// ValidateParams 校验规则参数
+	ValidateParams(params json.RawMessage) error
+	// Evaluate 评估事件是否命中规则，命中时返回原因
+	Evaluate(ctx context.Context, tx *gorm.DB, event *Event, params json.RawMessage) (hit bool, reason string, err error)
+}
+
+var registry = map[model.RiskRuleType]Rule{}
+
+// Register 注册规则实现
+func Register(ruleType model.RiskRuleType, rule Rule) {
+	registry[ruleType] = rule
+}
+
+// GetRule 获取规则实现
+func GetRule(ruleType model.RiskRuleType) (Rule, bool) {
+	rule, ok := registry[ruleType]
+	return rule, ok
+}
+
+// IsValidScene 检查场景是否合法
+func IsValidScene(scene string) bool {
+	for _, s := range Scenes {
+		if string(s) == scene {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate 在资金变动提交前评估风险
+// tx 为业务事务，用于读取一致的数据；决策记录使用独立会话写入，确保被拦截时业务事务回滚后仍可审计
+// 命中 block 规则时返回 common.RiskBlocked 错误；命中 review 规则时放行并进入人工复核队列
+func Evaluate(ctx context.Context, tx *gorm.DB, event *Event) (*model.RiskDecision, error) {
+	var rules []model.RiskRule
+	if err := tx.
+		Where("enabled = ?", true).
+		Where("scenes = '' OR ? = ANY(string_to_array(scenes, ','))", string(event.Scene)).
+		Order("priority ASC, id ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	outcome := model.RiskOutcomeAllow
+	hits := make([]Hit, 0)
+	for _, rule := range rules {
+		impl, ok := registry[rule.Type]
+		if !ok {
+			logger.WarnF(ctx, "未知的风控规则类型: %s", rule.Type)
+			continue
+		}
+
+		hit, reason, err := impl.Evaluate(ctx, tx, event, json.RawMessage(rule.Params))
+		if err != nil {
+			return nil, err
+		}
+		if !hit {
+			continue
+		}
+
+		hits = append(hits, Hit{RuleID: rule.ID, RuleName: rule.Name, RuleType: rule.Type, Action: rule.Action, Reason: reason})
+		if rule.Action == model.RiskOutcomeBlock {
+			outcome = model.RiskOutcomeBlock
+		} else if outcome == model.RiskOutcomeAllow {
+			outcome = model.RiskOutcomeReview
+		}
+	}
+
+	hitRules, err := json.Marshal(hits)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := model.RiskDecision{
+		Scene:              string(event.Scene),
+		UserID:             event.User.ID,
+		CounterpartyUserID: event.CounterpartyUserID,
+		Amount:             event.Amount,
+		ReferenceID:        event.ReferenceID,
+		Outcome:            outcome,
+		HitRules:           string(hitRules),
+	}
+	if outcome == model.RiskOutcomeReview {
+		decision.ReviewStatus = model.RiskReviewStatusPending
+	}
+	if err := db.DB(ctx).Create(&decision).Error; err != nil {
+		return nil, err
+	}
+
+	if outcome == model.RiskOutcomeBlock {
+		return &decision, errors.New(common.RiskBlocked)
+	}
+	return &decision, nil
+}
+
+// JoinScenes 将场景列表转换为存储格式
+func JoinScenes(scenes []string) string {
+	return strings.Join(scenes, ",")
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// outgoingStatuses 已实际发生资金流出的订单状态
+var outgoingStatuses = []model.OrderStatus{
+	model.OrderStatusSuccess,
+	model.OrderStatusDisputing,
+	model.OrderStatusRefused,
+	model.OrderStatusRefund,
+	model.OrderStatusPartialRefund,
+}
+
+func init() {
+	Register(model.RiskRuleTypeVelocity, velocityRule{})
+	Register(model.RiskRuleTypeNewCounterparty, newCounterpartyRule{})
+	Register(model.RiskRuleTypeNewAccount, newAccountRule{})
+	Register(model.RiskRuleTypeLargeAmount, largeAmountRule{})
+	Register(model.RiskRuleTypeFunnel, funnelRule{})
+}
+
+// decodeParams 解析规则参数，拒绝未知字段
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("规则参数格式错误: %w", err)
+	}
+	return nil
+}
+
+// VelocityParams 频率规则参数：时间窗口内的笔数或金额超过阈值（阈值为 0 表示不限）
+type VelocityParams struct {
+	WindowMinutes int             `json:"window_minutes"`
+	MaxCount      int64           `json:"max_count"`
+	MaxAmount     decimal.Decimal `json:"max_amount"`
+}
+
+type velocityRule struct{}
+
+func (velocityRule) ValidateParams(params json.RawMessage) error {
+	var p VelocityParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.WindowMinutes <= 0 {
+		return errors.New("window_minutes 必须大于 0")
+	}
+	if p.MaxCount <= 0 && p.MaxAmount.LessThanOrEqual(decimal.Zero) {
+		return errors.New("max_count 与 max_amount 至少需要设置一个")
+	}
+	return nil
+}
+
+func (velocityRule) Evaluate(_ context.Context, tx *gorm.DB, event *Event, params json.RawMessage) (bool, string, error) {
+	var p VelocityParams
+	if err := decodeParams(params, &p); err != nil {
+		return false, "", err
+	}
+
+	since := time.Now().Add(-time.Duration(p.WindowMinutes) * time.Minute)
+	var stats struct {
+		Count  int64
+		Amount decimal.Decimal
+	}
+	query := tx.Model(&model.Order{}).Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount")
+	if event.Scene == SceneRefund {
+		query = query.Where("payee_user_id = ? AND status = ? AND updated_at >= ?", event.User.ID, model.OrderStatusRefund, since)
+	} else {
+		query = query.Where("payer_user_id = ? AND status IN ? AND trade_time >= ?", event.User.ID, outgoingStatuses, since)
+	}
+	if err := query.Scan(&stats).Error; err != nil {
+		return false, "", err
+	}
+
+	if p.MaxCount > 0 && stats.Count+1 > p.MaxCount {
+		return true, fmt.Sprintf("%d 分钟内交易 %d 笔，超过 %d 笔", p.WindowMinutes, stats.Count+1, p.MaxCount), nil
+	}
+	if p.MaxAmount.GreaterThan(decimal.Zero) && stats.Amount.Add(event.Amount).GreaterThan(p.MaxAmount) {
+		return true, fmt.Sprintf("%d 分钟内交易金额 %s，超过 %s", p.WindowMinutes, stats.Amount.Add(event.Amount), p.MaxAmount), nil
+	}
+	return false, "", nil
+}
+
+// NewCounterpartyParams 首次交易对手规则参数：与从未交易过的对手方发生不低于 MinAmount 的交易
+type NewCounterpartyParams struct {
+	MinAmount decimal.Decimal `json:"min_amount"`
+}
+
+type newCounterpartyRule struct{}
+
+func (newCounterpartyRule) ValidateParams(params json.RawMessage) error {
+	var p NewCounterpartyParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.MinAmount.LessThan(decimal.Zero) {
+		return errors.New("min_amount 不能小于 0")
+	}
+	return nil
+}
+
+func (newCounterpartyRule) Evaluate(_ context.Context, tx *gorm.DB, event *Event, params json.RawMessage) (bool, string, error) {
+	if event.CounterpartyUserID == 0 || event.Scene == SceneRefund {
+		return false, "", nil
+	}
+
+	var p NewCounterpartyParams
+	if err := decodeParams(params, &p); err != nil {
+		return false, "", err
+	}
+	if event.Amount.LessThan(p.MinAmount) {
+		return false, "", nil
+	}
+
+	var exists bool
+	if err := tx.Raw(
+		"SELECT EXISTS (SELECT 1 FROM orders WHERE payer_user_id = ? AND payee_user_id = ? AND status IN ?)",
+		event.User.ID, event.CounterpartyUserID, outgoingStatuses,
+	).Scan(&exists).Error; err != nil {
+		return false, "", err
+	}
+	if exists {
+		return false, "", nil
+	}
+	return true, fmt.Sprintf("首次与该对手方交易，金额 %s", event.Amount), nil
+}
+
+// NewAccountParams 新账户规则参数：信任等级不高于 MaxTrustLevel 且注册不足 MaxAccountDays 天的账户发生不低于 MinAmount 的交易
+// MaxAccountDays 为 0 表示不限注册天数
+type NewAccountParams struct {
+	MaxTrustLevel  model.TrustLevel `json:"max_trust_level"`
+	MaxAccountDays int              `json:"max_account_days"`
+	MinAmount      decimal.Decimal  `json:"min_amount"`
+}
+
+type newAccountRule struct{}
+
+func (newAccountRule) ValidateParams(params json.RawMessage) error {
+	var p NewAccountParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.MaxTrustLevel > model.TrustLevelLeader {
+		return errors.New("max_trust_level 超出范围")
+	}
+	if p.MaxAccountDays < 0 || p.MinAmount.LessThan(decimal.Zero) {
+		return errors.New("max_account_days 与 min_amount 不能小于 0")
+	}
+	return nil
+}
+
+func (newAccountRule) Evaluate(_ context.Context, _ *gorm.DB, event *Event, params json.RawMessage) (bool, string, error) {
+	var p NewAccountParams
+	if err := decodeParams(params, &p); err != nil {
+		return false, "", err
+	}
+
+	if event.User.TrustLevel > p.MaxTrustLevel || event.Amount.LessThan(p.MinAmount) {
+		return false, "", nil
+	}
+	if p.MaxAccountDays > 0 && time.Now().After(event.User.CreatedAt.AddDate(0, 0, p.MaxAccountDays)) {
+		return false, "", nil
+	}
+	return true, fmt.Sprintf("信任等级 %d 的新账户交易金额 %s", event.User.TrustLevel, event.Amount), nil
+}
+
+// LargeAmountParams 大额规则参数：金额超过历史平均交易金额的 Multiplier 倍
+// 历史交易笔数不足 MinHistory 时不做判断，金额低于 MinAmount 时忽略
+type LargeAmountParams struct {
+	LookbackDays int             `json:"lookback_days"`
+	Multiplier   decimal.Decimal `json:"multiplier"`
+	MinHistory   int64           `json:"min_history"`
+	MinAmount    decimal.Decimal `json:"min_amount"`
+}
+
+type largeAmountRule struct{}
+
+func (largeAmountRule) ValidateParams(params json.RawMessage) error {
+	var p LargeAmountParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.LookbackDays <= 0 {
+		return errors.New("lookback_days 必须大于 0")
+	}
+	if p.Multiplier.LessThanOrEqual(decimal.NewFromInt(1)) {
+		return errors.New("multiplier 必须大于 1")
+	}
+	if p.MinHistory < 1 || p.MinAmount.LessThan(decimal.Zero) {
+		return errors.New("min_history 必须大于 0 且 min_amount 不能小于 0")
+	}
+	return nil
+}
+
+func (largeAmountRule) Evaluate(_ context.Context, tx *gorm.DB, event *Event, params json.RawMessage) (bool, string, error) {
+	var p LargeAmountParams
+	if err := decodeParams(params, &p); err != nil {
+		return false, "", err
+	}
+	if event.Amount.LessThan(p.MinAmount) {
+		return false, "", nil
+	}
+
+	since := time.Now().AddDate(0, 0, -p.LookbackDays)
+	var stats struct {
+		Count  int64
+		Amount decimal.Decimal
+	}
+	query := tx.Model(&model.Order{}).Select("COUNT(*) AS count, COALESCE(AVG(amount), 0) AS amount")
+	if event.Scene == SceneRefund {
+		query = query.Where("payee_user_id = ? AND status IN ? AND trade_time >= ?", event.User.ID, outgoingStatuses, since)
+	} else {
+		query = query.Where("payer_user_id = ? AND status IN ? AND trade_time >= ?", event.User.ID, outgoingStatuses, since)
+	}
+	if err := query.Scan(&stats).Error; err != nil {
+		return false, "", err
+	}
+	if stats.Count < p.MinHistory {
+		return false, "", nil
+	}
+
+	threshold := stats.Amount.Mul(p.Multiplier)
+	if event.Amount.GreaterThan(threshold) {
+		return true, fmt.Sprintf("金额 %s 超过近 %d 天平均金额 %s 的 %s 倍", event.Amount, p.LookbackDays, stats.Amount.Round(2), p.Multiplier), nil
+	}
+	return false, "", nil
+}
+
+// FunnelParams 资金汇集规则参数：时间窗口内向同一账户付款的不同付款方数量达到 MinDistinctPayers
+type FunnelParams struct {
+	WindowHours       int   `json:"window_hours"`
+	MinDistinctPayers int64 `json:"min_distinct_payers"`
+}
+
+type funnelRule struct{}
+
+func (funnelRule) ValidateParams(params json.RawMessage) error {
+	var p FunnelParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if p.WindowHours <= 0 || p.MinDistinctPayers < 2 {
+		return errors.New("window_hours 必须大于 0 且 min_distinct_payers 不能小于 2")
+	}
+	return nil
+}
+
+func (funnelRule) Evaluate(_ context.Context, tx *gorm.DB, event *Event, params json.RawMessage) (bool, string, error) {
+	if event.CounterpartyUserID == 0 || event.Scene == SceneRefund {
+		return false, "", nil
+	}
+
+	var p FunnelParams
+	if err := decodeParams(params, &p); err != nil {
+		return false, "", err
+	}
+
+	since := time.Now().Add(-time.Duration(p.WindowHours) * time.Hour)
+	var payers int64
+	if err := tx.Model(&model.Order{}).
+		Where("payee_user_id = ? AND payer_user_id <> ? AND type = ? AND status IN ? AND trade_time >= ?",
+			event.CounterpartyUserID, event.User.ID, model.OrderTypeTransfer, outgoingStatuses, since).
+		Distinct("payer_user_id").
+		Count(&payers).Error; err != nil {
+		return false, "", err
+	}
+
+	// 计入本次付款方
+	payers++
+	if payers >= p.MinDistinctPayers {
+		return true, fmt.Sprintf("%d 小时内已有 %d 个不同账户向收款方转账", p.WindowHours, payers), nil
+	}
+	return false, "", nil
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// maxWashTradeFindings 单次检测最多产出的可疑模式数量，避免稠密图下环路枚举失控
+const maxWashTradeFindings = 1000
+
+// washTradeOrderStatuses 资金已实际流转且未退回的订单状态
+var washTradeOrderStatuses = []model.OrderStatus{model.OrderStatusSuccess, model.OrderStatusRefused}
+
+// washTradeOrderTypes 参与检测的用户间资金流转订单类型
+var washTradeOrderTypes = []model.OrderType{model.OrderTypePayment, model.OrderTypeOnline, model.OrderTypeTransfer}
+
+// Edge 时间窗口内两个账户之间的资金流向汇总
+type Edge struct {
+	PayerUserID uint64
+	PayeeUserID uint64
+	OrderCount  int64
+	Amount      decimal.Decimal
+}
+
+// WashTradeParams 刷单检测参数
+type WashTradeParams struct {
+	// MinEdgeAmount 两账户间累计金额低于该值的资金流向不参与检测
+	MinEdgeAmount decimal.Decimal
+	// MaxCycleLength 检测的最长环路长度（不小于 3）
+	MaxCycleLength int
+	// StarMinDegree 星型模式中专属对手方的最少数量
+	StarMinDegree int
+	// StarConcentration 对手方资金流向集中于中心账户的最低比例
+	StarConcentration decimal.Decimal
+}
+
+// WashTradeFinding 检测出的可疑模式
+type WashTradeFinding struct {
+	Pattern     model.WashTradePattern
+	Members     []uint64
+	FlagUserIDs []uint64
+	OrderCount  int64
+	Amount      decimal.Decimal
+}
+
+// MembersKey 返回升序、逗号分隔的成员列表
+func (f *WashTradeFinding) MembersKey() string {
+	parts := make([]string, len(f.Members))
+	for i, id := range f.Members {
+		parts[i] = strconv.FormatUint(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// LoadEdges 汇总时间窗口内账户间的资金流向，启用 ClickHouse 时从 ClickHouse 读取
+func LoadEdges(ctx context.Context, start, end time.Time) ([]Edge, error) {
+	if config.Config.ClickHouse.Enabled {
+		return loadEdgesFromClickHouse(ctx, start, end)
+	}
+
+	var edges []Edge
+	if err := db.DB(ctx).Model(&model.Order{}).
+		Select("payer_user_id, payee_user_id, COUNT(*) AS order_count, SUM(amount) AS amount").
+		Where("status IN ? AND type IN ? AND trade_time >= ? AND trade_time < ?", washTradeOrderStatuses, washTradeOrderTypes, start, end).
+		Where("payer_user_id <> 0 AND payer_user_id <> payee_user_id").
+		Group("payer_user_id, payee_user_id").
+		Scan(&edges).Error; err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+func loadEdgesFromClickHouse(ctx context.Context, start, end time.Time) ([]Edge, error) {
+	quote := func(values []string) string {
+		return "'" + strings.Join(values, "','") + "'"
+	}
+	statuses := make([]string, len(washTradeOrderStatuses))
+	for i, s := range washTradeOrderStatuses {
+		statuses[i] = string(s)
+	}
+	types := make([]string, len(washTradeOrderTypes))
+	for i, t := range washTradeOrderTypes {
+		types[i] = string(t)
+	}
+
+	rows, err := db.ChConn.Query(ctx, fmt.Sprintf(`
+		SELECT payer_user_id, payee_user_id, count() AS order_count, sum(amount) AS amount
+		FROM orders FINAL
+		WHERE status IN (%s) AND type IN (%s) AND trade_time >= ? AND trade_time < ?
+			AND payer_user_id <> 0 AND payer_user_id <> payee_user_id
+		GROUP BY payer_user_id, payee_user_id
+	`, quote(statuses), quote(types)), start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []Edge
+	for rows.Next() {
+		var (
+			edge       Edge
+			orderCount uint64
+		)
+		if err := rows.Scan(&edge.PayerUserID, &edge.PayeeUserID, &orderCount, &edge.Amount); err != nil {
+			return nil, err
+		}
+		edge.OrderCount = int64(orderCount)
+		edges = append(edges, edge)
+	}
+	return edges, rows.Err()
+}
+
+// DetectWashTrading 在资金流向图中检测互转、环路与星型模式
+func DetectWashTrading(edges []Edge, params WashTradeParams) []WashTradeFinding {
+	graph := make(map[uint64]map[uint64]*Edge)
+	inbound := make(map[uint64][]*Edge)
+	outTotal := make(map[uint64]decimal.Decimal)
+	inTotal := make(map[uint64]decimal.Decimal)
+	for i := range edges {
+		edge := &edges[i]
+		outTotal[edge.PayerUserID] = outTotal[edge.PayerUserID].Add(edge.Amount)
+		inTotal[edge.PayeeUserID] = inTotal[edge.PayeeUserID].Add(edge.Amount)
+		if edge.Amount.LessThan(params.MinEdgeAmount) {
+			continue
+		}
+		if graph[edge.PayerUserID] == nil {
+			graph[edge.PayerUserID] = make(map[uint64]*Edge)
+		}
+		graph[edge.PayerUserID][edge.PayeeUserID] = edge
+		inbound[edge.PayeeUserID] = append(inbound[edge.PayeeUserID], edge)
+	}
+
+	var findings []WashTradeFinding
+	seen := make(map[string]struct{})
+	add := func(finding WashTradeFinding) bool {
+		if len(findings) >= maxWashTradeFindings {
+			return false
+		}
+		sort.Slice(finding.Members, func(i, j int) bool { return finding.Members[i] < finding.Members[j] })
+		key := string(finding.Pattern) + ":" + finding.MembersKey()
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			findings = append(findings, finding)
+		}
+		return true
+	}
+
+	nodes := make([]uint64, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	// 互转：A→B 与 B→A 同时存在
+	for _, a := range nodes {
+		for b, forward := range graph[a] {
+			if a >= b {
+				continue
+			}
+			backward, ok := graph[b][a]
+			if !ok {
+				continue
+			}
+			if !add(WashTradeFinding{
+				Pattern:     model.WashTradePatternReciprocal,
+				Members:     []uint64{a, b},
+				FlagUserIDs: []uint64{a, b},
+				OrderCount:  forward.OrderCount + backward.OrderCount,
+				Amount:      forward.Amount.Add(backward.Amount),
+			}) {
+				return findings
+			}
+		}
+	}
+
+	// 环路：长度 3 ~ MaxCycleLength，仅从环内最小节点出发，避免重复枚举
+	var path []*Edge
+	var dfs func(start, current uint64, visited map[uint64]bool) bool
+	dfs = func(start, current uint64, visited map[uint64]bool) bool {
+		for next, edge := range graph[current] {
+			if next == start && len(path) >= 2 {
+				cycle := append(append([]*Edge{}, path...), edge)
+				finding := WashTradeFinding{Pattern: model.WashTradePatternCycle}
+				for _, e := range cycle {
+					finding.Members = append(finding.Members, e.PayerUserID)
+					finding.OrderCount += e.OrderCount
+					finding.Amount = finding.Amount.Add(e.Amount)
+				}
+				finding.FlagUserIDs = append([]uint64{}, finding.Members...)
+				if !add(finding) {
+					return false
+				}
+				continue
+			}
+			if next <= start || visited[next] || len(path)+1 >= params.MaxCycleLength {
+				continue
+			}
+			visited[next] = true
+			path = append(path, edge)
+			ok := dfs(start, next, visited)
+			path = path[:len(path)-1]
+			delete(visited, next)
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+	if params.MaxCycleLength >= 3 {
+		for _, start := range nodes {
+			if !dfs(start, start, map[uint64]bool{start: true}) {
+				return findings
+			}
+		}
+	}
+
+	// 星型：大量资金流向集中于中心账户的专属对手方（汇入或分散）
+	detectStar := func(hub uint64, spokes []*Edge, counterparty func(*Edge) uint64, totals map[uint64]decimal.Decimal) bool {
+		finding := WashTradeFinding{
+			Pattern:     model.WashTradePatternStar,
+			Members:     []uint64{hub},
+			FlagUserIDs: []uint64{hub},
+		}
+		for _, edge := range spokes {
+			total := totals[counterparty(edge)]
+			if total.IsZero() || edge.Amount.Div(total).LessThan(params.StarConcentration) {
+				continue
+			}
+			finding.Members = append(finding.Members, counterparty(edge))
+			finding.OrderCount += edge.OrderCount
+			finding.Amount = finding.Amount.Add(edge.Amount)
+		}
+		if len(finding.Members)-1 < params.StarMinDegree {
+			return true
+		}
+		return add(finding)
+	}
+	if params.StarMinDegree > 0 {
+		payer := func(e *Edge) uint64 { return e.PayerUserID }
+		payee := func(e *Edge) uint64 { return e.PayeeUserID }
+
+		hubs := make([]uint64, 0, len(inbound))
+		for hub := range inbound {
+			hubs = append(hubs, hub)
+		}
+		sort.Slice(hubs, func(i, j int) bool { return hubs[i] < hubs[j] })
+		for _, hub := range hubs {
+			if !detectStar(hub, inbound[hub], payer, outTotal) {
+				return findings
+			}
+		}
+		for _, hub := range nodes {
+			spokes := make([]*Edge, 0, len(graph[hub]))
+			for _, edge := range graph[hub] {
+				spokes = append(spokes, edge)
+			}
+			if !detectStar(hub, spokes, payee, inTotal) {
+				return findings
+			}
+		}
+	}
+
+	return findings
+}
+
+// CalculateWashTradeClawback 计算被标记用户在检测窗口内与可疑对手方交易所获得的支付积分，
+// 并返回参与计算的订单 ID；已被其他标记扣回过的订单不再计入
+// 付款方积分按订单金额取整累计；商户积分按当前支付配置的积分倍率估算
+func CalculateWashTradeClawback(tx *gorm.DB, flag *model.WashTradeFlag) (int64, []uint64, error) {
+	var counterparties []uint64
+	for _, part := range strings.Split(flag.Members, ",") {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return 0, nil, err
+		}
+		if id != flag.UserID {
+			counterparties = append(counterparties, id)
+		}
+	}
+	if len(counterparties) == 0 {
+		return 0, nil, nil
+	}
+
+	scoreOrderTypes := []model.OrderType{model.OrderTypePayment, model.OrderTypeOnline}
+
+	var orders []model.Order
+	if err := tx.Model(&model.Order{}).
+		Select("id, payer_user_id, payee_user_id, amount").
+		Where("((payer_user_id = ? AND payee_user_id IN ?) OR (payee_user_id = ? AND payer_user_id IN ?))",
+			flag.UserID, counterparties, flag.UserID, counterparties).
+		Where("status IN ? AND type IN ? AND trade_time >= ? AND trade_time < ?",
+			washTradeOrderStatuses, scoreOrderTypes, flag.WindowStart, flag.WindowEnd).
+		Where("NOT EXISTS (SELECT 1 FROM wash_trade_clawback_orders c WHERE c.user_id = ? AND c.order_id = orders.id)", flag.UserID).
+		Find(&orders).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		payerScore     int64
+		receivedAmount decimal.Decimal
+		orderIDs       = make([]uint64, 0, len(orders))
+	)
+	for _, order := range orders {
+		if order.PayerUserID == flag.UserID {
+			payerScore += order.Amount.Round(0).IntPart()
+		} else {
+			receivedAmount = receivedAmount.Add(order.Amount)
+		}
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	var merchantScore int64
+	if receivedAmount.GreaterThan(decimal.Zero) {
+		var user model.User
+		if err := user.GetByID(tx, flag.UserID); err != nil {
+			return 0, nil, err
+		}
+		var payConfig model.UserPayConfig
+		if err := payConfig.GetByPayScore(tx, user.PayScore); err != nil {
+			return 0, nil, err
+		}
+		merchantScore = receivedAmount.Mul(payConfig.ScoreRate).Round(0).IntPart()
+	}
+
+	return payerScore + merchantScore, orderIDs, nil
+}
@@ -27,12 +27,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/linux-do/credit/internal/apps/access_token"
 	"github.com/linux-do/credit/internal/apps/admin"
 	publicconfig "github.com/linux-do/credit/internal/apps/config"
 	"github.com/linux-do/credit/internal/apps/dispute"
+	"github.com/linux-do/credit/internal/apps/email"
 	"github.com/linux-do/credit/internal/apps/merchant/api_key"
 	"github.com/linux-do/credit/internal/apps/merchant/link"
+	"github.com/linux-do/credit/internal/apps/merchant/payout"
+	"github.com/linux-do/credit/internal/apps/notification"
+	"github.com/linux-do/credit/internal/apps/payment_request"
+	"github.com/linux-do/credit/internal/apps/qrcode"
+	"github.com/linux-do/credit/internal/apps/red_packet"
+	"github.com/linux-do/credit/internal/apps/scheduled_transfer"
 	"github.com/linux-do/credit/internal/listener"
+	"github.com/linux-do/credit/internal/stream"
 	"github.com/linux-do/credit/internal/util"
 
 	"github.com/linux-do/credit/internal/apps/payment"
@@ -41,14 +50,23 @@ import (
 	"github.com/gin-contrib/sessions/redis"
 	"github.com/gin-gonic/gin"
 	_ "github.com/linux-do/credit/docs"
+	"github.com/linux-do/credit/internal/apps/admin/arbitration"
+	"github.com/linux-do/credit/internal/apps/admin/audit_log"
+	"github.com/linux-do/credit/internal/apps/admin/change_request"
+	"github.com/linux-do/credit/internal/apps/admin/risk_control"
+	"github.com/linux-do/credit/internal/apps/admin/role"
 	"github.com/linux-do/credit/internal/apps/admin/system_config"
+	"github.com/linux-do/credit/internal/apps/admin/user_manage"
 	"github.com/linux-do/credit/internal/apps/admin/user_pay_config"
 	"github.com/linux-do/credit/internal/apps/dashboard"
 	"github.com/linux-do/credit/internal/apps/health"
 	"github.com/linux-do/credit/internal/apps/oauth"
+	"github.com/linux-do/credit/internal/apps/oauth_server"
 	"github.com/linux-do/credit/internal/apps/order"
+	"github.com/linux-do/credit/internal/apps/totp"
 	"github.com/linux-do/credit/internal/apps/user"
 	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/model"
 	"github.com/linux-do/credit/internal/otel_trace"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -123,18 +141,45 @@ func Serve() {
 			apiV1Router.GET("/oauth/login", oauth.GetLoginURL)
 			apiV1Router.GET("/oauth/logout", oauth.LoginRequired(), oauth.Logout)
 			apiV1Router.POST("/oauth/callback", oauth.Callback)
-			apiV1Router.GET("/oauth/user-info", oauth.LoginRequired(), oauth.UserInfo)
+			apiV1Router.GET("/oauth/user-info", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadProfile), oauth.UserInfo)
+
+			// OAuth2 Authorization Server
+			oauth2Router := apiV1Router.Group("/oauth2")
+			{
+				oauth2Router.GET("/authorize", oauth.LoginRequired(), oauth_server.GetConsent)
+				oauth2Router.POST("/authorize", oauth.LoginRequired(), oauth_server.Authorize)
+				oauth2Router.POST("/token", oauth_server.Token)
+				oauth2Router.GET("/authorizations", oauth.LoginRequired(), oauth_server.ListAuthorizations)
+				oauth2Router.DELETE("/authorizations/:id", oauth.LoginRequired(), oauth_server.RevokeAuthorization)
+
+				oauth2Router.GET("/userinfo", oauth_server.RequireAccessToken(model.OAuthScopeProfile), oauth_server.UserInfo)
+				oauth2Router.GET("/balance", oauth_server.RequireAccessToken(model.OAuthScopeBalance), oauth_server.GetBalance)
+				oauth2Router.POST("/charge", oauth_server.RequireAccessToken(model.OAuthScopeChargeLimited), oauth_server.Charge)
+			}
 
 			// User
 			userRouter := apiV1Router.Group("/user")
-			userRouter.Use(oauth.LoginRequired())
 			{
-				userRouter.PUT("/pay-key", user.UpdatePayKey)
+				userRouter.PUT("/pay-key", oauth.LoginRequired(), user.UpdatePayKey)
+				userRouter.GET("/balance/stream", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadProfile), user.StreamBalance)
+
+				// TOTP
+				userRouter.GET("/totp", oauth.LoginRequired(), totp.GetStatus)
+				userRouter.POST("/totp/enroll", oauth.LoginRequired(), totp.Enroll)
+				userRouter.POST("/totp/activate", oauth.LoginRequired(), totp.Activate)
+				userRouter.POST("/totp/disable", oauth.LoginRequired(), totp.Disable)
+				userRouter.PUT("/totp/threshold", oauth.LoginRequired(), totp.UpdateThreshold)
+				userRouter.POST("/totp/recovery-codes", oauth.LoginRequired(), totp.RegenerateRecoveryCodes)
+
+				// Personal Access Token
+				userRouter.GET("/access-tokens", oauth.LoginRequired(), access_token.ListAccessTokens)
+				userRouter.POST("/access-tokens", oauth.LoginRequired(), access_token.CreateAccessToken)
+				userRouter.DELETE("/access-tokens/:id", oauth.LoginRequired(), access_token.RevokeAccessToken)
 			}
 
 			// Dashboard
 			dashboardRouter := apiV1Router.Group("/dashboard")
-			dashboardRouter.Use(oauth.LoginRequired())
+			dashboardRouter.Use(oauth.LoginOrAccessTokenRequired(model.TokenScopeReadDashboard))
 			{
 				dashboardRouter.GET("/stats/daily", dashboard.GetDailyStats)
 				dashboardRouter.GET("/stats/top-customers", dashboard.GetTopCustomers)
@@ -142,21 +187,80 @@ func Serve() {
 
 			// Order
 			orderRouter := apiV1Router.Group("/order")
-			orderRouter.Use(oauth.LoginRequired())
 			{
-				orderRouter.POST("/transactions", order.ListTransactions)
-				orderRouter.POST("/dispute", dispute.CreateDispute)
-				orderRouter.POST("/disputes/merchant", dispute.ListMerchantDisputes)
-				orderRouter.POST("/disputes", dispute.ListDisputes)
-				orderRouter.POST("/refund-review", dispute.RefundReview)
-				orderRouter.POST("/dispute/close", dispute.CloseDispute)
+				orderRouter.POST("/transactions", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadOrders), order.ListTransactions)
+				orderRouter.POST("/dispute", oauth.LoginRequired(), dispute.CreateDispute)
+				orderRouter.POST("/disputes/merchant", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadOrders), dispute.ListMerchantDisputes)
+				orderRouter.POST("/disputes", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadOrders), dispute.ListDisputes)
+				orderRouter.POST("/refund-review", oauth.LoginRequired(), dispute.RefundReview)
+				orderRouter.POST("/dispute/close", oauth.LoginRequired(), dispute.CloseDispute)
+				orderRouter.POST("/dispute/appeal", oauth.LoginRequired(), dispute.AppealDispute)
+				orderRouter.POST("/dispute/messages", oauth.LoginRequired(), dispute.SendDisputeMessage)
+				orderRouter.POST("/dispute/offer/respond", oauth.LoginRequired(), dispute.RespondDisputeOffer)
+				orderRouter.GET("/dispute/attachments/:id", oauth.LoginRequired(), dispute.DownloadDisputeAttachment)
 			}
 
 			// Payment
 			paymentRouter := apiV1Router.Group("/payment")
-			paymentRouter.Use(oauth.LoginRequired())
 			{
-				paymentRouter.POST("/transfer", payment.Transfer)
+				paymentRouter.POST("/transfer", oauth.LoginOrAccessTokenRequired(model.TokenScopeWriteTransfer), payment.Transfer)
+				paymentRouter.GET("/recipient", oauth.LoginOrAccessTokenRequired(model.TokenScopeWriteTransfer), payment.GetRecipient)
+
+				// Payment Request
+				paymentRouter.POST("/request", oauth.LoginRequired(), payment_request.CreatePaymentRequest)
+				paymentRouter.POST("/requests", oauth.LoginRequired(), payment_request.ListPaymentRequests)
+				paymentRouter.GET("/requests/:token", oauth.LoginOrAccessTokenRequired(model.TokenScopeWritePayment), payment_request.GetPaymentRequestByToken)
+				paymentRouter.POST("/request/approve", oauth.LoginOrAccessTokenRequired(model.TokenScopeWritePayment), payment_request.ApprovePaymentRequest)
+				paymentRouter.POST("/request/decline", oauth.LoginRequired(), payment_request.DeclinePaymentRequest)
+				paymentRouter.POST("/request/cancel", oauth.LoginRequired(), payment_request.CancelPaymentRequest)
+
+				// Scheduled Transfer
+				paymentRouter.POST("/scheduled-transfer", oauth.LoginRequired(), scheduled_transfer.CreateScheduledTransfer)
+				paymentRouter.POST("/scheduled-transfers", oauth.LoginRequired(), scheduled_transfer.ListScheduledTransfers)
+				paymentRouter.POST("/scheduled-transfer/pause", oauth.LoginRequired(), scheduled_transfer.PauseScheduledTransfer)
+				paymentRouter.POST("/scheduled-transfer/resume", oauth.LoginRequired(), scheduled_transfer.ResumeScheduledTransfer)
+				paymentRouter.POST("/scheduled-transfer/cancel", oauth.LoginRequired(), scheduled_transfer.CancelScheduledTransfer)
+				paymentRouter.POST("/scheduled-transfer/runs", oauth.LoginRequired(), scheduled_transfer.ListScheduledTransferRuns)
+			}
+
+			// Red Packet
+			redPacketRouter := apiV1Router.Group("/red-packet")
+			redPacketRouter.Use(oauth.LoginRequired())
+			{
+				redPacketRouter.POST("", red_packet.CreateRedPacket)
+				redPacketRouter.POST("/list", red_packet.ListRedPackets)
+				redPacketRouter.POST("/claim", red_packet.ClaimRedPacket)
+				redPacketRouter.GET("/:token", red_packet.GetRedPacket)
+				redPacketRouter.GET("/:token/claims", red_packet.ListRedPacketClaims)
+			}
+
+			// Notification
+			notificationRouter := apiV1Router.Group("/notification")
+			{
+				notificationRouter.POST("/list", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadNotifications), notification.ListNotifications)
+				notificationRouter.GET("/unread-count", oauth.LoginOrAccessTokenRequired(model.TokenScopeReadNotifications), notification.GetUnreadCount)
+				notificationRouter.POST("/read", oauth.LoginRequired(), notification.MarkRead)
+				notificationRouter.GET("/preferences", oauth.LoginRequired(), notification.ListPreferences)
+				notificationRouter.PUT("/preferences", oauth.LoginRequired(), notification.UpdatePreference)
+			}
+
+			// Email
+			emailRouter := apiV1Router.Group("/email")
+			emailRouter.Use(oauth.LoginRequired())
+			{
+				emailRouter.GET("", email.GetEmail)
+				emailRouter.POST("", email.BindEmail)
+				emailRouter.POST("/verify", email.VerifyEmail)
+				emailRouter.PUT("/settings", email.UpdateEmailSettings)
+			}
+
+			// QRCode
+			qrcodeRouter := apiV1Router.Group("/qrcode")
+			{
+				qrcodeRouter.GET("/order", qrcode.GetOrderQRCode)
+				qrcodeRouter.GET("/payment-links/:token", qrcode.GetPaymentLinkQRCode)
+				qrcodeRouter.GET("/receive", oauth.LoginRequired(), qrcode.GetReceiveQRCode)
+				qrcodeRouter.GET("/payment-requests/:token", qrcode.GetPaymentRequestQRCode)
 			}
 
 			// Config (public)
@@ -179,6 +283,11 @@ func Serve() {
 					apiKeyRouter.PUT("", api_key.UpdateAPIKey)
 					apiKeyRouter.DELETE("", api_key.DeleteAPIKey)
 
+					// Client Secrets
+					apiKeyRouter.GET("/secrets", api_key.ListSecrets)
+					apiKeyRouter.POST("/secrets/rotate", api_key.RotateSecret)
+					apiKeyRouter.DELETE("/secrets/:secretId", api_key.RevokeSecret)
+
 					// Payment Links
 					linkRouter := apiKeyRouter.Group("/payment-links")
 					{
@@ -188,14 +297,24 @@ func Serve() {
 					}
 				}
 
-				merchantRouter.GET("/payment-links/:token", oauth.LoginRequired(), link.GetPaymentLinkByToken)
-				merchantRouter.POST("/payment-links/pay", oauth.LoginRequired(), link.PayByLink)
+				// Merchant Payouts
+				payoutRouter := merchantRouter.Group("/payouts")
+				payoutRouter.Use(payment.RequireMerchantAuth())
+				{
+					payoutRouter.POST("", payout.CreatePayout)
+					payoutRouter.GET("/:id", payout.GetPayout)
+				}
+
+				merchantRouter.GET("/orders/:trade_no/stream", payment.RequireMerchantAuth(), payment.StreamMerchantOrder)
+				merchantRouter.GET("/payment-links/:token", oauth.LoginOrAccessTokenRequired(model.TokenScopeWritePayment), link.GetPaymentLinkByToken)
+				merchantRouter.POST("/payment-links/pay", oauth.LoginOrAccessTokenRequired(model.TokenScopeWritePayment), link.PayByLink)
 
 				// MerchantAPIKey Payment
 				MerchantPaymentRouter := merchantRouter.Group("/payment")
 				{
-					MerchantPaymentRouter.GET("/order", oauth.LoginRequired(), payment.GetPaymentPageDetails)
-					MerchantPaymentRouter.POST("", oauth.LoginRequired(), payment.PayMerchantOrder)
+					MerchantPaymentRouter.GET("/order", oauth.LoginOrAccessTokenRequired(model.TokenScopeWritePayment), payment.GetPaymentPageDetails)
+					MerchantPaymentRouter.GET("/order/stream", oauth.LoginRequired(), payment.StreamPaymentOrder)
+					MerchantPaymentRouter.POST("", oauth.LoginOrAccessTokenRequired(model.TokenScopeWritePayment), payment.PayMerchantOrder)
 				}
 			}
 
@@ -204,26 +323,74 @@ func Serve() {
 			adminRouter.Use(oauth.LoginRequired(), admin.LoginAdminRequired())
 			{
 				// System Config
-				adminRouter.POST("/system-configs", system_config.CreateSystemConfig)
-				adminRouter.GET("/system-configs", system_config.ListSystemConfigs)
+				adminRouter.POST("/system-configs", admin.RequirePermission(admin.PermSystemConfigWrite), system_config.CreateSystemConfig)
+				adminRouter.GET("/system-configs", admin.RequirePermission(admin.PermSystemConfigRead), system_config.ListSystemConfigs)
 
 				systemConfigRouter := adminRouter.Group("/system-configs/:key")
 				{
-					systemConfigRouter.GET("", system_config.GetSystemConfig)
-					systemConfigRouter.PUT("", system_config.UpdateSystemConfig)
-					systemConfigRouter.DELETE("", system_config.DeleteSystemConfig)
+					systemConfigRouter.GET("", admin.RequirePermission(admin.PermSystemConfigRead), system_config.GetSystemConfig)
+					systemConfigRouter.PUT("", admin.RequirePermission(admin.PermSystemConfigWrite), system_config.UpdateSystemConfig)
+					systemConfigRouter.DELETE("", admin.RequirePermission(admin.PermSystemConfigWrite), system_config.DeleteSystemConfig)
 				}
 
 				// User Credit Config
-				adminRouter.POST("/user-pay-configs", user_pay_config.CreateUserPayConfig)
-				adminRouter.GET("/user-pay-configs", user_pay_config.ListUserPayConfigs)
+				adminRouter.POST("/user-pay-configs", admin.RequirePermission(admin.PermPayConfigWrite), user_pay_config.CreateUserPayConfig)
+				adminRouter.GET("/user-pay-configs", admin.RequirePermission(admin.PermPayConfigRead), user_pay_config.ListUserPayConfigs)
 
 				userPayConfigRouter := adminRouter.Group("/user-pay-configs/:id")
 				{
-					userPayConfigRouter.GET("", user_pay_config.GetUserPayConfig)
-					userPayConfigRouter.PUT("", user_pay_config.UpdateUserPayConfig)
-					userPayConfigRouter.DELETE("", user_pay_config.DeleteUserPayConfig)
+					userPayConfigRouter.GET("", admin.RequirePermission(admin.PermPayConfigRead), user_pay_config.GetUserPayConfig)
+					userPayConfigRouter.PUT("", admin.RequirePermission(admin.PermPayConfigWrite), user_pay_config.UpdateUserPayConfig)
+					userPayConfigRouter.DELETE("", admin.RequirePermission(admin.PermPayConfigWrite), user_pay_config.DeleteUserPayConfig)
 				}
+
+				// Risk Control
+				adminRouter.POST("/risk-rules", admin.RequirePermission(admin.PermRiskWrite), risk_control.CreateRiskRule)
+				adminRouter.GET("/risk-rules", admin.RequirePermission(admin.PermRiskRead), risk_control.ListRiskRules)
+				adminRouter.PUT("/risk-rules/:id", admin.RequirePermission(admin.PermRiskWrite), risk_control.UpdateRiskRule)
+				adminRouter.DELETE("/risk-rules/:id", admin.RequirePermission(admin.PermRiskWrite), risk_control.DeleteRiskRule)
+				adminRouter.POST("/risk-decisions", admin.RequirePermission(admin.PermRiskRead), risk_control.ListRiskDecisions)
+				adminRouter.POST("/risk-decisions/:id/review", admin.RequirePermission(admin.PermRiskReview), risk_control.ReviewRiskDecision)
+				adminRouter.POST("/wash-trade-flags", admin.RequirePermission(admin.PermRiskRead), risk_control.ListWashTradeFlags)
+				adminRouter.POST("/wash-trade-flags/:id/review", admin.RequirePermission(admin.PermRiskReview), risk_control.ReviewWashTradeFlag)
+
+				// User Management
+				adminRouter.POST("/users/search", admin.RequirePermission(admin.PermUserRead), user_manage.SearchUsers)
+
+				userManageRouter := adminRouter.Group("/users/:id")
+				{
+					userManageRouter.GET("", admin.RequirePermission(admin.PermUserRead), user_manage.GetUserDetail)
+					userManageRouter.POST("/status", admin.RequirePermission(admin.PermUserBan), user_manage.UpdateUserStatus)
+					userManageRouter.POST("/adjust", admin.RequirePermission(admin.PermUserAdjust), user_manage.AdjustBalance)
+					userManageRouter.POST("/revoke-sessions", admin.RequirePermission(admin.PermUserBan), user_manage.RevokeUserSessions)
+					userManageRouter.POST("/unlock-pay-key", admin.RequirePermission(admin.PermUserBan), user_manage.UnlockPayKey)
+					userManageRouter.POST("/operations", admin.RequirePermission(admin.PermUserRead), user_manage.ListUserOperations)
+				}
+
+				// Audit Log
+				adminRouter.POST("/audit-logs", admin.RequirePermission(admin.PermAuditRead), audit_log.ListAuditLogs)
+				adminRouter.GET("/audit-logs/export", admin.RequirePermission(admin.PermAuditRead), audit_log.ExportAuditLogs)
+				adminRouter.GET("/audit-logs/verify", admin.RequirePermission(admin.PermAuditRead), audit_log.VerifyAuditLogs)
+
+				// Dispute Arbitration
+				adminRouter.POST("/disputes", admin.RequirePermission(admin.PermDisputeRead), arbitration.ListArbitrations)
+				adminRouter.GET("/disputes/:id", admin.RequirePermission(admin.PermDisputeRead), arbitration.GetArbitration)
+				adminRouter.POST("/disputes/:id/rule", admin.RequirePermission(admin.PermDisputeWrite), arbitration.RuleDispute)
+				adminRouter.POST("/disputes/:id/offers", admin.RequirePermission(admin.PermDisputeWrite), arbitration.ProposeOffer)
+				adminRouter.POST("/disputes/:id/messages", admin.RequirePermission(admin.PermDisputeWrite), arbitration.SendArbitrationMessage)
+				adminRouter.GET("/dispute-attachments/:id", admin.RequirePermission(admin.PermDisputeRead), arbitration.DownloadArbitrationAttachment)
+
+				// Change Request
+				adminRouter.POST("/change-requests", admin.RequirePermission(admin.PermSystemConfigRead), change_request.ListChangeRequests)
+				adminRouter.POST("/change-requests/:id/approve", admin.RequirePermission(admin.PermChangeRequestReview), change_request.ApproveChangeRequest)
+				adminRouter.POST("/change-requests/:id/reject", admin.RequirePermission(admin.PermChangeRequestReview), change_request.RejectChangeRequest)
+
+				// Role
+				adminRouter.GET("/roles", admin.RequirePermission(admin.PermRoleRead), role.ListRoles)
+				adminRouter.GET("/roles/me", admin.RequirePermission(admin.PermRoleRead), role.GetMyRoles)
+				adminRouter.POST("/user-roles", admin.RequirePermission(admin.PermRoleManage), role.ListUserRoles)
+				adminRouter.POST("/user-roles/grant", admin.RequirePermission(admin.PermRoleManage), role.GrantUserRole)
+				adminRouter.POST("/user-roles/revoke", admin.RequirePermission(admin.PermRoleManage), role.RevokeUserRole)
 			}
 		}
 	}
@@ -238,6 +405,7 @@ func Serve() {
 		Addr:    config.Config.App.Addr,
 		Handler: r,
 	}
+	srv.RegisterOnShutdown(stream.Shutdown)
 
 	go func() {
 		log.Printf("[API] server starting on %s\n", config.Config.App.Addr)
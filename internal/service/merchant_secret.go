@@ -0,0 +1,133 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"gorm.io/gorm"
+)
+
+const (
+	clientSecretPrefixLength  = 8                        // 列表中展示的密钥前缀长度
+	clientSecretTouchInterval = time.Minute              // 最近使用时间的最小写库间隔
+	clientSecretSealPurpose   = "merchant_client_secret" // 派生加密密钥的用途标识
+)
+
+// SigningSecret 解密后的商户密钥，仅用于 MD5 签名计算
+type SigningSecret struct {
+	ID     uint64
+	Secret string
+}
+
+// IssueClientSecret 为商户应用生成新密钥并返回明文，明文只在此处出现一次
+func IssueClientSecret(tx *gorm.DB, apiKey *model.MerchantAPIKey) (string, *model.MerchantClientSecret, error) {
+	plaintext := util.GenerateUniqueIDSimple()
+	secret, err := SaveClientSecret(tx, apiKey, plaintext)
+	if err != nil {
+		return "", nil, err
+	}
+	return plaintext, secret, nil
+}
+
+// SaveClientSecret 以哈希与密文形式保存给定的密钥明文，也用于迁移历史明文密钥
+func SaveClientSecret(tx *gorm.DB, apiKey *model.MerchantAPIKey, plaintext string) (*model.MerchantClientSecret, error) {
+	cipherText, err := sealClientSecret(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := model.MerchantClientSecret{
+		APIKeyID:     apiKey.ID,
+		SecretPrefix: plaintext[:min(clientSecretPrefixLength, len(plaintext))],
+		SecretHash:   model.HashClientSecret(plaintext),
+		SecretCipher: cipherText,
+	}
+	if err := tx.Create(&secret).Error; err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// AuthenticateMerchant 通过 ClientID/ClientSecret 认证商户应用，轮换过渡期内新旧密钥均可通过
+func AuthenticateMerchant(tx *gorm.DB, clientID, clientSecret string) (*model.MerchantAPIKey, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var apiKey model.MerchantAPIKey
+	if err := apiKey.GetByClientID(tx, clientID); err != nil {
+		return nil, err
+	}
+
+	var secret model.MerchantClientSecret
+	if err := tx.
+		Where("api_key_id = ? AND secret_hash = ?", apiKey.ID, model.HashClientSecret(clientSecret)).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		First(&secret).Error; err != nil {
+		return nil, err
+	}
+
+	TouchClientSecret(tx, secret.ID)
+	return &apiKey, nil
+}
+
+// MerchantSigningSecrets 解密商户应用当前全部有效密钥，按创建时间倒序，首个即回调签名所用密钥
+func MerchantSigningSecrets(tx *gorm.DB, apiKey *model.MerchantAPIKey) ([]SigningSecret, error) {
+	var secrets []model.MerchantClientSecret
+	if err := tx.
+		Where("api_key_id = ?", apiKey.ID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&secrets).Error; err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, errors.New("商户应用没有有效密钥")
+	}
+
+	result := make([]SigningSecret, 0, len(secrets))
+	for _, secret := range secrets {
+		plaintext, err := util.OpenSecret(config.Config.App.SecretEncryptionKey, clientSecretSealPurpose, secret.SecretCipher)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, SigningSecret{ID: secret.ID, Secret: plaintext})
+	}
+	return result, nil
+}
+
+// TouchClientSecret 记录密钥最近使用时间，间隔内的重复使用不再写库；写库失败只记录日志
+func TouchClientSecret(tx *gorm.DB, secretID uint64) {
+	now := time.Now()
+	if err := tx.
+		Model(&model.MerchantClientSecret{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", secretID, now.Add(-clientSecretTouchInterval)).
+		Update("last_used_at", now).Error; err != nil {
+		logger.ErrorF(tx.Statement.Context, "更新商户密钥[%d]最近使用时间失败: %v", secretID, err)
+	}
+}
+
+// sealClientSecret 使用服务端主密钥派生的密钥加密商户密钥明文，不依赖任何数据库字段
+func sealClientSecret(plaintext string) (string, error) {
+	return util.SealSecret(config.Config.App.SecretEncryptionKey, clientSecretSealPurpose, plaintext)
+}
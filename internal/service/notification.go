@@ -0,0 +1,90 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/linux-do/credit/internal/model"
+	"gorm.io/gorm"
+)
+
+// Notify 为用户写入一条站内通知，需在业务事务内调用以保证一致性
+// 用户关闭了该分类的通知时直接跳过
+func Notify(tx *gorm.DB, userID uint64, category model.NotificationCategory, title, content string, relatedID *uint64) error {
+	var disabled int64
+	if err := tx.Model(&model.NotificationPreference{}).
+		Where("user_id = ? AND category = ? AND enabled = ?", userID, category, false).
+		Count(&disabled).Error; err != nil {
+		return err
+	}
+	if disabled > 0 {
+		return nil
+	}
+
+	return tx.Create(&model.Notification{
+		UserID:    userID,
+		Category:  category,
+		Title:     title,
+		Content:   content,
+		RelatedID: relatedID,
+	}).Error
+}
+
+// NotifyPayLevelChange 在用户支付积分变动 scoreDelta 后，若支付等级发生变化则通知用户
+func NotifyPayLevelChange(tx *gorm.DB, userID uint64, scoreDelta int64) error {
+	if scoreDelta == 0 {
+		return nil
+	}
+
+	var user model.User
+	if err := tx.Select("id", "pay_score").Where("id = ?", userID).First(&user).Error; err != nil {
+		return err
+	}
+
+	// 积分落在任何等级区间之外时不做通知
+	var oldConfig, newConfig model.UserPayConfig
+	if err := oldConfig.GetByPayScore(tx, user.PayScore-scoreDelta); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := newConfig.GetByPayScore(tx, user.PayScore); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if oldConfig.Level == newConfig.Level {
+		return nil
+	}
+
+	title := "支付等级已提升"
+	if newConfig.Level < oldConfig.Level {
+		title = "支付等级已下降"
+	}
+	return Notify(
+		tx,
+		userID,
+		model.NotificationCategoryPayLevel,
+		title,
+		fmt.Sprintf("当前支付积分 %d，支付等级由 %d 级变为 %d 级", user.PayScore, oldConfig.Level, newConfig.Level),
+		nil,
+	)
+}
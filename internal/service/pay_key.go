@@ -0,0 +1,211 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+)
+
+const (
+	payKeyFailuresKeyFormat = "pay_key:failures:%d" // 连续失败次数，窗口期与锁定时长一致
+	payKeyDelayKeyFormat    = "pay_key:delay:%d"    // 失败后的冷却期，存在期间拒绝验证
+	payKeyLockKeyFormat     = "pay_key:lock:%d"     // 锁定标记，存在期间拒绝验证
+)
+
+// PayKeyAttemptStatus 支付密码验证失败时返回给客户端的尝试状态
+type PayKeyAttemptStatus struct {
+	RemainingAttempts int        `json:"remaining_attempts"`
+	RetryAfter        int64      `json:"retry_after"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+}
+
+// CheckPayKey 验证支付密码并记录失败次数，验证失败时直接写入错误响应
+// 连续失败后需等待递增的冷却时间，达到上限后锁定一段时间并通知用户
+func CheckPayKey(c *gin.Context, user *model.User, payKey string) bool {
+	ctx := c.Request.Context()
+
+	maxAttempts, lockoutMinutes, delays, err := loadPayKeyLimits(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+
+	lockKey := db.PrefixedKey(fmt.Sprintf(payKeyLockKeyFormat, user.ID))
+	delayKey := db.PrefixedKey(fmt.Sprintf(payKeyDelayKeyFormat, user.ID))
+	failuresKey := db.PrefixedKey(fmt.Sprintf(payKeyFailuresKeyFormat, user.ID))
+
+	if ttl, err := db.Redis.PTTL(ctx, lockKey).Result(); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	} else if ttl > 0 {
+		respondPayKeyLocked(c, ttl)
+		return false
+	}
+
+	if ttl, err := db.Redis.PTTL(ctx, delayKey).Result(); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	} else if ttl > 0 {
+		c.JSON(http.StatusTooManyRequests, util.Response[PayKeyAttemptStatus]{
+			ErrorMsg: common.PayKeyRetryTooSoon,
+			Data:     PayKeyAttemptStatus{RetryAfter: ceilSeconds(ttl)},
+		})
+		return false
+	}
+
+	// 先计数再验证，并发请求各自占用一次尝试机会，保证总尝试次数不超过上限
+	lockout := time.Duration(lockoutMinutes) * time.Minute
+	pipe := db.Redis.TxPipeline()
+	incr := pipe.Incr(ctx, failuresKey)
+	pipe.Expire(ctx, failuresKey, lockout)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+	attempt := int(incr.Val())
+	if attempt > maxAttempts {
+		lockPayKey(ctx, user, lockKey, failuresKey, lockout)
+		respondPayKeyLocked(c, lockout)
+		return false
+	}
+
+	if user.VerifyPayKey(ctx, payKey) {
+		if err := db.Redis.Del(ctx, failuresKey, delayKey).Err(); err != nil {
+			logger.ErrorF(ctx, "清除用户[%d]支付密码失败次数失败: %v", user.ID, err)
+		}
+		return true
+	}
+
+	if attempt >= maxAttempts {
+		lockPayKey(ctx, user, lockKey, failuresKey, lockout)
+		respondPayKeyLocked(c, lockout)
+		return false
+	}
+
+	status := PayKeyAttemptStatus{RemainingAttempts: maxAttempts - attempt}
+	if len(delays) > 0 {
+		delay := time.Duration(delays[min(attempt, len(delays))-1]) * time.Second
+		if delay > 0 {
+			if err := db.Redis.Set(ctx, delayKey, attempt, delay).Err(); err != nil {
+				logger.ErrorF(ctx, "设置用户[%d]支付密码冷却期失败: %v", user.ID, err)
+			}
+			status.RetryAfter = ceilSeconds(delay)
+		}
+	}
+	c.JSON(http.StatusBadRequest, util.Response[PayKeyAttemptStatus]{
+		ErrorMsg: common.PayKeyIncorrect,
+		Data:     status,
+	})
+	return false
+}
+
+// UnlockPayKey 清除用户的支付密码失败次数、冷却期与锁定状态
+// 返回解锁前是否处于锁定状态
+func UnlockPayKey(ctx context.Context, userID uint64) (bool, error) {
+	lockKey := db.PrefixedKey(fmt.Sprintf(payKeyLockKeyFormat, userID))
+	locked, err := db.Redis.Exists(ctx, lockKey).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if err := db.Redis.Del(
+		ctx,
+		lockKey,
+		db.PrefixedKey(fmt.Sprintf(payKeyDelayKeyFormat, userID)),
+		db.PrefixedKey(fmt.Sprintf(payKeyFailuresKeyFormat, userID)),
+	).Err(); err != nil {
+		return false, err
+	}
+	return locked > 0, nil
+}
+
+// loadPayKeyLimits 读取支付密码尝试上限、锁定时长（分钟）与各次失败后的冷却秒数
+func loadPayKeyLimits(ctx context.Context) (int, int, []int, error) {
+	maxAttempts, err := model.GetIntByKey(ctx, model.ConfigKeyPayKeyMaxAttempts)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if maxAttempts <= 0 {
+		return 0, 0, nil, errors.New(common.InvalidPayKeyLimitConfig)
+	}
+	lockoutMinutes, err := model.GetIntByKey(ctx, model.ConfigKeyPayKeyLockoutMinutes)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if lockoutMinutes <= 0 {
+		return 0, 0, nil, errors.New(common.InvalidPayKeyLimitConfig)
+	}
+	delays, err := model.GetIntListByKey(ctx, model.ConfigKeyPayKeyRetryDelays)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return maxAttempts, lockoutMinutes, delays, nil
+}
+
+// lockPayKey 锁定用户支付密码验证，首次锁定时通知用户
+func lockPayKey(ctx context.Context, user *model.User, lockKey, failuresKey string, lockout time.Duration) {
+	locked, err := db.Redis.SetNX(ctx, lockKey, time.Now().Unix(), lockout).Result()
+	if err != nil {
+		logger.ErrorF(ctx, "锁定用户[%d]支付密码失败: %v", user.ID, err)
+		return
+	}
+	if err := db.Redis.Del(ctx, failuresKey).Err(); err != nil {
+		logger.ErrorF(ctx, "清除用户[%d]支付密码失败次数失败: %v", user.ID, err)
+	}
+	if !locked {
+		return
+	}
+
+	if err := Notify(
+		db.DB(ctx),
+		user.ID,
+		model.NotificationCategorySecurity,
+		"支付密码已被锁定",
+		fmt.Sprintf("支付密码连续输错次数过多，已锁定 %d 分钟。如非本人操作，请尽快修改支付密码", int(lockout.Minutes())),
+		nil,
+	); err != nil {
+		logger.ErrorF(ctx, "通知用户[%d]支付密码锁定失败: %v", user.ID, err)
+	}
+}
+
+// respondPayKeyLocked 返回支付密码锁定响应
+func respondPayKeyLocked(c *gin.Context, ttl time.Duration) {
+	lockedUntil := time.Now().Add(ttl)
+	c.JSON(http.StatusLocked, util.Response[PayKeyAttemptStatus]{
+		ErrorMsg: common.PayKeyLocked,
+		Data: PayKeyAttemptStatus{
+			RetryAfter:  ceilSeconds(ttl),
+			LockedUntil: &lockedUntil,
+		},
+	})
+}
+
+// ceilSeconds 将时长向上取整为秒
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
@@ -83,18 +83,22 @@ func DeductUserBalance(tx *gorm.DB, userID uint64, amount decimal.Decimal) error
 		return errors.New(common.InsufficientBalance)
 	}
 
-	return nil
+	return NotifyPayLevelChange(tx, userID, amount.Round(0).IntPart())
 }
 
 // AddMerchantBalance 增加商户余额和积分
 func AddMerchantBalance(tx *gorm.DB, merchantUserID uint64, amount decimal.Decimal, scoreIncrease int64) error {
-	return tx.Model(&model.User{}).
+	if err := tx.Model(&model.User{}).
 		Where("id = ?", merchantUserID).
 		UpdateColumns(map[string]interface{}{
 			"available_balance": gorm.Expr("available_balance + ?", amount),
 			"total_receive":     gorm.Expr("total_receive + ?", amount),
 			"pay_score":         gorm.Expr("pay_score + ?", scoreIncrease),
-		}).Error
+		}).Error; err != nil {
+		return err
+	}
+
+	return NotifyPayLevelChange(tx, merchantUserID, scoreIncrease)
 }
 
 // CalculateFee 计算手续费和商户实收金额
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/risk"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RefundOrder 从商家（收款方）退回 amount 给付款方并累计订单退款金额，商户接口退款与争议退款共用
+// 订单记录了手续费时按退款比例退还手续费，商家只承担实收部分；积分按退款金额扣减。
+// 手续费在支付时直接从商家实收中扣除、不计入任何账户，因此退还的手续费部分由平台承担，
+// 即退款时付款方收到的额度中有这部分是新增发放的。
+// 所有退款路径都经过此处，风控评估统一在此进行，被拦截时返回 common.RiskBlocked
+func RefundOrder(tx *gorm.DB, order *model.Order, amount decimal.Decimal) error {
+	var payeeUser model.User
+	if err := payeeUser.GetByID(tx, order.PayeeUserID); err != nil {
+		return err
+	}
+
+	// 风控评估
+	if _, err := risk.Evaluate(tx.Statement.Context, tx, &risk.Event{
+		Scene:              risk.SceneRefund,
+		User:               &payeeUser,
+		CounterpartyUserID: order.PayerUserID,
+		Amount:             amount,
+		ReferenceID:        &order.ID,
+	}); err != nil {
+		return err
+	}
+
+	// 获取商家的支付配置
+	var merchantPayConfig model.UserPayConfig
+	if err := merchantPayConfig.GetByPayScore(tx, payeeUser.PayScore); err != nil {
+		return err
+	}
+
+	// 计算商家积分减少：退款金额 × 商家的 score_rate
+	merchantScoreDecrease := amount.Mul(merchantPayConfig.ScoreRate).Round(0).IntPart()
+
+	// 计算按比例退还的手续费：手续费 × 退款金额 / 订单金额
+	feeRefund := decimal.Zero
+	if order.Fee.GreaterThan(decimal.Zero) && order.Amount.GreaterThan(decimal.Zero) {
+		feeRefund = order.Fee.Mul(amount).Div(order.Amount).Round(2)
+	}
+	merchantDeduct := amount.Sub(feeRefund)
+
+	// 商家(收款方)退款：扣除可用余额、总收款和积分
+	if err := tx.Model(&model.User{}).
+		Where("id = ?", payeeUser.ID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance - ?", merchantDeduct),
+			"total_receive":     gorm.Expr("total_receive - ?", merchantDeduct),
+			"pay_score":         gorm.Expr("pay_score - ?", merchantScoreDecrease),
+		}).Error; err != nil {
+		return err
+	}
+
+	// 付款方收到退款：增加可用余额，减少总支付和支付积分
+	if err := tx.Model(&model.User{}).
+		Where("id = ?", order.PayerUserID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance + ?", amount),
+			"total_payment":     gorm.Expr("total_payment - ?", amount),
+			"pay_score":         gorm.Expr("pay_score - ?", amount.Round(0).IntPart()),
+		}).Error; err != nil {
+		return err
+	}
+
+	if err := NotifyPayLevelChange(tx, payeeUser.ID, -merchantScoreDecrease); err != nil {
+		return err
+	}
+	if err := NotifyPayLevelChange(tx, order.PayerUserID, -amount.Round(0).IntPart()); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.Order{}).
+		Where("id = ?", order.ID).
+		UpdateColumn("refunded_amount", gorm.Expr("refunded_amount + ?", amount)).Error; err != nil {
+		return err
+	}
+
+	return Notify(
+		tx,
+		order.PayerUserID,
+		model.NotificationCategoryRefund,
+		"订单已退款",
+		fmt.Sprintf("订单「%s」已退款 %s，款项已退回可用余额", order.OrderName, amount.StringFixed(2)),
+		&order.ID,
+	)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/config"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/linux-do/credit/internal/util"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	totpFailuresKeyFormat = "totp:failures:%d" // TOTP 连续失败次数
+	totpMaxFailures       = 5
+	totpFailureWindow     = 15 * time.Minute
+	totpSecretSealPurpose = "totp_secret" // 派生加密密钥的用途标识
+)
+
+// SealTOTPSecret 使用服务端主密钥派生的密钥加密 TOTP 种子，不依赖任何数据库字段
+func SealTOTPSecret(secret string) (string, error) {
+	return util.SealSecret(config.Config.App.SecretEncryptionKey, totpSecretSealPurpose, secret)
+}
+
+// OpenTOTPSecret 解密 SealTOTPSecret 加密的 TOTP 种子
+func OpenTOTPSecret(sealed string) (string, error) {
+	return util.OpenSecret(config.Config.App.SecretEncryptionKey, totpSecretSealPurpose, sealed)
+}
+
+// CheckTOTP 用户已启用 TOTP 时校验验证码或恢复码，未启用时直接通过
+// 校验失败时直接写入错误响应
+func CheckTOTP(c *gin.Context, user *model.User, code string) bool {
+	totp, err := GetEnabledTOTP(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+	if totp == nil {
+		return true
+	}
+	return checkTOTPCode(c, user, totp, code)
+}
+
+// CheckTOTPForAmount 支付金额超过用户设置的阈值时校验 TOTP，其余情况直接通过
+// 校验失败时直接写入错误响应
+func CheckTOTPForAmount(c *gin.Context, user *model.User, amount decimal.Decimal, code string) bool {
+	totp, err := GetEnabledTOTP(db.DB(c.Request.Context()), user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+	if totp == nil || !totp.RequiredForAmount(amount) {
+		return true
+	}
+	return checkTOTPCode(c, user, totp, code)
+}
+
+// GetEnabledTOTP 查询用户已启用的 TOTP 配置，未启用时返回 nil
+func GetEnabledTOTP(tx *gorm.DB, userID uint64) (*model.UserTOTP, error) {
+	var totp model.UserTOTP
+	if err := tx.Where("user_id = ? AND enabled_at IS NOT NULL", userID).First(&totp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &totp, nil
+}
+
+// ConsumeTOTPStep 记录已使用的时间步，同一验证码不能重复使用
+func ConsumeTOTPStep(tx *gorm.DB, userID uint64, step int64) (bool, error) {
+	result := tx.Model(&model.UserTOTP{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// checkTOTPCode 校验验证码或恢复码并限制连续失败次数
+func checkTOTPCode(c *gin.Context, user *model.User, totp *model.UserTOTP, code string) bool {
+	if code == "" {
+		c.JSON(http.StatusBadRequest, util.Err(common.TOTPRequired))
+		return false
+	}
+
+	ctx := c.Request.Context()
+	failuresKey := db.PrefixedKey(fmt.Sprintf(totpFailuresKeyFormat, user.ID))
+
+	pipe := db.Redis.TxPipeline()
+	incr := pipe.Incr(ctx, failuresKey)
+	pipe.Expire(ctx, failuresKey, totpFailureWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+	if incr.Val() > totpMaxFailures {
+		c.JSON(http.StatusTooManyRequests, util.Err(common.TOTPTooManyAttempts))
+		return false
+	}
+
+	ok, err := verifyTOTPCode(ctx, user, totp, code)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return false
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, util.Err(common.TOTPCodeIncorrect))
+		return false
+	}
+
+	if err := db.Redis.Del(ctx, failuresKey).Err(); err != nil {
+		logger.ErrorF(ctx, "清除用户[%d] TOTP 失败次数失败: %v", user.ID, err)
+	}
+	return true
+}
+
+// verifyTOTPCode 6 位数字按 TOTP 校验，其余按恢复码校验
+func verifyTOTPCode(ctx context.Context, user *model.User, totp *model.UserTOTP, code string) (bool, error) {
+	if len(code) == util.TOTPDigits {
+		secret, err := OpenTOTPSecret(totp.Secret)
+		if err != nil {
+			return false, err
+		}
+		step, ok := util.ValidateTOTP(secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return ConsumeTOTPStep(db.DB(ctx), user.ID, step)
+	}
+
+	var remaining int64
+	if err := db.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.UserTOTPRecoveryCode{}).
+			Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, model.HashRecoveryCode(code)).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New(common.TOTPCodeIncorrect)
+		}
+
+		if err := tx.Model(&model.UserTOTPRecoveryCode{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+
+		return Notify(
+			tx,
+			user.ID,
+			model.NotificationCategorySecurity,
+			"已使用 TOTP 恢复码",
+			fmt.Sprintf("你的账户使用了一个 TOTP 恢复码完成验证，剩余 %d 个可用恢复码。如非本人操作，请尽快修改支付密码", remaining),
+			nil,
+		)
+	}); err != nil {
+		if err.Error() == common.TOTPCodeIncorrect {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransferParams 转账结算参数
+type TransferParams struct {
+	PayerUserID uint64
+	PayeeUserID uint64
+	Amount      decimal.Decimal
+	OrderName   string
+	Remark      string
+}
+
+// SettleTransfer 执行转账结算
+// 锁定付款人并校验余额，创建转账订单，扣减付款人余额并增加收款人余额
+func SettleTransfer(tx *gorm.DB, params *TransferParams) (*model.Order, error) {
+	if err := DebitTransferPayer(tx, params.PayerUserID, params.Amount); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := model.Order{
+		OrderName:   params.OrderName,
+		PayerUserID: params.PayerUserID,
+		PayeeUserID: params.PayeeUserID,
+		Amount:      params.Amount,
+		Status:      model.OrderStatusSuccess,
+		Type:        model.OrderTypeTransfer,
+		Remark:      params.Remark,
+		TradeTime:   now,
+		ExpiresAt:   now.Add(24 * time.Hour),
+	}
+	if err := CreditTransferPayee(tx, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// DebitTransferPayer 锁定付款人，校验余额后扣减转账金额
+func DebitTransferPayer(tx *gorm.DB, payerUserID uint64, amount decimal.Decimal) error {
+	var payer model.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+		Where("id = ?", payerUserID).
+		First(&payer).Error; err != nil {
+		return err
+	}
+
+	if payer.AvailableBalance.LessThan(amount) {
+		return errors.New(common.InsufficientBalance)
+	}
+
+	return tx.Model(&model.User{}).
+		Where("id = ?", payerUserID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance - ?", amount),
+			"total_transfer":    gorm.Expr("total_transfer + ?", amount),
+		}).Error
+}
+
+// RefundTransferPayer 退回已扣减但未完成转账的金额
+func RefundTransferPayer(tx *gorm.DB, payerUserID uint64, amount decimal.Decimal) error {
+	return tx.Model(&model.User{}).
+		Where("id = ?", payerUserID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance + ?", amount),
+			"total_transfer":    gorm.Expr("total_transfer - ?", amount),
+		}).Error
+}
+
+// CreditTransferPayee 创建转账订单并增加收款人余额（付款人余额需已扣减）
+func CreditTransferPayee(tx *gorm.DB, order *model.Order) error {
+	if err := tx.Create(order).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&model.User{}).
+		Where("id = ?", order.PayeeUserID).
+		UpdateColumns(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance + ?", order.Amount),
+			"total_receive":     gorm.Expr("total_receive + ?", order.Amount),
+		}).Error
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/linux-do/credit/internal/common"
+	"github.com/linux-do/credit/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// TransferQuota 用户转账额度使用情况，限额为 nil 表示不限
+type TransferQuota struct {
+	DailyLimit     *int64
+	MonthlyLimit   *int64
+	DailyUsed      decimal.Decimal
+	MonthlyUsed    decimal.Decimal
+	RemainingQuota decimal.Decimal
+}
+
+// CheckTransferLimit 检查用户转账限额（单笔上限、账户注册天数、日/月累计限额）
+// 返回 nil 表示允许转账，返回 error 表示超限或查询失败
+func CheckTransferLimit(tx *gorm.DB, user *model.User, amount decimal.Decimal, payConfig *model.UserPayConfig) error {
+	if payConfig.MaxTransferAmount != nil && *payConfig.MaxTransferAmount > 0 &&
+		amount.GreaterThan(decimal.NewFromInt(*payConfig.MaxTransferAmount)) {
+		return errors.New(common.TransferAmountExceedsMax)
+	}
+
+	now := time.Now()
+	if payConfig.MinTransferAccountDays > 0 &&
+		now.Before(user.CreatedAt.AddDate(0, 0, payConfig.MinTransferAccountDays)) {
+		return errors.New(common.TransferAccountTooNew)
+	}
+
+	hasDailyLimit := payConfig.DailyTransferLimit != nil && *payConfig.DailyTransferLimit > 0
+	hasMonthlyLimit := payConfig.MonthlyTransferLimit != nil && *payConfig.MonthlyTransferLimit > 0
+	if !hasDailyLimit && !hasMonthlyLimit {
+		return nil
+	}
+
+	// 按月加锁，同时覆盖日限额与月限额的并发校验
+	// 锁 ID 的日期部分为 yyyymm，与支付每日限额的 yyyymmdd 不会冲突
+	monthPart := int64(now.Year()*100 + int(now.Month()))
+	lockID := int64(user.ID)*100000000 + monthPart
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+		return err
+	}
+
+	quota, err := GetTransferQuota(tx, user.ID, payConfig)
+	if err != nil {
+		return err
+	}
+
+	if hasDailyLimit && quota.DailyUsed.Add(amount).GreaterThan(decimal.NewFromInt(*payConfig.DailyTransferLimit)) {
+		return errors.New(common.TransferDailyLimitExceeded)
+	}
+	if hasMonthlyLimit && quota.MonthlyUsed.Add(amount).GreaterThan(decimal.NewFromInt(*payConfig.MonthlyTransferLimit)) {
+		return errors.New(common.TransferMonthlyLimitExceeded)
+	}
+
+	return nil
+}
+
+// GetTransferQuota 获取用户当日、当月已使用的转账额度及剩余额度（-1 表示无限额）
+func GetTransferQuota(db *gorm.DB, userID uint64, payConfig *model.UserPayConfig) (*TransferQuota, error) {
+	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	quota := &TransferQuota{
+		DailyLimit:     payConfig.DailyTransferLimit,
+		MonthlyLimit:   payConfig.MonthlyTransferLimit,
+		RemainingQuota: decimal.NewFromInt(-1),
+	}
+
+	var err error
+	if quota.DailyUsed, err = getTransferUsedAmount(db, userID, todayStart, todayStart.Add(24*time.Hour)); err != nil {
+		return nil, err
+	}
+	if quota.MonthlyUsed, err = getTransferUsedAmount(db, userID, monthStart, monthEnd); err != nil {
+		return nil, err
+	}
+
+	// 剩余额度取日、月剩余额度中的较小值
+	var remaining *decimal.Decimal
+	applyLimit := func(limit *int64, used decimal.Decimal) {
+		if limit == nil || *limit <= 0 {
+			return
+		}
+		r := decimal.Max(decimal.NewFromInt(*limit).Sub(used), decimal.Zero)
+		if remaining == nil || r.LessThan(*remaining) {
+			remaining = &r
+		}
+	}
+	applyLimit(payConfig.DailyTransferLimit, quota.DailyUsed)
+	applyLimit(payConfig.MonthlyTransferLimit, quota.MonthlyUsed)
+	if remaining != nil {
+		quota.RemainingQuota = *remaining
+	}
+
+	return quota, nil
+}
+
+// getTransferUsedAmount 统计时间范围内用户发起的转账金额
+// 包含个人转账订单（不含商户批量付款与红包领取产生的订单）以及发出红包的实际支出
+func getTransferUsedAmount(db *gorm.DB, userID uint64, start, end time.Time) (decimal.Decimal, error) {
+	var orderAmount decimal.Decimal
+	if err := db.Model(&model.Order{}).
+		Where("payer_user_id = ? AND status = ? AND type = ? AND client_id = '' AND trade_time >= ? AND trade_time < ?",
+			userID,
+			model.OrderStatusSuccess,
+			model.OrderTypeTransfer,
+			start,
+			end).
+		Where("NOT EXISTS (SELECT 1 FROM red_packet_claims WHERE red_packet_claims.order_id = orders.id)").
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&orderAmount).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	var redPacketAmount decimal.Decimal
+	if err := db.Model(&model.RedPacket{}).
+		Where("sender_user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Select("COALESCE(SUM(total_amount - refunded_amount), 0)").
+		Scan(&redPacketAmount).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	return orderAmount.Add(redPacketAmount), nil
+}
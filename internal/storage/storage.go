@@ -0,0 +1,55 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"github.com/linux-do/credit/internal/config"
+)
+
+const (
+	DriverLocal = "local"
+)
+
+// BlobStore 二进制文件存储，key 由调用方生成，仅允许使用 / 分隔的相对路径
+type BlobStore interface {
+	// Put 写入文件，key 已存在时覆盖
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get 读取文件，调用方负责关闭返回的 ReadCloser
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除文件，文件不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// Store 全局文件存储
+var Store BlobStore
+
+func init() {
+	switch driver := config.Config.Storage.Driver; driver {
+	case "", DriverLocal:
+		store, err := NewLocalStore(config.Config.Storage.LocalPath)
+		if err != nil {
+			log.Fatalf("[Storage] init local store failed: %v\n", err)
+		}
+		Store = store
+	default:
+		log.Fatalf("[Storage] unsupported driver: %s\n", driver)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/linux-do/credit/internal/db"
+	"github.com/linux-do/credit/internal/logger"
+	"github.com/linux-do/credit/internal/util"
+)
+
+const (
+	OrderChannelFormat = "stream:order:%d"
+	UserChannelFormat  = "stream:user:%d"
+	HeartbeatInterval  = 15 * time.Second
+)
+
+const (
+	EventOrderStatus = "order_status"
+	EventBalance     = "balance"
+)
+
+// shutdownCtx 服务关闭时取消所有推送连接，避免长连接阻塞优雅关闭
+var shutdownCtx, shutdown = context.WithCancel(context.Background())
+
+// Shutdown 关闭所有推送连接，注册到 http.Server.RegisterOnShutdown
+func Shutdown() {
+	shutdown()
+}
+
+// Snapshot 读取当前状态，返回推送的事件名、数据以及是否为终态（终态推送后关闭连接）
+type Snapshot func(ctx context.Context) (event string, data interface{}, done bool, err error)
+
+// PublishOrderStatus 通知订阅者订单状态已变化，需在事务提交后调用
+func PublishOrderStatus(ctx context.Context, orderIDs ...uint64) {
+	for _, orderID := range orderIDs {
+		publish(ctx, fmt.Sprintf(OrderChannelFormat, orderID), EventOrderStatus)
+	}
+}
+
+// PublishBalance 通知订阅者用户余额已变化，需在事务提交后调用
+func PublishBalance(ctx context.Context, userIDs ...uint64) {
+	for _, userID := range userIDs {
+		if userID == 0 {
+			continue
+		}
+		publish(ctx, fmt.Sprintf(UserChannelFormat, userID), EventBalance)
+	}
+}
+
+// publish 推送失败只影响实时性，客户端仍可通过查询接口获取最新状态，因此仅记录日志
+func publish(ctx context.Context, channel, event string) {
+	if err := db.Redis.Publish(ctx, db.PrefixedKey(channel), event).Err(); err != nil {
+		logger.ErrorF(ctx, "推送实时事件失败: channel=%s, error=%v", channel, err)
+	}
+}
+
+// Serve 以 Server-Sent Events 推送 channel 上的状态变化
+// 先订阅再读取快照，收到通知后重新读取快照推送，消息只作为变化信号，跨副本通过 Redis pub/sub 分发
+func Serve(c *gin.Context, channel string, snapshot Snapshot) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-shutdownCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	pubSub := db.Redis.Subscribe(ctx, db.PrefixedKey(channel))
+	defer pubSub.Close()
+	if _, err := pubSub.Receive(ctx); err != nil {
+		c.JSON(http.StatusInternalServerError, util.Err(err.Error()))
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// send 推送一次快照，返回是否应结束推送
+	send := func() bool {
+		event, data, done, err := snapshot(ctx)
+		if err != nil {
+			logger.ErrorF(ctx, "读取实时推送快照失败: channel=%s, error=%v", channel, err)
+			c.SSEvent("error", util.Err(err.Error()))
+			c.Writer.Flush()
+			return true
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+		return done
+	}
+
+	if send() {
+		return
+	}
+
+	messages := pubSub.Channel()
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok || send() {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
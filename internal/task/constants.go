@@ -21,12 +21,22 @@ const (
 	UpdateSingleUserGamificationScoreTask = "user:gamification:update_single_score_task"
 	AutoRefundExpiredDisputesTask         = "dispute:auto_refund_expired"
 	AutoRefundSingleDisputeTask           = "dispute:auto_refund_single"
+	RemindPendingDisputesTask             = "dispute:remind_pending"
 	MerchantPaymentNotifyTask             = "payment:merchant_notify"
 	SyncOrdersToClickHouseTask            = "order:sync_to_clickhouse"
+	ExpirePaymentRequestTask              = "payment_request:expire"
+	DispatchScheduledTransfersTask        = "scheduled_transfer:dispatch"
+	ExecuteScheduledTransferTask          = "scheduled_transfer:execute"
+	ProcessMerchantPayoutTask             = "merchant:payout:process"
+	RefundExpiredRedPacketsTask           = "red_packet:refund_expired"
+	DetectWashTradingTask                 = "risk:detect_wash_trading"
+	ExpireChangeRequestsTask              = "change_request:expire"
+	SendEmailTask                         = "email:send"
 )
 
 const (
 	QueueWhitelistOnly = "whitelist_only"
 	QueueWebhook       = "webhook"
 	QueueDefault       = "default"
+	QueueEmail         = "email"
 )
@@ -73,6 +73,16 @@ func StartScheduler() error {
 			return
 		}
 
+		// 争议处理提醒任务
+		if _, err = scheduler.Register(
+			config.Config.Scheduler.RemindPendingDisputesTaskCron,
+			asynq.NewTask(task.RemindPendingDisputesTask, nil),
+			asynq.MaxRetry(3),
+			asynq.Unique(25*time.Minute),
+		); err != nil {
+			return
+		}
+
 		// 订单同步任务
 		if _, err = scheduler.Register(
 			config.Config.Scheduler.SyncOrdersToClickHouseTaskCron,
@@ -83,6 +93,47 @@ func StartScheduler() error {
 			return
 		}
 
+		// 定时转账分发任务
+		if _, err = scheduler.Register(
+			config.Config.Scheduler.DispatchScheduledTransfersTaskCron,
+			asynq.NewTask(task.DispatchScheduledTransfersTask, nil),
+			asynq.MaxRetry(3),
+			asynq.Unique(50*time.Second),
+		); err != nil {
+			return
+		}
+
+		// 过期红包退款任务
+		if _, err = scheduler.Register(
+			config.Config.Scheduler.RefundExpiredRedPacketsTaskCron,
+			asynq.NewTask(task.RefundExpiredRedPacketsTask, nil),
+			asynq.MaxRetry(3),
+			asynq.Unique(4*time.Minute),
+		); err != nil {
+			return
+		}
+
+		// 刷单检测任务
+		if _, err = scheduler.Register(
+			config.Config.Scheduler.DetectWashTradingTaskCron,
+			asynq.NewTask(task.DetectWashTradingTask, nil),
+			asynq.MaxRetry(3),
+			asynq.Timeout(30*time.Minute),
+			asynq.Unique(23*time.Hour),
+		); err != nil {
+			return
+		}
+
+		// 过期变更申请任务
+		if _, err = scheduler.Register(
+			config.Config.Scheduler.ExpireChangeRequestsTaskCron,
+			asynq.NewTask(task.ExpireChangeRequestsTask, nil),
+			asynq.MaxRetry(3),
+			asynq.Unique(9*time.Minute),
+		); err != nil {
+			return
+		}
+
 		// 启动调度器
 		err = scheduler.Run()
 	})
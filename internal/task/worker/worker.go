@@ -23,9 +23,16 @@ import (
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/linux-do/credit/internal/apps/admin/change_request"
+	"github.com/linux-do/credit/internal/apps/admin/risk_control"
 	"github.com/linux-do/credit/internal/apps/dispute"
+	"github.com/linux-do/credit/internal/apps/email"
+	"github.com/linux-do/credit/internal/apps/merchant/payout"
 	"github.com/linux-do/credit/internal/apps/order"
 	"github.com/linux-do/credit/internal/apps/payment"
+	"github.com/linux-do/credit/internal/apps/payment_request"
+	"github.com/linux-do/credit/internal/apps/red_packet"
+	"github.com/linux-do/credit/internal/apps/scheduled_transfer"
 	"github.com/linux-do/credit/internal/apps/user"
 	"github.com/linux-do/credit/internal/config"
 	"github.com/linux-do/credit/internal/task"
@@ -75,8 +82,17 @@ func StartWorker() error {
 	mux.HandleFunc(task.UpdateSingleUserGamificationScoreTask, user.HandleUpdateSingleUserGamificationScore)
 	mux.HandleFunc(task.AutoRefundExpiredDisputesTask, dispute.HandleAutoRefundExpiredDisputes)
 	mux.HandleFunc(task.AutoRefundSingleDisputeTask, dispute.HandleAutoRefundSingleDispute)
+	mux.HandleFunc(task.RemindPendingDisputesTask, dispute.HandleRemindPendingDisputes)
 	mux.HandleFunc(task.MerchantPaymentNotifyTask, payment.HandleMerchantPaymentNotify)
 	mux.HandleFunc(task.SyncOrdersToClickHouseTask, order.HandleSyncOrdersToClickHouse)
+	mux.HandleFunc(task.ExpirePaymentRequestTask, payment_request.HandleExpirePaymentRequest)
+	mux.HandleFunc(task.DispatchScheduledTransfersTask, scheduled_transfer.HandleDispatchScheduledTransfers)
+	mux.HandleFunc(task.ExecuteScheduledTransferTask, scheduled_transfer.HandleExecuteScheduledTransfer)
+	mux.HandleFunc(task.ProcessMerchantPayoutTask, payout.HandleProcessMerchantPayout)
+	mux.HandleFunc(task.RefundExpiredRedPacketsTask, red_packet.HandleRefundExpiredRedPackets)
+	mux.HandleFunc(task.DetectWashTradingTask, risk_control.HandleDetectWashTrading)
+	mux.HandleFunc(task.ExpireChangeRequestsTask, change_request.HandleExpireChangeRequests)
+	mux.HandleFunc(task.SendEmailTask, email.HandleSendEmail)
 	// 启动服务器
 	return asynqServer.Run(mux)
 }
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id 参数，调整后旧哈希会在下次验证成功时自动重算
+const (
+	argon2Memory  uint32 = 64 * 1024
+	argon2Time    uint32 = 3
+	argon2Threads uint8  = 2
+	argon2SaltLen        = 16
+	argon2KeyLen  uint32 = 32
+	argon2Prefix         = "$argon2id$"
+)
+
+// HashSecret 使用 argon2id 计算带随机盐的不可逆哈希
+// pepper 为服务端密钥，为空时不参与计算
+// return: PHC 格式字符串 $argon2id$v=19$m=...,t=...,p=...$salt$hash
+func HashSecret(secret string, pepper string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey(pepperSecret(secret, pepper), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix, argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// VerifySecret 校验明文与 HashSecret 生成的哈希是否匹配
+// needsRehash 表示哈希参数已过时，调用方应在校验成功后重新计算
+func VerifySecret(encoded string, secret string, pepper string) (ok bool, needsRehash bool, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || !IsSecretHash(encoded) {
+		return false, false, errors.New("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, false, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return false, false, errors.New("unsupported argon2id version")
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false, fmt.Errorf("invalid argon2id params: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, fmt.Errorf("invalid argon2id hash: %w", err)
+	}
+
+	key := argon2.IDKey(pepperSecret(secret, pepper), salt, time, memory, threads, uint32(len(expected)))
+	if subtle.ConstantTimeCompare(key, expected) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash = memory != argon2Memory || time != argon2Time || threads != argon2Threads ||
+		len(salt) != argon2SaltLen || uint32(len(expected)) != argon2KeyLen
+	return true, needsRehash, nil
+}
+
+// IsSecretHash 判断存储值是否为 HashSecret 生成的哈希
+func IsSecretHash(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2Prefix)
+}
+
+// pepperSecret 使用服务端 pepper 对明文做 HMAC，数据库泄露时无法离线爆破
+func pepperSecret(secret string, pepper string) []byte {
+	if pepper == "" {
+		return []byte(secret)
+	}
+	mac := hmac.New(sha256.New, []byte(pepper))
+	mac.Write([]byte(secret))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 linux.do
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	SealedSecretPrefix = "$sb1$" // 密文格式版本，便于识别历史密文并迁移
+	sealSaltLen        = 16
+	sealKeyLen         = 32
+)
+
+// SealSecret 加密需要还原明文的敏感数据（如商户签名密钥、TOTP 种子）
+// 加密密钥由服务端主密钥与每条密文独立的随机盐经 HKDF-SHA256 派生，主密钥只存在于配置中，
+// 仅获取数据库内容无法还原明文；purpose 区分用途，不同用途的密文不能互相解密
+func SealSecret(masterKey string, purpose string, plaintext string) (string, error) {
+	salt := make([]byte, sealSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := sealCipher(masterKey, purpose, salt)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := append(salt, nonce...)
+	sealed = gcm.Seal(sealed, nonce, []byte(plaintext), []byte(purpose))
+	return SealedSecretPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSecret 解密 SealSecret 生成的密文
+func OpenSecret(masterKey string, purpose string, sealed string) (string, error) {
+	if !IsSealedSecret(sealed) {
+		return "", errors.New("unsupported sealed secret format")
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, SealedSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode sealed secret: %w", err)
+	}
+	if len(data) < sealSaltLen {
+		return "", errors.New("sealed secret too short")
+	}
+
+	gcm, err := sealCipher(masterKey, purpose, data[:sealSaltLen])
+	if err != nil {
+		return "", err
+	}
+
+	data = data[sealSaltLen:]
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("sealed secret too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(purpose))
+	if err != nil {
+		return "", fmt.Errorf("failed to open sealed secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// IsSealedSecret 判断密文是否为 SealSecret 生成的格式
+func IsSealedSecret(sealed string) bool {
+	return strings.HasPrefix(sealed, SealedSecretPrefix)
+}
+
+// sealCipher 由主密钥与盐派生 AES-256-GCM
+func sealCipher(masterKey string, purpose string, salt []byte) (cipher.AEAD, error) {
+	if masterKey == "" {
+		return nil, errors.New("secret encryption key is not configured")
+	}
+
+	key, err := hkdf.Key(sha256.New, []byte(masterKey), salt, purpose, sealKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}